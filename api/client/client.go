@@ -2,8 +2,10 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"os/user"
 	"time"
 
 	"github.com/NetSys/quilt/api"
@@ -36,8 +38,31 @@ type Client interface {
 	// QueryEtcd retrieves the etcd information tracked by the Quilt daemon.
 	QueryEtcd() ([]db.Etcd, error)
 
+	// QueryViolations retrieves the invariants that don't hold for the running
+	// cluster.
+	QueryViolations() ([]db.Violation, error)
+
+	// QueryConnections retrieves the connections tracked by the Quilt daemon.
+	QueryConnections() ([]db.Connection, error)
+
+	// QueryPlacements retrieves the placement rules tracked by the Quilt daemon.
+	QueryPlacements() ([]db.Placement, error)
+
+	// QueryACLs retrieves the ACLs tracked by the Quilt daemon.
+	QueryACLs() ([]db.ACL, error)
+
+	// QueryRevisions retrieves the history of specs applied by the Quilt daemon.
+	QueryRevisions() ([]db.Revision, error)
+
 	// RunStitch makes a request to the Quilt daemon to execute the given stitch.
-	RunStitch(stitch string) error
+	// Unless `force` is set, the daemon refuses stitches that would terminate
+	// too many machines.
+	RunStitch(stitch string, force bool) error
+
+	// Rollback makes a request to the Quilt daemon to re-apply the spec of the
+	// given revision.  Like RunStitch, it's refused if it would terminate too
+	// many machines, unless `force` is set.
+	Rollback(revision int, force bool) error
 }
 
 type clientImpl struct {
@@ -95,6 +120,36 @@ func query(pbClient pb.APIClient, table db.TableType) (interface{}, error) {
 			return nil, err
 		}
 		return etcds, nil
+	case db.ViolationTable:
+		var violations []db.Violation
+		if err := json.Unmarshal(replyBytes, &violations); err != nil {
+			return nil, err
+		}
+		return violations, nil
+	case db.ConnectionTable:
+		var connections []db.Connection
+		if err := json.Unmarshal(replyBytes, &connections); err != nil {
+			return nil, err
+		}
+		return connections, nil
+	case db.PlacementTable:
+		var placements []db.Placement
+		if err := json.Unmarshal(replyBytes, &placements); err != nil {
+			return nil, err
+		}
+		return placements, nil
+	case db.ACLTable:
+		var acls []db.ACL
+		if err := json.Unmarshal(replyBytes, &acls); err != nil {
+			return nil, err
+		}
+		return acls, nil
+	case db.RevisionTable:
+		var revisions []db.Revision
+		if err := json.Unmarshal(replyBytes, &revisions); err != nil {
+			return nil, err
+		}
+		return revisions, nil
 	default:
 		panic(fmt.Sprintf("unsupported table type: %s", table))
 	}
@@ -135,9 +190,95 @@ func (c clientImpl) QueryEtcd() ([]db.Etcd, error) {
 	return rows.([]db.Etcd), nil
 }
 
+// QueryViolations retrieves the invariants that don't hold for the running cluster.
+func (c clientImpl) QueryViolations() ([]db.Violation, error) {
+	rows, err := query(c.pbClient, db.ViolationTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows.([]db.Violation), nil
+}
+
+// QueryConnections retrieves the connections tracked by the Quilt daemon.
+func (c clientImpl) QueryConnections() ([]db.Connection, error) {
+	rows, err := query(c.pbClient, db.ConnectionTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows.([]db.Connection), nil
+}
+
+// QueryPlacements retrieves the placement rules tracked by the Quilt daemon.
+func (c clientImpl) QueryPlacements() ([]db.Placement, error) {
+	rows, err := query(c.pbClient, db.PlacementTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows.([]db.Placement), nil
+}
+
+// QueryACLs retrieves the ACLs tracked by the Quilt daemon.
+func (c clientImpl) QueryACLs() ([]db.ACL, error) {
+	rows, err := query(c.pbClient, db.ACLTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows.([]db.ACL), nil
+}
+
+// QueryRevisions retrieves the history of specs applied by the Quilt daemon.
+func (c clientImpl) QueryRevisions() ([]db.Revision, error) {
+	rows, err := query(c.pbClient, db.RevisionTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows.([]db.Revision), nil
+}
+
 // RunStitch makes a request to the Quilt daemon to execute the given stitch.
-func (c clientImpl) RunStitch(stitch string) error {
+func (c clientImpl) RunStitch(stitch string, force bool) error {
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	reply, err := c.pbClient.Run(ctx,
+		&pb.RunRequest{Stitch: stitch, Force: force, User: currentUser()})
+	if err != nil {
+		return err
+	}
+
+	if reply.Refusal != "" {
+		return errors.New(reply.Refusal)
+	}
+	return nil
+}
+
+// Rollback makes a request to the Quilt daemon to re-apply the spec of the given
+// revision.
+func (c clientImpl) Rollback(revision int, force bool) error {
 	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
-	_, err := c.pbClient.Run(ctx, &pb.RunRequest{Stitch: stitch})
-	return err
+	reply, err := c.pbClient.Rollback(ctx, &pb.RollbackRequest{
+		Revision: int32(revision),
+		User:     currentUser(),
+		Force:    force,
+	})
+	if err != nil {
+		return err
+	}
+
+	if reply.Refusal != "" {
+		return errors.New(reply.Refusal)
+	}
+	return nil
+}
+
+// currentUser returns the name of the user running the client, so that the daemon
+// can record who applied each spec.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
 }
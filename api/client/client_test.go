@@ -29,6 +29,12 @@ func (c mockAPIClient) Run(ctx context.Context, in *pb.RunRequest,
 	return &pb.RunReply{}, nil
 }
 
+func (c mockAPIClient) Rollback(ctx context.Context, in *pb.RollbackRequest,
+	opts ...grpc.CallOption) (*pb.RollbackReply, error) {
+
+	return &pb.RollbackReply{}, nil
+}
+
 func TestUnmarshalMachine(t *testing.T) {
 	t.Parallel()
 
@@ -13,6 +13,8 @@ It has these top-level messages:
 	QueryReply
 	RunRequest
 	RunReply
+	RollbackRequest
+	RollbackReply
 */
 package pb
 
@@ -56,6 +58,8 @@ func (*QueryReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{
 
 type RunRequest struct {
 	Stitch string `protobuf:"bytes,1,opt,name=Stitch,json=stitch" json:"Stitch,omitempty"`
+	Force  bool   `protobuf:"varint,2,opt,name=Force,json=force" json:"Force,omitempty"`
+	User   string `protobuf:"bytes,3,opt,name=User,json=user" json:"User,omitempty"`
 }
 
 func (m *RunRequest) Reset()                    { *m = RunRequest{} }
@@ -64,6 +68,7 @@ func (*RunRequest) ProtoMessage()               {}
 func (*RunRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 type RunReply struct {
+	Refusal string `protobuf:"bytes,1,opt,name=Refusal,json=refusal" json:"Refusal,omitempty"`
 }
 
 func (m *RunReply) Reset()                    { *m = RunReply{} }
@@ -71,11 +76,33 @@ func (m *RunReply) String() string            { return proto.CompactTextString(m
 func (*RunReply) ProtoMessage()               {}
 func (*RunReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
+type RollbackRequest struct {
+	Revision int32  `protobuf:"varint,1,opt,name=Revision,json=revision" json:"Revision,omitempty"`
+	User     string `protobuf:"bytes,2,opt,name=User,json=user" json:"User,omitempty"`
+	Force    bool   `protobuf:"varint,3,opt,name=Force,json=force" json:"Force,omitempty"`
+}
+
+func (m *RollbackRequest) Reset()                    { *m = RollbackRequest{} }
+func (m *RollbackRequest) String() string            { return proto.CompactTextString(m) }
+func (*RollbackRequest) ProtoMessage()               {}
+func (*RollbackRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type RollbackReply struct {
+	Refusal string `protobuf:"bytes,1,opt,name=Refusal,json=refusal" json:"Refusal,omitempty"`
+}
+
+func (m *RollbackReply) Reset()                    { *m = RollbackReply{} }
+func (m *RollbackReply) String() string            { return proto.CompactTextString(m) }
+func (*RollbackReply) ProtoMessage()               {}
+func (*RollbackReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
 func init() {
 	proto.RegisterType((*DBQuery)(nil), "DBQuery")
 	proto.RegisterType((*QueryReply)(nil), "QueryReply")
 	proto.RegisterType((*RunRequest)(nil), "RunRequest")
 	proto.RegisterType((*RunReply)(nil), "RunReply")
+	proto.RegisterType((*RollbackRequest)(nil), "RollbackRequest")
+	proto.RegisterType((*RollbackReply)(nil), "RollbackReply")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -91,6 +118,7 @@ const _ = grpc.SupportPackageIsVersion3
 type APIClient interface {
 	Query(ctx context.Context, in *DBQuery, opts ...grpc.CallOption) (*QueryReply, error)
 	Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*RunReply, error)
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackReply, error)
 }
 
 type aPIClient struct {
@@ -119,11 +147,21 @@ func (c *aPIClient) Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOp
 	return out, nil
 }
 
+func (c *aPIClient) Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackReply, error) {
+	out := new(RollbackReply)
+	err := grpc.Invoke(ctx, "/API/Rollback", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for API service
 
 type APIServer interface {
 	Query(context.Context, *DBQuery) (*QueryReply, error)
 	Run(context.Context, *RunRequest) (*RunReply, error)
+	Rollback(context.Context, *RollbackRequest) (*RollbackReply, error)
 }
 
 func RegisterAPIServer(s *grpc.Server, srv APIServer) {
@@ -166,6 +204,24 @@ func _API_Run_Handler(srv interface{}, ctx context.Context, dec func(interface{}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/Rollback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Rollback(ctx, req.(*RollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _API_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "API",
 	HandlerType: (*APIServer)(nil),
@@ -178,6 +234,10 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Run",
 			Handler:    _API_Run_Handler,
 		},
+		{
+			MethodName: "Rollback",
+			Handler:    _API_Rollback_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: fileDescriptor0,
@@ -186,17 +246,24 @@ var _API_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pb/pb.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 185 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xe2, 0x2e, 0x48, 0xd2, 0x2f,
-	0x48, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x57, 0x92, 0xe7, 0x62, 0x77, 0x71, 0x0a, 0x2c, 0x4d,
-	0x2d, 0xaa, 0x14, 0x12, 0xe1, 0x62, 0x0d, 0x49, 0x4c, 0xca, 0x49, 0x95, 0x60, 0x54, 0x60, 0xd4,
-	0xe0, 0x0c, 0x62, 0x2d, 0x01, 0x71, 0x94, 0x8c, 0xb8, 0xb8, 0xc0, 0xd2, 0x41, 0xa9, 0x05, 0x39,
-	0x95, 0x42, 0x2a, 0x5c, 0xbc, 0x60, 0x35, 0xce, 0xf9, 0x79, 0x25, 0xa9, 0x79, 0x25, 0xc5, 0x50,
-	0xb5, 0xbc, 0x25, 0xc8, 0x82, 0x4a, 0x2a, 0x5c, 0x5c, 0x41, 0xa5, 0x79, 0x41, 0xa9, 0x85, 0xa5,
-	0xa9, 0xc5, 0x25, 0x42, 0x62, 0x5c, 0x6c, 0xc1, 0x25, 0x99, 0x25, 0xc9, 0x19, 0x50, 0xc5, 0x6c,
-	0xc5, 0x60, 0x9e, 0x12, 0x17, 0x17, 0x07, 0x58, 0x15, 0xd0, 0x5c, 0x23, 0x0f, 0x2e, 0x66, 0xc7,
-	0x00, 0x4f, 0x21, 0x05, 0x2e, 0x56, 0x88, 0x5b, 0x38, 0xf4, 0xa0, 0xae, 0x92, 0xe2, 0xd6, 0x43,
-	0x58, 0xaf, 0xc4, 0x20, 0x24, 0xcf, 0xc5, 0x0c, 0xd4, 0x24, 0xc4, 0xad, 0x87, 0xb0, 0x40, 0x8a,
-	0x53, 0x0f, 0x66, 0x8e, 0x12, 0x43, 0x12, 0x1b, 0xd8, 0x5f, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x7c, 0xaf, 0xc0, 0xc2, 0xe6, 0x00, 0x00, 0x00,
+	// 290 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0x4d, 0x4f, 0xb3, 0x40,
+	0x14, 0x85, 0xdb, 0x52, 0x60, 0x7a, 0x09, 0xef, 0x6b, 0x26, 0xc6, 0x10, 0x36, 0x25, 0x93, 0x2e,
+	0xea, 0x66, 0x4c, 0xea, 0x2f, 0xf0, 0x23, 0x26, 0x6e, 0x8c, 0x8e, 0x1a, 0xd7, 0x40, 0x6e, 0x23,
+	0x91, 0x30, 0x38, 0x1f, 0xc6, 0xfe, 0x7b, 0xc3, 0x14, 0xa4, 0xba, 0x70, 0x79, 0xe0, 0xe4, 0x9c,
+	0xe7, 0x9e, 0x81, 0xa8, 0x2d, 0xce, 0xda, 0x82, 0xb7, 0x4a, 0x1a, 0xc9, 0x96, 0x10, 0x5e, 0x5f,
+	0x3e, 0x58, 0x54, 0x3b, 0x7a, 0x0c, 0xfe, 0x53, 0x5e, 0xd4, 0x98, 0x4c, 0xb3, 0xe9, 0x7a, 0x21,
+	0x7c, 0xd3, 0x09, 0xb6, 0x01, 0x70, 0xbf, 0x05, 0xb6, 0xf5, 0x8e, 0xae, 0x20, 0x76, 0x9e, 0x2b,
+	0xd9, 0x18, 0x6c, 0x8c, 0xee, 0xbd, 0xb1, 0x39, 0xfc, 0xc8, 0xee, 0x00, 0x84, 0x6d, 0x04, 0xbe,
+	0x5b, 0xd4, 0x86, 0x9e, 0x40, 0xf0, 0x68, 0x2a, 0x53, 0xbe, 0xf6, 0xe6, 0x40, 0x3b, 0xd5, 0xf5,
+	0xdd, 0x48, 0x55, 0x62, 0x32, 0xcb, 0xa6, 0x6b, 0x22, 0xfc, 0x6d, 0x27, 0x28, 0x85, 0xf9, 0xb3,
+	0x46, 0x95, 0x78, 0xce, 0x3b, 0xb7, 0x1a, 0x15, 0x5b, 0x01, 0x71, 0x79, 0x1d, 0x41, 0x02, 0xa1,
+	0xc0, 0xad, 0xd5, 0x79, 0xdd, 0xc7, 0x85, 0x6a, 0x2f, 0xd9, 0x0b, 0xfc, 0x17, 0xb2, 0xae, 0x8b,
+	0xbc, 0x7c, 0x1b, 0xaa, 0x53, 0x20, 0x02, 0x3f, 0x2a, 0x5d, 0xc9, 0xc6, 0xb9, 0x7d, 0x41, 0x54,
+	0xaf, 0xbf, 0x8b, 0x66, 0x63, 0xd1, 0x88, 0xe4, 0x1d, 0x20, 0xb1, 0x53, 0x88, 0xc7, 0xe0, 0x3f,
+	0x19, 0x36, 0x9f, 0xe0, 0x5d, 0xdc, 0xdf, 0xd2, 0x0c, 0xfc, 0xfd, 0xa6, 0x84, 0xf7, 0xeb, 0xa6,
+	0x11, 0x1f, 0x67, 0x64, 0x13, 0xba, 0x04, 0x4f, 0xd8, 0x86, 0x46, 0x7c, 0x1c, 0x2a, 0x5d, 0xf0,
+	0xe1, 0x4a, 0x36, 0xa1, 0x1c, 0xc8, 0x50, 0x4a, 0x8f, 0xf8, 0xaf, 0xc3, 0xd2, 0x7f, 0xfc, 0x07,
+	0x11, 0x9b, 0x14, 0x81, 0x7b, 0xcf, 0xf3, 0xaf, 0x01, 0x00, 0x6b, 0x70, 0x73, 0x59, 0xde, 0x01,
+	0x00, 0x00,
 }
@@ -24,17 +24,23 @@ import (
 
 type server struct {
 	dbConn db.Conn
+
+	// The largest fraction of the machines a stitch may terminate without
+	// being forced.
+	maxTerminateFraction float64
 }
 
-// Run accepts incoming `quiltctl` connections and responds to them.
-func Run(conn db.Conn, listenAddr string) error {
+// Run accepts incoming `quiltctl` connections and responds to them.  Stitches that
+// would terminate more than `maxTerminateFraction` of the machines are refused
+// unless they're forced.
+func Run(conn db.Conn, listenAddr string, maxTerminateFraction float64) error {
 	proto, addr, err := api.ParseListenAddress(listenAddr)
 	if err != nil {
 		return err
 	}
 
 	var sock net.Listener
-	apiServer := server{conn}
+	apiServer := server{conn, maxTerminateFraction}
 	for {
 		sock, err = net.Listen(proto, addr)
 
@@ -73,6 +79,16 @@ func (s server) Query(cts context.Context, query *pb.DBQuery) (*pb.QueryReply, e
 			rows = view.SelectFromContainer(nil)
 		case db.EtcdTable:
 			rows = view.SelectFromEtcd(nil)
+		case db.ViolationTable:
+			rows = view.SelectFromViolation(nil)
+		case db.ConnectionTable:
+			rows = view.SelectFromConnection(nil)
+		case db.PlacementTable:
+			rows = view.SelectFromPlacement(nil)
+		case db.ACLTable:
+			rows = view.SelectFromACL(nil)
+		case db.RevisionTable:
+			rows = view.SelectFromRevision(nil)
 		default:
 			return fmt.Errorf("unrecognized table: %s", query.Table)
 		}
@@ -101,10 +117,49 @@ func (s server) Run(cts context.Context, runReq *pb.RunRequest) (*pb.RunReply, e
 			"machines", ip.MaxMinionCount)
 	}
 
-	err = engine.UpdatePolicy(s.dbConn, stitch)
+	refusal, err := engine.UpdatePolicyProtected(s.dbConn, stitch,
+		engine.Protection{
+			MaxTerminateFraction: s.maxTerminateFraction,
+			Force:                runReq.Force,
+		}, runReq.User)
 	if err != nil {
 		return &pb.RunReply{}, err
 	}
 
-	return &pb.RunReply{}, nil
+	return &pb.RunReply{Refusal: refusal}, nil
+}
+
+func (s server) Rollback(cts context.Context, rollbackReq *pb.RollbackRequest) (
+	*pb.RollbackReply, error) {
+
+	var spec string
+	err := s.dbConn.Transact(func(view db.Database) error {
+		revs := view.SelectFromRevision(func(rev db.Revision) bool {
+			return rev.Number == int(rollbackReq.Revision)
+		})
+		if len(revs) == 0 {
+			return fmt.Errorf("no revision %d", rollbackReq.Revision)
+		}
+		spec = revs[0].Spec
+		return nil
+	})
+	if err != nil {
+		return &pb.RollbackReply{}, err
+	}
+
+	stitch, err := stitch.New(spec, stitch.DefaultImportGetter)
+	if err != nil {
+		return &pb.RollbackReply{}, err
+	}
+
+	refusal, err := engine.UpdatePolicyProtected(s.dbConn, stitch,
+		engine.Protection{
+			MaxTerminateFraction: s.maxTerminateFraction,
+			Force:                rollbackReq.Force,
+		}, rollbackReq.User)
+	if err != nil {
+		return &pb.RollbackReply{}, err
+	}
+
+	return &pb.RollbackReply{Refusal: refusal}, nil
 }
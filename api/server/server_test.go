@@ -39,11 +39,16 @@ func TestMachineResponse(t *testing.T) {
 		return nil
 	})
 
-	exp := `[{"ID":1,"Role":"Master","Provider":"Amazon","Region":"",` +
-		`"Size":"size","DiskSize":0,"SSHKeys":null,"CloudID":"",` +
-		`"PublicIP":"8.8.8.8","PrivateIP":"9.9.9.9","Connected":false}]`
+	exp := `[{"ID":1,"Namespace":"","Role":"Master","Provider":"Amazon",` +
+		`"Region":"","Size":"size","DiskSize":0,"Market":"","MaxPrice":0,` +
+		`"Image":"","SSHKeys":null,"BootScriptExtra":"","Host":"",` +
+		`"Protected":false,` +
+		`"CloudID":"","PublicIP":"8.8.8.8",` +
+		`"PrivateIP":"9.9.9.9","Bid":0,"Price":0,"Draining":false,` +
+		`"BootError":"","BootFailures":0,"RetryAt":"0001-01-01T00:00:00Z",` +
+		`"Connected":false}]`
 
-	checkQuery(t, server{conn}, db.MachineTable, exp)
+	checkQuery(t, server{dbConn: conn}, db.MachineTable, exp)
 }
 
 func TestContainerResponse(t *testing.T) {
@@ -63,9 +68,59 @@ func TestContainerResponse(t *testing.T) {
 
 	exp := `[{"ID":1,"Pid":0,"IP":"","Mac":"","Minion":"",` +
 		`"DockerID":"docker-id","StitchID":0,"Image":"image",` +
-		`"Command":["cmd","arg"],"Labels":["labelA","labelB"],"Env":null}]`
+		`"Command":["cmd","arg"],"Labels":["labelA","labelB"],"Env":null,` +
+		`"Dependencies":null,"Job":{"Parallelism":0,"BackoffLimit":0,` +
+		`"Backoff":0,"State":"","ExitCode":0,"Retries":0,` +
+		`"Finished":"0001-01-01T00:00:00Z"}}]`
 
-	checkQuery(t, server{conn}, db.ContainerTable, exp)
+	checkQuery(t, server{dbConn: conn}, db.ContainerTable, exp)
+}
+
+func TestViolationResponse(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	conn.Transact(func(view db.Database) error {
+		v := view.InsertViolation()
+		v.Invariant = `reach true "a" "b"`
+		v.Counterexample = "container 1 (a) can't reach container 2 (b)"
+		view.Commit(v)
+
+		return nil
+	})
+
+	exp := `[{"ID":1,"Invariant":"reach true \"a\" \"b\"",` +
+		`"Counterexample":"container 1 (a) can't reach container 2 (b)"}]`
+
+	checkQuery(t, server{dbConn: conn}, db.ViolationTable, exp)
+}
+
+func TestConnectionResponse(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	conn.Transact(func(view db.Database) error {
+		c := view.InsertConnection()
+		c.From = "a"
+		c.To = "b"
+		c.MinPort = 80
+		c.MaxPort = 80
+		view.Commit(c)
+
+		acl := view.InsertACL()
+		acl.Namespace = "ns"
+		acl.Admin = []string{"1.2.3.4/32"}
+		view.Commit(acl)
+
+		return nil
+	})
+
+	exp := `[{"ID":1,"From":"a","To":"b","MinPort":80,"MaxPort":80,"CidrIP":""}]`
+	checkQuery(t, server{dbConn: conn}, db.ConnectionTable, exp)
+
+	exp = `[{"ID":2,"Namespace":"ns","Admin":["1.2.3.4/32"],` +
+		`"ApplicationPorts":null}]`
+	checkQuery(t, server{dbConn: conn}, db.ACLTable, exp)
 }
 
 func TestBadStitch(t *testing.T) {
@@ -117,3 +172,138 @@ func TestRun(t *testing.T) {
 			"but we found: %v\n", machines)
 	}
 }
+
+func TestRunRefusal(t *testing.T) {
+	conn := db.New()
+	s := server{dbConn: conn, maxTerminateFraction: 0.5}
+
+	createMachineStitch :=
+		`deployment.deploy([
+		new Machine({provider: "Amazon", size: "m4.large", role: "Master"}),
+		new Machine({provider: "Amazon", size: "m4.large", role: "Worker"}),
+		]);`
+	_, err := s.Run(context.Background(),
+		&pb.RunRequest{Stitch: createMachineStitch})
+	if err != nil {
+		t.Errorf("Unexpected error when running stich: %s\n", err.Error())
+		return
+	}
+
+	reply, err := s.Run(context.Background(), &pb.RunRequest{Stitch: ""})
+	if err != nil {
+		t.Errorf("Unexpected error when running stich: %s\n", err.Error())
+		return
+	}
+
+	expRefusal := "the spec would terminate 2 of the 2 machines, more than " +
+		"the maximum of 50%; run with -force to apply it anyway"
+	if reply.Refusal != expRefusal {
+		t.Errorf("Expected refusal %q, but got %q\n", expRefusal, reply.Refusal)
+	}
+
+	reply, err = s.Run(context.Background(),
+		&pb.RunRequest{Stitch: "", Force: true})
+	if err != nil || reply.Refusal != "" {
+		t.Errorf("Forced run should succeed, but got %v, %q\n", err,
+			reply.Refusal)
+	}
+
+	var machines []db.Machine
+	conn.Transact(func(view db.Database) error {
+		machines = view.SelectFromMachine(nil)
+		return nil
+	})
+	if len(machines) != 0 {
+		t.Errorf("Forced run should terminate every machine, but found: %v\n",
+			machines)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	conn := db.New()
+	s := server{dbConn: conn, maxTerminateFraction: 1}
+
+	first := `createDeployment({namespace: "ns"});`
+	second := `createDeployment({namespace: "ns", adminACL: ["1.2.3.4/32"]});`
+	for _, spec := range []string{first, second} {
+		_, err := s.Run(context.Background(),
+			&pb.RunRequest{Stitch: spec, User: "alice"})
+		if err != nil {
+			t.Fatalf("Unexpected error when running stitch: %s\n", err)
+		}
+	}
+
+	_, err := s.Rollback(context.Background(),
+		&pb.RollbackRequest{Revision: 1, User: "bob"})
+	if err != nil {
+		t.Fatalf("Unexpected error when rolling back: %s\n", err)
+	}
+
+	var cluster db.Cluster
+	var revisions []db.Revision
+	conn.Transact(func(view db.Database) error {
+		cluster, _ = view.GetCluster("ns")
+		revisions = view.SelectFromRevision(func(rev db.Revision) bool {
+			return rev.Number == 3
+		})
+		return nil
+	})
+
+	if cluster.Spec != first {
+		t.Errorf("Expected spec %s after rollback, but got %s\n", first,
+			cluster.Spec)
+	}
+
+	if len(revisions) != 1 || revisions[0].User != "bob" ||
+		revisions[0].Spec != first {
+		t.Errorf("Rollback should be recorded as revision 3, but got %v\n",
+			revisions)
+	}
+
+	_, err = s.Rollback(context.Background(), &pb.RollbackRequest{Revision: 7})
+	if err == nil || err.Error() != "no revision 7" {
+		t.Errorf("Expected missing revision error, but got %v\n", err)
+	}
+}
+
+func TestRollbackRefusal(t *testing.T) {
+	conn := db.New()
+	s := server{dbConn: conn, maxTerminateFraction: 0.5}
+
+	machines := `deployment.deploy([
+		new Machine({provider: "Amazon", size: "m4.large", role: "Master"}),
+		new Machine({provider: "Amazon", size: "m4.large", role: "Worker"}),
+		]);`
+	for _, spec := range []string{"", machines} {
+		_, err := s.Run(context.Background(), &pb.RunRequest{Stitch: spec})
+		if err != nil {
+			t.Fatalf("Unexpected error when running stitch: %s\n", err)
+		}
+	}
+
+	reply, err := s.Rollback(context.Background(),
+		&pb.RollbackRequest{Revision: 1})
+	expRefusal := "the spec would terminate 2 of the 2 machines, more than " +
+		"the maximum of 50%; run with -force to apply it anyway"
+	if err != nil || reply.Refusal != expRefusal {
+		t.Errorf("Expected refusal %q, but got %v, %q\n", expRefusal, err,
+			reply.Refusal)
+	}
+
+	reply, err = s.Rollback(context.Background(),
+		&pb.RollbackRequest{Revision: 1, Force: true})
+	if err != nil || reply.Refusal != "" {
+		t.Errorf("Forced rollback should succeed, but got %v, %q\n", err,
+			reply.Refusal)
+	}
+
+	var dbms []db.Machine
+	conn.Transact(func(view db.Database) error {
+		dbms = view.SelectFromMachine(nil)
+		return nil
+	})
+	if len(dbms) != 0 {
+		t.Errorf("Forced rollback should terminate every machine, but found: "+
+			"%v\n", dbms)
+	}
+}
@@ -0,0 +1,337 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NetSys/quilt/db"
+)
+
+// AmazonPricingURL is the address of the Amazon Price List API.
+const AmazonPricingURL = "https://pricing.us-east-1.amazonaws.com"
+
+const amazonIndexPath = "/offers/v1.0/aws/AmazonEC2/current/region_index.json"
+
+// Amazon is a Source that reads the EC2 instance types, and their on-demand Linux
+// prices, from the Amazon Price List API.  The price list of each region is
+// hundreds of megabytes, so it's streamed rather than read into memory.
+type Amazon struct {
+	BaseURL string
+	HTTP    *http.Client
+
+	// The regions to list.  If empty, all of them are.
+	Regions []string
+}
+
+// Catalog fetches the descriptions of the EC2 instance types.
+func (a Amazon) Catalog() (Catalog, error) {
+	var index struct {
+		Regions map[string]struct {
+			CurrentVersionURL string `json:"currentVersionUrl"`
+		} `json:"regions"`
+	}
+	if err := a.get(amazonIndexPath, func(dec *json.Decoder) error {
+		return dec.Decode(&index)
+	}); err != nil {
+		return nil, err
+	}
+
+	regions := a.Regions
+	if len(regions) == 0 {
+		for region := range index.Regions {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+	}
+
+	var descriptions []Description
+	for _, region := range regions {
+		offer, ok := index.Regions[region]
+		if !ok {
+			return nil, fmt.Errorf("unknown Amazon region: %s", region)
+		}
+
+		var regionDescs []Description
+		err := a.get(offer.CurrentVersionURL, func(dec *json.Decoder) error {
+			var err error
+			regionDescs, err = parseAmazonOffer(dec, region)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", region, err)
+		}
+		descriptions = append(descriptions, regionDescs...)
+	}
+	return Catalog{db.Amazon: descriptions}, nil
+}
+
+func (a Amazon) get(path string, parse func(*json.Decoder) error) error {
+	baseURL := a.BaseURL
+	if baseURL == "" {
+		baseURL = AmazonPricingURL
+	}
+
+	client := a.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(baseURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return parse(json.NewDecoder(resp.Body))
+}
+
+type amazonProduct struct {
+	ProductFamily string `json:"productFamily"`
+	Attributes    struct {
+		InstanceType    string `json:"instanceType"`
+		Memory          string `json:"memory"`
+		VCPU            string `json:"vcpu"`
+		GPU             string `json:"gpu"`
+		Storage         string `json:"storage"`
+		OperatingSystem string `json:"operatingSystem"`
+		Tenancy         string `json:"tenancy"`
+		PreInstalledSW  string `json:"preInstalledSw"`
+		CapacityStatus  string `json:"capacitystatus"`
+
+		CurrentGeneration  string `json:"currentGeneration"`
+		NetworkPerformance string `json:"networkPerformance"`
+	} `json:"attributes"`
+}
+
+type amazonTerm struct {
+	PriceDimensions map[string]struct {
+		Unit         string            `json:"unit"`
+		PricePerUnit map[string]string `json:"pricePerUnit"`
+	} `json:"priceDimensions"`
+}
+
+// parseAmazonOffer parses the price list of `region`.  The products are listed
+// before their terms, so the products that don't matter are dropped as soon as
+// they're read.
+func parseAmazonOffer(dec *json.Decoder, region string) ([]Description, error) {
+	products := map[string]Description{}
+	prices := map[string]float64{}
+
+	err := walkObject(dec, func(key string) error {
+		switch key {
+		case "products":
+			return walkObject(dec, func(sku string) error {
+				var product amazonProduct
+				if err := dec.Decode(&product); err != nil {
+					return err
+				}
+
+				if d, ok := amazonDescription(product); ok {
+					d.Region = region
+					products[sku] = d
+				}
+				return nil
+			})
+		case "terms":
+			return walkObject(dec, func(termType string) error {
+				if termType != "OnDemand" {
+					return skip(dec)
+				}
+
+				return walkObject(dec, func(sku string) error {
+					var terms map[string]amazonTerm
+					if err := dec.Decode(&terms); err != nil {
+						return err
+					}
+
+					if _, ok := products[sku]; !ok {
+						return nil
+					}
+
+					if price, ok := amazonHourlyPrice(terms); ok {
+						prices[sku] = price
+					}
+					return nil
+				})
+			})
+		default:
+			return skip(dec)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// There may be several products of the same instance type, so take the
+	// cheapest.
+	bySize := map[string]Description{}
+	for sku, d := range products {
+		price, ok := prices[sku]
+		if !ok {
+			continue
+		}
+
+		d.Price = price
+		if old, ok := bySize[d.Size]; !ok || d.Price < old.Price {
+			bySize[d.Size] = d
+		}
+	}
+
+	var sizes []string
+	for size := range bySize {
+		sizes = append(sizes, size)
+	}
+	sort.Strings(sizes)
+
+	var descriptions []Description
+	for _, size := range sizes {
+		descriptions = append(descriptions, bySize[size])
+	}
+	return descriptions, nil
+}
+
+var amazonStorageRegex = regexp.MustCompile(
+	`^(?:(\d+) x )?([\d,]+) (?:NVMe )?(SSD|HDD)$`)
+
+// amazonDescription converts `product` into a Description, if it's a Linux instance
+// type that Quilt can boot.
+func amazonDescription(product amazonProduct) (Description, bool) {
+	attrs := product.Attributes
+	if product.ProductFamily != "Compute Instance" ||
+		attrs.OperatingSystem != "Linux" || attrs.Tenancy != "Shared" ||
+		(attrs.PreInstalledSW != "" && attrs.PreInstalledSW != "NA") ||
+		(attrs.CapacityStatus != "" && attrs.CapacityStatus != "Used") {
+		return Description{}, false
+	}
+
+	// T1 and T2 instances are not supported for Spot requests:
+	// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/using-spot-limits.html
+	if strings.HasPrefix(attrs.InstanceType, "t1.") ||
+		strings.HasPrefix(attrs.InstanceType, "t2.") {
+		return Description{}, false
+	}
+
+	cpu, err := strconv.Atoi(attrs.VCPU)
+	if err != nil {
+		return Description{}, false
+	}
+
+	memory := strings.Replace(strings.TrimSuffix(attrs.Memory, " GiB"), ",", "",
+		-1)
+	ram, err := strconv.ParseFloat(memory, 64)
+	if err != nil {
+		return Description{}, false
+	}
+
+	d := Description{
+		Size:               attrs.InstanceType,
+		CPU:                cpu,
+		RAM:                ram,
+		Family:             strings.SplitN(attrs.InstanceType, ".", 2)[0],
+		PreviousGeneration: attrs.CurrentGeneration == "No",
+		Network:            amazonNetwork(attrs.NetworkPerformance),
+	}
+	d.GPU, _ = strconv.Atoi(attrs.GPU)
+
+	if match := amazonStorageRegex.FindStringSubmatch(attrs.Storage); match != nil {
+		disks := 1
+		if match[1] != "" {
+			disks, _ = strconv.Atoi(match[1])
+		}
+		diskSize, _ := strconv.Atoi(strings.Replace(match[2], ",", "", -1))
+		d.EphemeralDisk = disks * diskSize
+		d.EphemeralDiskType = match[3]
+	}
+	return d, true
+}
+
+var amazonNetworkRegex = regexp.MustCompile(`^(?:Up to )?(\d+) Gigabit$`)
+
+// Amazon only gives the bandwidth of its smaller sizes as a level, so these are
+// rough guesses of what each level means.
+var amazonNetworkLevels = map[string]float64{
+	"Very Low":        0.05,
+	"Low":             0.1,
+	"Low to Moderate": 0.3,
+	"Moderate":        0.5,
+	"High":            1,
+}
+
+// amazonNetwork converts a network performance, such as "High" or "Up to 10
+// Gigabit", into Gbps.
+func amazonNetwork(performance string) float64 {
+	if match := amazonNetworkRegex.FindStringSubmatch(performance); match != nil {
+		gbps, _ := strconv.ParseFloat(match[1], 64)
+		return gbps
+	}
+	return amazonNetworkLevels[performance]
+}
+
+// amazonHourlyPrice returns the hourly price in dollars of the first of `terms`
+// that has one.
+func amazonHourlyPrice(terms map[string]amazonTerm) (float64, bool) {
+	for _, term := range terms {
+		for _, dim := range term.PriceDimensions {
+			if dim.Unit != "Hrs" {
+				continue
+			}
+
+			price, err := strconv.ParseFloat(dim.PricePerUnit["USD"], 64)
+			if err == nil && price > 0 {
+				return price, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// walkObject reads a JSON object from `dec`, calling `value` with each key.
+// `value` must read the key's value from `dec`.
+func walkObject(dec *json.Decoder, value func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected an object key, got %v", tok)
+		}
+
+		if err := value(key); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("expected %s, got %v", delim, tok)
+	}
+	return nil
+}
+
+// skip reads the next value from `dec`, and throws it away.
+func skip(dec *json.Decoder) error {
+	var value json.RawMessage
+	return dec.Decode(&value)
+}
@@ -0,0 +1,183 @@
+package catalog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/NetSys/quilt/db"
+)
+
+const testIndex = `{
+	"formatVersion": "v1.0",
+	"regions": {
+		"us-west-1": {
+			"regionCode": "us-west-1",
+			"currentVersionUrl": "/offers/us-west-1/index.json"
+		},
+		"eu-west-1": {
+			"regionCode": "eu-west-1",
+			"currentVersionUrl": "/offers/eu-west-1/index.json"
+		}
+	}
+}`
+
+const testOffer = `{
+	"formatVersion": "v1.0",
+	"offerCode": "AmazonEC2",
+	"products": {
+		"LARGE": {
+			"sku": "LARGE",
+			"productFamily": "Compute Instance",
+			"attributes": {
+				"instanceType": "m4.large", "vcpu": "2",
+				"memory": "8 GiB", "storage": "EBS only",
+				"operatingSystem": "Linux", "tenancy": "Shared",
+				"preInstalledSw": "NA", "capacitystatus": "Used",
+				"currentGeneration": "Yes",
+				"networkPerformance": "Moderate"
+			}
+		},
+		"PREVIOUS": {
+			"sku": "PREVIOUS",
+			"productFamily": "Compute Instance",
+			"attributes": {
+				"instanceType": "m3.medium", "vcpu": "1",
+				"memory": "3.75 GiB", "storage": "1 x 4 SSD",
+				"operatingSystem": "Linux", "tenancy": "Shared",
+				"currentGeneration": "No", "networkPerformance": "Low"
+			}
+		},
+		"LARGE-WINDOWS": {
+			"sku": "LARGE-WINDOWS",
+			"productFamily": "Compute Instance",
+			"attributes": {
+				"instanceType": "m4.large", "vcpu": "2",
+				"memory": "8 GiB", "storage": "EBS only",
+				"operatingSystem": "Windows", "tenancy": "Shared"
+			}
+		},
+		"GPU": {
+			"sku": "GPU",
+			"productFamily": "Compute Instance",
+			"attributes": {
+				"instanceType": "p2.16xlarge", "vcpu": "64",
+				"memory": "732 GiB", "gpu": "16",
+				"storage": "EBS only", "operatingSystem": "Linux",
+				"tenancy": "Shared", "networkPerformance": "25 Gigabit"
+			}
+		},
+		"STORAGE": {
+			"sku": "STORAGE",
+			"productFamily": "Compute Instance",
+			"attributes": {
+				"instanceType": "i3.16xlarge", "vcpu": "64",
+				"memory": "488 GiB", "storage": "8 x 1,900 NVMe SSD",
+				"operatingSystem": "Linux", "tenancy": "Shared",
+				"networkPerformance": "Up to 25 Gigabit"
+			}
+		},
+		"BURST": {
+			"sku": "BURST",
+			"productFamily": "Compute Instance",
+			"attributes": {
+				"instanceType": "t2.micro", "vcpu": "1",
+				"memory": "1 GiB", "storage": "EBS only",
+				"operatingSystem": "Linux", "tenancy": "Shared"
+			}
+		},
+		"VOLUME": {
+			"sku": "VOLUME",
+			"productFamily": "Storage",
+			"attributes": {"volumeType": "General Purpose"}
+		}
+	},
+	"terms": {
+		"OnDemand": {
+			"LARGE": {"LARGE.TERM": {"priceDimensions": {"LARGE.TERM.RATE": {
+				"unit": "Hrs", "pricePerUnit": {"USD": "%s"}}}}},
+			"PREVIOUS": {"P.TERM": {"priceDimensions": {"P.TERM.RATE": {
+				"unit": "Hrs", "pricePerUnit": {"USD": "0.067"}}}}},
+			"LARGE-WINDOWS": {"W.TERM": {"priceDimensions": {"W.TERM.RATE": {
+				"unit": "Hrs", "pricePerUnit": {"USD": "0.2"}}}}},
+			"GPU": {"GPU.TERM": {"priceDimensions": {"GPU.TERM.RATE": {
+				"unit": "Hrs", "pricePerUnit": {"USD": "15.3"}}}}},
+			"STORAGE": {"S.TERM": {"priceDimensions": {"S.TERM.RATE": {
+				"unit": "Hrs", "pricePerUnit": {"USD": "5.5"}}}}},
+			"BURST": {"B.TERM": {"priceDimensions": {"B.TERM.RATE": {
+				"unit": "Hrs", "pricePerUnit": {"USD": "0.013"}}}}}
+		},
+		"Reserved": {
+			"LARGE": {"LARGE.RESERVED": {"priceDimensions": {}}}
+		}
+	}
+}`
+
+func TestAmazon(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case amazonIndexPath:
+				fmt.Fprint(w, testIndex)
+			case "/offers/us-west-1/index.json":
+				fmt.Fprintf(w, testOffer, "0.117")
+			case "/offers/eu-west-1/index.json":
+				fmt.Fprintf(w, testOffer, "0.111")
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	defer server.Close()
+
+	source := Amazon{BaseURL: server.URL}
+	c, err := source.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp []Description
+	for _, region := range []string{"eu-west-1", "us-west-1"} {
+		price := 0.117
+		if region == "eu-west-1" {
+			price = 0.111
+		}
+
+		exp = append(exp,
+			Description{Size: "i3.16xlarge", Region: region, Price: 5.5,
+				CPU: 64, RAM: 488, EphemeralDisk: 15200,
+				EphemeralDiskType: "SSD", Family: "i3", Network: 25},
+			Description{Size: "m3.medium", Region: region, Price: 0.067,
+				CPU: 1, RAM: 3.75, EphemeralDisk: 4,
+				EphemeralDiskType: "SSD", Family: "m3",
+				PreviousGeneration: true, Network: 0.1},
+			Description{Size: "m4.large", Region: region, Price: price,
+				CPU: 2, RAM: 8, Family: "m4", Network: 0.5},
+			Description{Size: "p2.16xlarge", Region: region, Price: 15.3,
+				CPU: 64, RAM: 732, GPU: 16, Family: "p2", Network: 25})
+	}
+
+	if !reflect.DeepEqual(c, Catalog{db.Amazon: exp}) {
+		t.Errorf("\nGot: %v\nExp: %v", c[db.Amazon], exp)
+	}
+
+	source.Regions = []string{"us-west-1"}
+	if c, err = source.Catalog(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, Catalog{db.Amazon: exp[4:]}) {
+		t.Errorf("\nGot: %v\nExp: %v", c[db.Amazon], exp[4:])
+	}
+
+	source.Regions = []string{"mars-1"}
+	_, err = source.Catalog()
+	if err == nil || err.Error() != "unknown Amazon region: mars-1" {
+		t.Errorf("expected an unknown region error, got %v", err)
+	}
+
+	source.BaseURL = server.URL + "/missing"
+	if _, err = source.Catalog(); err == nil {
+		t.Error("expected an error fetching a missing price list")
+	}
+}
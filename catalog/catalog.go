@@ -0,0 +1,243 @@
+//go:generate ../scripts/generate-catalog catalog.json
+
+// Package catalog lists the machine sizes each cloud provider offers, and what they
+// cost in each region.  Quilt bundles a catalog, which `quilt catalog update`
+// refreshes from the providers' APIs.
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NetSys/quilt/db"
+
+	log "github.com/Sirupsen/logrus"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// A Description describes a machine size offered by a cloud provider.
+type Description struct {
+	Size   string
+	Region string  // Empty if the size costs the same in every region.
+	Price  float64 // The on-demand price, in dollars per hour.
+	CPU    int
+	RAM    float64 // In GiB.
+	GPU    int
+
+	// The total size in GB, and the kind ("SSD" or "HDD"), of the machine's
+	// instance storage, which is lost when the machine stops.
+	EphemeralDisk     int
+	EphemeralDiskType string
+
+	// The group of sizes with the same hardware, such as "m4" on Amazon, or
+	// "n1-highmem" on Google.
+	Family string
+
+	// Whether the provider has replaced the size with a newer generation.  Old
+	// sizes are often cheaper on the spot market.
+	PreviousGeneration bool
+
+	// The network bandwidth in Gbps, or zero if it isn't known.  Providers
+	// rarely promise a bandwidth, so this is only a rough guide.
+	Network float64
+}
+
+// InFamilies returns true if `family` is one of `families`, or belongs to one of
+// them, as "n1-highmem" belongs to "n1".
+func InFamilies(family string, families []string) bool {
+	for _, f := range families {
+		if family == f || strings.HasPrefix(family, f+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// A Catalog holds the Descriptions of each provider's sizes.
+type Catalog map[db.Provider][]Description
+
+// A Source provides a Catalog, perhaps from a file or a provider's API.
+type Source interface {
+	Catalog() (Catalog, error)
+}
+
+// DefaultPath is where `quilt catalog update` saves the catalog.
+const DefaultPath = "~/.quilt/catalog.json"
+
+// Default is the Source of the catalog used to choose machine sizes.  It's the
+// catalog saved by `quilt catalog update`, or the bundled one if there isn't one.
+var Default Source = NewFile(DefaultPath)
+
+// Bundled is the Source of the catalog built into Quilt.
+var Bundled Source = bundledSource{}
+
+type bundledSource struct{}
+
+func (bundledSource) Catalog() (Catalog, error) {
+	return Parse([]byte(bundledJSON))
+}
+
+// Get returns the Descriptions of `p`'s sizes in the Default catalog, or in the
+// bundled one if the Default can't be read.
+func Get(p db.Provider) []Description {
+	c, err := Default.Catalog()
+	if err != nil {
+		log.WithError(err).Warn("Failed to read the catalog, using the " +
+			"bundled one instead.")
+		c, _ = Bundled.Catalog()
+	}
+	return c[p]
+}
+
+// InRegion returns the `descriptions` that apply in `region`.  A Google zone, such
+// as "us-east1-b", is in the region it's named after.  If the catalog doesn't know
+// of the region at all, prices elsewhere are the best guess, so all of the
+// `descriptions` are returned.
+func InRegion(descriptions []Description, region string) []Description {
+	var inRegion []Description
+	for _, d := range descriptions {
+		if d.Region == "" || d.Region == region ||
+			strings.HasPrefix(region, d.Region+"-") {
+			inRegion = append(inRegion, d)
+		}
+	}
+
+	if len(inRegion) == 0 {
+		return descriptions
+	}
+	return inRegion
+}
+
+// A File is a Source that reads a catalog file, which is only parsed again once it
+// changes.  If there's no file, the bundled catalog is used.
+type File struct {
+	path string
+
+	mutex   sync.Mutex
+	modTime time.Time
+	catalog Catalog
+}
+
+// NewFile returns a Source that reads the catalog at `path`.
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Catalog returns the catalog in the file.
+func (f *File) Catalog() (Catalog, error) {
+	path, err := homedir.Expand(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return Bundled.Catalog()
+	} else if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.catalog != nil && info.ModTime().Equal(f.modTime) {
+		return f.catalog, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f.catalog = c
+	f.modTime = info.ModTime()
+	return c, nil
+}
+
+// Save writes `c` to the file at `path`, replacing whatever is there.
+func Save(path string, c Catalog) error {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, c); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that the daemon never reads half of a
+	// catalog.
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Parse parses a catalog written by Write.
+func Parse(data []byte) (Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Write writes `c` as JSON with a Description per line, so that changes to the
+// bundled catalog are easy to review.
+func Write(w io.Writer, c Catalog) error {
+	var providers []string
+	for p := range c {
+		providers = append(providers, string(p))
+	}
+	sort.Strings(providers)
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, p := range providers {
+		name, _ := json.Marshal(p)
+		buf.WriteString("\t" + string(name) + ": [\n")
+
+		descriptions := c[db.Provider(p)]
+		for j, d := range descriptions {
+			line, err := json.Marshal(d)
+			if err != nil {
+				return err
+			}
+
+			buf.WriteString("\t\t" + string(line))
+			if j < len(descriptions)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+
+		buf.WriteString("\t]")
+		if i < len(providers)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
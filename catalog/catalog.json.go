@@ -0,0 +1,436 @@
+// Autogenerated code. DO NOT EDIT!
+
+package catalog
+
+var bundledJSON = `{
+	"Amazon": [
+		{"Size":"m4.large","Region":"us-east-1","Price":0.12,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"us-east-1","Price":0.239,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"us-east-1","Price":0.479,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"us-east-1","Price":0.958,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"us-east-1","Price":2.394,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"us-east-1","Price":0.067,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"us-east-1","Price":0.133,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"us-east-1","Price":0.266,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"us-east-1","Price":0.532,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"us-east-1","Price":0.105,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"us-east-1","Price":0.209,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"us-east-1","Price":0.419,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"us-east-1","Price":0.838,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"us-east-1","Price":1.675,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"us-east-1","Price":0.105,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"us-east-1","Price":0.21,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"us-east-1","Price":0.42,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"us-east-1","Price":0.84,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"us-east-1","Price":1.68,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"us-east-1","Price":0.65,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"us-east-1","Price":2.6,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"us-east-1","Price":0.166,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"us-east-1","Price":0.333,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"us-east-1","Price":0.665,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"us-east-1","Price":1.33,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"us-east-1","Price":2.66,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"us-east-1","Price":0.853,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"us-east-1","Price":1.705,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"us-east-1","Price":3.41,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"us-east-1","Price":6.82,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"us-east-1","Price":0.69,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"us-east-1","Price":1.38,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"us-east-1","Price":2.76,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"us-east-1","Price":5.52,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m4.large","Region":"us-west-2","Price":0.12,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"us-west-2","Price":0.239,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"us-west-2","Price":0.479,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"us-west-2","Price":0.958,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"us-west-2","Price":2.394,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"us-west-2","Price":0.067,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"us-west-2","Price":0.133,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"us-west-2","Price":0.266,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"us-west-2","Price":0.532,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"us-west-2","Price":0.105,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"us-west-2","Price":0.209,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"us-west-2","Price":0.419,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"us-west-2","Price":0.838,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"us-west-2","Price":1.675,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"us-west-2","Price":0.105,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"us-west-2","Price":0.21,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"us-west-2","Price":0.42,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"us-west-2","Price":0.84,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"us-west-2","Price":1.68,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"us-west-2","Price":0.65,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"us-west-2","Price":2.6,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"us-west-2","Price":0.166,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"us-west-2","Price":0.333,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"us-west-2","Price":0.665,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"us-west-2","Price":1.33,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"us-west-2","Price":2.66,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"us-west-2","Price":0.853,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"us-west-2","Price":1.705,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"us-west-2","Price":3.41,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"us-west-2","Price":6.82,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"us-west-2","Price":0.69,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"us-west-2","Price":1.38,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"us-west-2","Price":2.76,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"us-west-2","Price":5.52,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m4.large","Region":"us-west-1","Price":0.14,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"us-west-1","Price":0.279,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"us-west-1","Price":0.559,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"us-west-1","Price":1.117,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"us-west-1","Price":2.793,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"us-west-1","Price":0.077,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"us-west-1","Price":0.154,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"us-west-1","Price":0.308,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"us-west-1","Price":0.616,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"us-west-1","Price":0.131,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"us-west-1","Price":0.262,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"us-west-1","Price":0.524,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"us-west-1","Price":1.049,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"us-west-1","Price":2.098,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"us-west-1","Price":0.12,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"us-west-1","Price":0.239,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"us-west-1","Price":0.478,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"us-west-1","Price":0.956,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"us-west-1","Price":1.912,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"us-west-1","Price":0.702,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"us-west-1","Price":2.808,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"us-west-1","Price":0.185,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"us-west-1","Price":0.371,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"us-west-1","Price":0.741,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"us-west-1","Price":1.482,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"us-west-1","Price":2.964,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"us-west-1","Price":0.938,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"us-west-1","Price":1.876,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"us-west-1","Price":3.751,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"us-west-1","Price":7.502,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"m4.large","Region":"eu-west-1","Price":0.132,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"eu-west-1","Price":0.264,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"eu-west-1","Price":0.528,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"eu-west-1","Price":1.056,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"eu-west-1","Price":2.641,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"eu-west-1","Price":0.073,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"eu-west-1","Price":0.146,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"eu-west-1","Price":0.293,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"eu-west-1","Price":0.585,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"eu-west-1","Price":0.119,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"eu-west-1","Price":0.238,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"eu-west-1","Price":0.477,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"eu-west-1","Price":0.953,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"eu-west-1","Price":1.906,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"eu-west-1","Price":0.12,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"eu-west-1","Price":0.239,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"eu-west-1","Price":0.478,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"eu-west-1","Price":0.956,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"eu-west-1","Price":1.912,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"eu-west-1","Price":0.702,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"eu-west-1","Price":2.808,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"eu-west-1","Price":0.185,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"eu-west-1","Price":0.371,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"eu-west-1","Price":0.741,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"eu-west-1","Price":1.482,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"eu-west-1","Price":2.964,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"eu-west-1","Price":0.938,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"eu-west-1","Price":1.876,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"eu-west-1","Price":3.751,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"eu-west-1","Price":7.502,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"eu-west-1","Price":0.735,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"eu-west-1","Price":1.47,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"eu-west-1","Price":2.94,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"eu-west-1","Price":5.88,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m4.large","Region":"eu-central-1","Price":0.143,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"eu-central-1","Price":0.285,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"eu-central-1","Price":0.57,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"eu-central-1","Price":1.14,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"eu-central-1","Price":2.85,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"eu-central-1","Price":0.079,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"eu-central-1","Price":0.158,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"eu-central-1","Price":0.315,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"eu-central-1","Price":0.632,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"eu-central-1","Price":0.134,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"eu-central-1","Price":0.267,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"eu-central-1","Price":0.534,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"eu-central-1","Price":1.069,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"eu-central-1","Price":2.138,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"eu-central-1","Price":0.129,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"eu-central-1","Price":0.258,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"eu-central-1","Price":0.516,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"eu-central-1","Price":1.032,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"eu-central-1","Price":2.064,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"eu-central-1","Price":0.772,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"eu-central-1","Price":3.088,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"eu-central-1","Price":0.2,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"eu-central-1","Price":0.4,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"eu-central-1","Price":0.8,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"eu-central-1","Price":1.6,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"eu-central-1","Price":3.201,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"eu-central-1","Price":1.013,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"eu-central-1","Price":2.026,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"eu-central-1","Price":4.051,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"eu-central-1","Price":8.102,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"eu-central-1","Price":0.794,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"eu-central-1","Price":1.588,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"eu-central-1","Price":3.176,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"eu-central-1","Price":6.352,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m4.large","Region":"ap-southeast-1","Price":0.178,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"ap-southeast-1","Price":0.355,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"ap-southeast-1","Price":0.711,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"ap-southeast-1","Price":1.421,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"ap-southeast-1","Price":3.553,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"ap-southeast-1","Price":0.098,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"ap-southeast-1","Price":0.196,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"ap-southeast-1","Price":0.392,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"ap-southeast-1","Price":0.784,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"ap-southeast-1","Price":0.144,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"ap-southeast-1","Price":0.289,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"ap-southeast-1","Price":0.578,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"ap-southeast-1","Price":1.155,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"ap-southeast-1","Price":2.31,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"ap-southeast-1","Price":0.132,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"ap-southeast-1","Price":0.265,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"ap-southeast-1","Price":0.529,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"ap-southeast-1","Price":1.058,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"ap-southeast-1","Price":2.117,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"ap-southeast-1","Price":1,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"ap-southeast-1","Price":4,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"ap-southeast-1","Price":0.2,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"ap-southeast-1","Price":0.399,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"ap-southeast-1","Price":0.798,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"ap-southeast-1","Price":1.596,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"ap-southeast-1","Price":3.192,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"ap-southeast-1","Price":1.018,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"ap-southeast-1","Price":2.035,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"ap-southeast-1","Price":4.07,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"ap-southeast-1","Price":8.14,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"ap-southeast-1","Price":0.87,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"ap-southeast-1","Price":1.74,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"ap-southeast-1","Price":3.48,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"ap-southeast-1","Price":6.96,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m4.large","Region":"ap-northeast-1","Price":0.174,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"ap-northeast-1","Price":0.348,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"ap-northeast-1","Price":0.695,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"ap-northeast-1","Price":1.391,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"ap-northeast-1","Price":3.477,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"ap-northeast-1","Price":0.096,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"ap-northeast-1","Price":0.193,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"ap-northeast-1","Price":0.385,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"ap-northeast-1","Price":0.77,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"ap-northeast-1","Price":0.133,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"ap-northeast-1","Price":0.265,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"ap-northeast-1","Price":0.531,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"ap-northeast-1","Price":1.061,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"ap-northeast-1","Price":2.122,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"ap-northeast-1","Price":0.128,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"ap-northeast-1","Price":0.255,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"ap-northeast-1","Price":0.511,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"ap-northeast-1","Price":1.021,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"ap-northeast-1","Price":2.043,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"ap-northeast-1","Price":0.898,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"ap-northeast-1","Price":3.592,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"ap-northeast-1","Price":0.2,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"ap-northeast-1","Price":0.399,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"ap-northeast-1","Price":0.798,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"ap-northeast-1","Price":1.596,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"ap-northeast-1","Price":3.192,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"ap-northeast-1","Price":1.001,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"ap-northeast-1","Price":2.001,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"ap-northeast-1","Price":4.002,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"ap-northeast-1","Price":8.004,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"ap-northeast-1","Price":0.844,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"ap-northeast-1","Price":1.688,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"ap-northeast-1","Price":3.376,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"ap-northeast-1","Price":6.752,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m4.large","Region":"ap-southeast-2","Price":0.168,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"ap-southeast-2","Price":0.336,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"ap-southeast-2","Price":0.673,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"ap-southeast-2","Price":1.345,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"ap-southeast-2","Price":3.363,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"ap-southeast-2","Price":0.093,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"ap-southeast-2","Price":0.186,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"ap-southeast-2","Price":0.372,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"ap-southeast-2","Price":0.745,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c4.large","Region":"ap-southeast-2","Price":0.137,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"ap-southeast-2","Price":0.275,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"ap-southeast-2","Price":0.549,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"ap-southeast-2","Price":1.097,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"ap-southeast-2","Price":2.195,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"c3.large","Region":"ap-southeast-2","Price":0.132,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"ap-southeast-2","Price":0.265,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"ap-southeast-2","Price":0.529,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"ap-southeast-2","Price":1.058,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"ap-southeast-2","Price":2.117,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"g2.2xlarge","Region":"ap-southeast-2","Price":0.898,"CPU":8,"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"g2.8xlarge","Region":"ap-southeast-2","Price":3.592,"CPU":32,"RAM":60,"GPU":4,"EphemeralDisk":240,"EphemeralDiskType":"SSD","Family":"g2","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"ap-southeast-2","Price":0.2,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"ap-southeast-2","Price":0.399,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"ap-southeast-2","Price":0.798,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"ap-southeast-2","Price":1.596,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"ap-southeast-2","Price":3.192,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"ap-southeast-2","Price":1.018,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"ap-southeast-2","Price":2.035,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"ap-southeast-2","Price":4.07,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"ap-southeast-2","Price":8.14,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"ap-southeast-2","Price":0.87,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"ap-southeast-2","Price":1.74,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"ap-southeast-2","Price":3.48,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"ap-southeast-2","Price":6.96,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m4.large","Region":"ap-northeast-2","Price":0.165,"CPU":2,"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"m4.xlarge","Region":"ap-northeast-2","Price":0.331,"CPU":4,"RAM":16,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.2xlarge","Region":"ap-northeast-2","Price":0.66,"CPU":8,"RAM":32,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.4xlarge","Region":"ap-northeast-2","Price":1.321,"CPU":16,"RAM":64,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":1},
+		{"Size":"m4.10xlarge","Region":"ap-northeast-2","Price":3.303,"CPU":40,"RAM":160,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"m4","PreviousGeneration":false,"Network":10},
+		{"Size":"c4.large","Region":"ap-northeast-2","Price":0.12,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":0.5},
+		{"Size":"c4.xlarge","Region":"ap-northeast-2","Price":0.239,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.2xlarge","Region":"ap-northeast-2","Price":0.478,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.4xlarge","Region":"ap-northeast-2","Price":0.955,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":1},
+		{"Size":"c4.8xlarge","Region":"ap-northeast-2","Price":1.91,"CPU":36,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"c4","PreviousGeneration":false,"Network":10},
+		{"Size":"r3.large","Region":"ap-northeast-2","Price":0.2,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"ap-northeast-2","Price":0.399,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"ap-northeast-2","Price":0.798,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"ap-northeast-2","Price":1.596,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"ap-northeast-2","Price":3.192,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"ap-northeast-2","Price":1.001,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"ap-northeast-2","Price":2.001,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"ap-northeast-2","Price":4.002,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"ap-northeast-2","Price":8.004,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"ap-northeast-2","Price":0.844,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"ap-northeast-2","Price":1.688,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"ap-northeast-2","Price":3.376,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"ap-northeast-2","Price":6.752,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10},
+		{"Size":"m3.medium","Region":"sa-east-1","Price":0.095,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"sa-east-1","Price":0.19,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"sa-east-1","Price":0.381,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"sa-east-1","Price":0.761,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.large","Region":"sa-east-1","Price":0.163,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"sa-east-1","Price":0.325,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"sa-east-1","Price":0.65,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"sa-east-1","Price":1.3,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"sa-east-1","Price":2.6,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.4xlarge","Region":"sa-east-1","Price":2.799,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"sa-east-1","Price":5.597,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"m3.medium","Region":"us-gov-west-1","Price":0.084,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":4,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.large","Region":"us-gov-west-1","Price":0.168,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"m3.xlarge","Region":"us-gov-west-1","Price":0.336,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"m3.2xlarge","Region":"us-gov-west-1","Price":0.672,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"m3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.large","Region":"us-gov-west-1","Price":0.126,"CPU":2,"RAM":3.75,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.xlarge","Region":"us-gov-west-1","Price":0.252,"CPU":4,"RAM":7.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"c3.2xlarge","Region":"us-gov-west-1","Price":0.504,"CPU":8,"RAM":15,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.4xlarge","Region":"us-gov-west-1","Price":1.008,"CPU":16,"RAM":30,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":1},
+		{"Size":"c3.8xlarge","Region":"us-gov-west-1","Price":2.016,"CPU":32,"RAM":60,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"c3","PreviousGeneration":true,"Network":10},
+		{"Size":"r3.large","Region":"us-gov-west-1","Price":0.2,"CPU":2,"RAM":15,"GPU":0,"EphemeralDisk":32,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.xlarge","Region":"us-gov-west-1","Price":0.399,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":0.5},
+		{"Size":"r3.2xlarge","Region":"us-gov-west-1","Price":0.798,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.4xlarge","Region":"us-gov-west-1","Price":1.596,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":1},
+		{"Size":"r3.8xlarge","Region":"us-gov-west-1","Price":3.192,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"r3","PreviousGeneration":true,"Network":10},
+		{"Size":"i2.xlarge","Region":"us-gov-west-1","Price":1.023,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":800,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":0.5},
+		{"Size":"i2.2xlarge","Region":"us-gov-west-1","Price":2.046,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":1600,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.4xlarge","Region":"us-gov-west-1","Price":4.092,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":3200,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":1},
+		{"Size":"i2.8xlarge","Region":"us-gov-west-1","Price":8.184,"CPU":32,"RAM":244,"GPU":0,"EphemeralDisk":6400,"EphemeralDiskType":"SSD","Family":"i2","PreviousGeneration":true,"Network":10},
+		{"Size":"d2.xlarge","Region":"us-gov-west-1","Price":0.828,"CPU":4,"RAM":30.5,"GPU":0,"EphemeralDisk":6000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":0.5},
+		{"Size":"d2.2xlarge","Region":"us-gov-west-1","Price":1.656,"CPU":8,"RAM":61,"GPU":0,"EphemeralDisk":12000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.4xlarge","Region":"us-gov-west-1","Price":3.312,"CPU":16,"RAM":122,"GPU":0,"EphemeralDisk":24000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":1},
+		{"Size":"d2.8xlarge","Region":"us-gov-west-1","Price":6.624,"CPU":36,"RAM":244,"GPU":0,"EphemeralDisk":48000,"EphemeralDiskType":"HDD","Family":"d2","PreviousGeneration":false,"Network":10}
+	],
+	"DigitalOcean": [
+		{"Size":"512mb","Region":"","Price":0.007,"CPU":1,"RAM":0.5,"GPU":0,"EphemeralDisk":20,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"1gb","Region":"","Price":0.015,"CPU":1,"RAM":1,"GPU":0,"EphemeralDisk":30,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"2gb","Region":"","Price":0.03,"CPU":2,"RAM":2,"GPU":0,"EphemeralDisk":40,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"4gb","Region":"","Price":0.06,"CPU":2,"RAM":4,"GPU":0,"EphemeralDisk":60,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"8gb","Region":"","Price":0.119,"CPU":4,"RAM":8,"GPU":0,"EphemeralDisk":80,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"16gb","Region":"","Price":0.238,"CPU":8,"RAM":16,"GPU":0,"EphemeralDisk":160,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"32gb","Region":"","Price":0.476,"CPU":12,"RAM":32,"GPU":0,"EphemeralDisk":320,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"48gb","Region":"","Price":0.714,"CPU":16,"RAM":48,"GPU":0,"EphemeralDisk":480,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0},
+		{"Size":"64gb","Region":"","Price":0.952,"CPU":20,"RAM":64,"GPU":0,"EphemeralDisk":640,"EphemeralDiskType":"SSD","Family":"","PreviousGeneration":false,"Network":0}
+	],
+	"Google": [
+		{"Size":"n1-standard-1","Region":"us-central1","Price":0.05,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":2},
+		{"Size":"n1-standard-1","Region":"us-east1","Price":0.05,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":2},
+		{"Size":"n1-standard-1","Region":"us-west1","Price":0.05,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":2},
+		{"Size":"n1-standard-2","Region":"us-central1","Price":0.1,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-standard-2","Region":"us-east1","Price":0.1,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-standard-2","Region":"us-west1","Price":0.1,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-standard-4","Region":"us-central1","Price":0.2,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-standard-4","Region":"us-east1","Price":0.2,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-standard-4","Region":"us-west1","Price":0.2,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-standard-8","Region":"us-central1","Price":0.4,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-8","Region":"us-east1","Price":0.4,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-8","Region":"us-west1","Price":0.4,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-16","Region":"us-central1","Price":0.8,"CPU":16,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-16","Region":"us-east1","Price":0.8,"CPU":16,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-16","Region":"us-west1","Price":0.8,"CPU":16,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-32","Region":"us-central1","Price":1.6,"CPU":32,"RAM":120,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-32","Region":"us-east1","Price":1.6,"CPU":32,"RAM":120,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-32","Region":"us-west1","Price":1.6,"CPU":32,"RAM":120,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"f1-micro","Region":"us-central1","Price":0.008,"CPU":1,"RAM":0.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"f1-micro","PreviousGeneration":false,"Network":2},
+		{"Size":"f1-micro","Region":"us-east1","Price":0.008,"CPU":1,"RAM":0.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"f1-micro","PreviousGeneration":false,"Network":2},
+		{"Size":"f1-micro","Region":"us-west1","Price":0.008,"CPU":1,"RAM":0.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"f1-micro","PreviousGeneration":false,"Network":2},
+		{"Size":"g1-small","Region":"us-central1","Price":0.027,"CPU":1,"RAM":1.7,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"g1-small","PreviousGeneration":false,"Network":2},
+		{"Size":"g1-small","Region":"us-east1","Price":0.027,"CPU":1,"RAM":1.7,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"g1-small","PreviousGeneration":false,"Network":2},
+		{"Size":"g1-small","Region":"us-west1","Price":0.027,"CPU":1,"RAM":1.7,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"g1-small","PreviousGeneration":false,"Network":2},
+		{"Size":"n1-highmem-2","Region":"us-central1","Price":0.126,"CPU":2,"RAM":13,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highmem-2","Region":"us-east1","Price":0.126,"CPU":2,"RAM":13,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highmem-2","Region":"us-west1","Price":0.126,"CPU":2,"RAM":13,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highmem-4","Region":"us-central1","Price":0.252,"CPU":4,"RAM":26,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highmem-4","Region":"us-east1","Price":0.252,"CPU":4,"RAM":26,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highmem-4","Region":"us-west1","Price":0.252,"CPU":4,"RAM":26,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highmem-8","Region":"us-central1","Price":0.504,"CPU":8,"RAM":52,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-8","Region":"us-east1","Price":0.504,"CPU":8,"RAM":52,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-8","Region":"us-west1","Price":0.504,"CPU":8,"RAM":52,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-16","Region":"us-central1","Price":1.008,"CPU":16,"RAM":104,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-16","Region":"us-east1","Price":1.008,"CPU":16,"RAM":104,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-16","Region":"us-west1","Price":1.008,"CPU":16,"RAM":104,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-32","Region":"us-central1","Price":2.016,"CPU":32,"RAM":208,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-32","Region":"us-east1","Price":2.016,"CPU":32,"RAM":208,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-32","Region":"us-west1","Price":2.016,"CPU":32,"RAM":208,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-2","Region":"us-central1","Price":0.076,"CPU":2,"RAM":1.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highcpu-2","Region":"us-east1","Price":0.076,"CPU":2,"RAM":1.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highcpu-2","Region":"us-west1","Price":0.076,"CPU":2,"RAM":1.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highcpu-4","Region":"us-central1","Price":0.152,"CPU":4,"RAM":3.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highcpu-4","Region":"us-east1","Price":0.152,"CPU":4,"RAM":3.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highcpu-4","Region":"us-west1","Price":0.152,"CPU":4,"RAM":3.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highcpu-8","Region":"us-central1","Price":0.304,"CPU":8,"RAM":7.2,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-8","Region":"us-east1","Price":0.304,"CPU":8,"RAM":7.2,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-8","Region":"us-west1","Price":0.304,"CPU":8,"RAM":7.2,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-16","Region":"us-central1","Price":0.608,"CPU":16,"RAM":14.4,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-16","Region":"us-east1","Price":0.608,"CPU":16,"RAM":14.4,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-16","Region":"us-west1","Price":0.608,"CPU":16,"RAM":14.4,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-32","Region":"us-central1","Price":1.216,"CPU":32,"RAM":28.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-32","Region":"us-east1","Price":1.216,"CPU":32,"RAM":28.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-32","Region":"us-west1","Price":1.216,"CPU":32,"RAM":28.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-1","Region":"europe-west1","Price":0.055,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":2},
+		{"Size":"n1-standard-1","Region":"asia-east1","Price":0.055,"CPU":1,"RAM":3.75,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":2},
+		{"Size":"n1-standard-2","Region":"europe-west1","Price":0.11,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-standard-2","Region":"asia-east1","Price":0.11,"CPU":2,"RAM":7.5,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-standard-4","Region":"europe-west1","Price":0.22,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-standard-4","Region":"asia-east1","Price":0.22,"CPU":4,"RAM":15,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-standard-8","Region":"europe-west1","Price":0.44,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-8","Region":"asia-east1","Price":0.44,"CPU":8,"RAM":30,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-16","Region":"europe-west1","Price":0.88,"CPU":16,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-16","Region":"asia-east1","Price":0.88,"CPU":16,"RAM":60,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-32","Region":"europe-west1","Price":1.76,"CPU":32,"RAM":120,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-standard-32","Region":"asia-east1","Price":1.76,"CPU":32,"RAM":120,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-standard","PreviousGeneration":false,"Network":16},
+		{"Size":"f1-micro","Region":"europe-west1","Price":0.009,"CPU":1,"RAM":0.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"f1-micro","PreviousGeneration":false,"Network":2},
+		{"Size":"f1-micro","Region":"asia-east1","Price":0.009,"CPU":1,"RAM":0.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"f1-micro","PreviousGeneration":false,"Network":2},
+		{"Size":"g1-small","Region":"europe-west1","Price":0.03,"CPU":1,"RAM":1.7,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"g1-small","PreviousGeneration":false,"Network":2},
+		{"Size":"g1-small","Region":"asia-east1","Price":0.03,"CPU":1,"RAM":1.7,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"g1-small","PreviousGeneration":false,"Network":2},
+		{"Size":"n1-highmem-2","Region":"europe-west1","Price":0.139,"CPU":2,"RAM":13,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highmem-2","Region":"asia-east1","Price":0.139,"CPU":2,"RAM":13,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highmem-4","Region":"europe-west1","Price":0.278,"CPU":4,"RAM":26,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highmem-4","Region":"asia-east1","Price":0.278,"CPU":4,"RAM":26,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highmem-8","Region":"europe-west1","Price":0.556,"CPU":8,"RAM":52,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-8","Region":"asia-east1","Price":0.556,"CPU":8,"RAM":52,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-16","Region":"europe-west1","Price":1.112,"CPU":16,"RAM":104,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-16","Region":"asia-east1","Price":1.112,"CPU":16,"RAM":104,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-32","Region":"europe-west1","Price":2.224,"CPU":32,"RAM":208,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highmem-32","Region":"asia-east1","Price":2.224,"CPU":32,"RAM":208,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highmem","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-2","Region":"europe-west1","Price":0.084,"CPU":2,"RAM":1.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highcpu-2","Region":"asia-east1","Price":0.084,"CPU":2,"RAM":1.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":4},
+		{"Size":"n1-highcpu-4","Region":"europe-west1","Price":0.168,"CPU":4,"RAM":3.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highcpu-4","Region":"asia-east1","Price":0.168,"CPU":4,"RAM":3.6,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":8},
+		{"Size":"n1-highcpu-8","Region":"europe-west1","Price":0.336,"CPU":8,"RAM":7.2,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-8","Region":"asia-east1","Price":0.336,"CPU":8,"RAM":7.2,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-16","Region":"europe-west1","Price":0.672,"CPU":16,"RAM":14.4,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-16","Region":"asia-east1","Price":0.672,"CPU":16,"RAM":14.4,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-32","Region":"europe-west1","Price":1.344,"CPU":32,"RAM":28.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16},
+		{"Size":"n1-highcpu-32","Region":"asia-east1","Price":1.344,"CPU":32,"RAM":28.8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"","Family":"n1-highcpu","PreviousGeneration":false,"Network":16}
+	]
+}
+`
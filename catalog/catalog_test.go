@@ -0,0 +1,199 @@
+package catalog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NetSys/quilt/db"
+)
+
+func TestBundled(t *testing.T) {
+	c, err := Bundled.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []db.Provider{db.Amazon, db.Google, db.DigitalOcean} {
+		if len(c[p]) == 0 {
+			t.Errorf("no %s sizes in the bundled catalog", p)
+		}
+	}
+}
+
+func TestWriteParse(t *testing.T) {
+	c := Catalog{
+		db.Amazon: {
+			{Size: "g2.2xlarge", Region: "us-west-1", Price: 0.702, CPU: 8,
+				RAM: 15, GPU: 1, EphemeralDisk: 60,
+				EphemeralDiskType: "SSD", Family: "g2",
+				PreviousGeneration: true, Network: 1},
+			{Size: "m4.large", Region: "us-west-1", Price: 0.117, CPU: 2,
+				RAM: 8, Family: "m4", Network: 0.5},
+		},
+		db.DigitalOcean: {{Size: "1gb", Price: 0.007, CPU: 1, RAM: 1}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `{
+	"Amazon": [
+		{"Size":"g2.2xlarge","Region":"us-west-1","Price":0.702,"CPU":8,` +
+		`"RAM":15,"GPU":1,"EphemeralDisk":60,"EphemeralDiskType":"SSD",` +
+		`"Family":"g2","PreviousGeneration":true,"Network":1},
+		{"Size":"m4.large","Region":"us-west-1","Price":0.117,"CPU":2,` +
+		`"RAM":8,"GPU":0,"EphemeralDisk":0,"EphemeralDiskType":"",` +
+		`"Family":"m4","PreviousGeneration":false,"Network":0.5}
+	],
+	"DigitalOcean": [
+		{"Size":"1gb","Region":"","Price":0.007,"CPU":1,"RAM":1,"GPU":0,` +
+		`"EphemeralDisk":0,"EphemeralDiskType":"","Family":"",` +
+		`"PreviousGeneration":false,"Network":0}
+	]
+}
+`
+	if buf.String() != exp {
+		t.Errorf("\nGot: %s\nExp: %s", buf.String(), exp)
+	}
+
+	parsed, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, c) {
+		t.Errorf("expected %v, got %v", c, parsed)
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "quilt", "catalog.json")
+	f := NewFile(path)
+
+	// Without a file, the bundled catalog is used.
+	bundled, _ := Bundled.Catalog()
+	c, err := f.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, bundled) {
+		t.Error("expected the bundled catalog")
+	}
+
+	exp := Catalog{db.Amazon: {{Size: "m4.large", Price: 0.1}}}
+	if err := Save(path, exp); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, err = f.Catalog(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, exp) {
+		t.Errorf("expected %v, got %v", exp, c)
+	}
+
+	// The file is only parsed again once it changes.
+	oldTime := time.Now().Add(-time.Minute)
+	os.Chtimes(path, oldTime, oldTime)
+	f.Catalog()
+	f.catalog[db.Amazon][0].Price = 1
+
+	if c, _ = f.Catalog(); c[db.Amazon][0].Price != 1 {
+		t.Error("expected the cached catalog")
+	}
+
+	exp = Catalog{db.Amazon: {{Size: "m4.large", Price: 0.2}}}
+	if err := Save(path, exp); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, _ = f.Catalog(); !reflect.DeepEqual(c, exp) {
+		t.Errorf("expected %v, got %v", exp, c)
+	}
+
+	// A broken file is an error, rather than silently ignored.
+	ioutil.WriteFile(path, []byte("{"), 0644)
+	os.Chtimes(path, oldTime, oldTime.Add(time.Second))
+	if _, err := f.Catalog(); err == nil {
+		t.Error("expected an error parsing a broken catalog")
+	}
+}
+
+func TestGet(t *testing.T) {
+	defer func(old Source) { Default = old }(Default)
+
+	exp := []Description{{Size: "m4.large"}}
+	Default = fakeSource{catalog: Catalog{db.Amazon: exp}}
+	if res := Get(db.Amazon); !reflect.DeepEqual(res, exp) {
+		t.Errorf("expected %v, got %v", exp, res)
+	}
+
+	// If the catalog can't be read, the bundled one is used instead.
+	bundled, _ := Bundled.Catalog()
+	Default = fakeSource{err: os.ErrPermission}
+	res := Get(db.Google)
+	if !reflect.DeepEqual(res, bundled[db.Google]) {
+		t.Errorf("expected the bundled Google sizes, got %v", res)
+	}
+}
+
+func TestInFamilies(t *testing.T) {
+	families := []string{"m4", "n1"}
+	for family, exp := range map[string]bool{
+		"m4":         true,
+		"m4d":        false,
+		"n1-highmem": true,
+		"c4":         false,
+		"":           false,
+	} {
+		if res := InFamilies(family, families); res != exp {
+			t.Errorf("%q: expected %v, got %v", family, exp, res)
+		}
+	}
+}
+
+func TestInRegion(t *testing.T) {
+	descriptions := []Description{
+		{Size: "a", Region: "us-east1"},
+		{Size: "b", Region: "us-east1-b"},
+		{Size: "c", Region: "us-east10"},
+		{Size: "d"},
+	}
+
+	check := func(region string, exp []Description) {
+		res := InRegion(descriptions, region)
+		if !reflect.DeepEqual(res, exp) {
+			t.Errorf("%s: expected %v, got %v", region, exp, res)
+		}
+	}
+
+	check("us-east1", []Description{descriptions[0], descriptions[3]})
+	check("us-east1-b", []Description{descriptions[0], descriptions[1],
+		descriptions[3]})
+	check("europe-west1", []Description{descriptions[3]})
+
+	descriptions = descriptions[:3]
+	check("europe-west1", descriptions)
+}
+
+type fakeSource struct {
+	catalog Catalog
+	err     error
+}
+
+func (s fakeSource) Catalog() (Catalog, error) {
+	return s.catalog, s.err
+}
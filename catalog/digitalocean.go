@@ -0,0 +1,55 @@
+package catalog
+
+import (
+	"strings"
+
+	"github.com/NetSys/quilt/cluster/provider/digitalocean"
+	"github.com/NetSys/quilt/db"
+)
+
+// DigitalOcean is a Source that reads the droplet sizes, and their prices, from
+// the DigitalOcean API.
+type DigitalOcean struct {
+	Client digitalocean.Client
+}
+
+// Catalog fetches the descriptions of the droplet sizes available in each region.
+func (do DigitalOcean) Catalog() (Catalog, error) {
+	sizes, err := do.Client.ListSizes()
+	if err != nil {
+		return nil, err
+	}
+
+	var descriptions []Description
+	for _, size := range sizes {
+		if !size.Available {
+			continue
+		}
+
+		for _, region := range size.Regions {
+			descriptions = append(descriptions, Description{
+				Size:   size.Slug,
+				Region: region,
+				Price:  size.PriceHourly,
+				CPU:    size.VCPUs,
+				RAM:    float64(size.Memory) / 1024,
+
+				// Droplets are stored on local SSDs.
+				EphemeralDisk:     size.Disk,
+				EphemeralDiskType: "SSD",
+
+				Family: doFamily(size.Slug),
+			})
+		}
+	}
+	return Catalog{db.DigitalOcean: descriptions}, nil
+}
+
+// doFamily returns the family of the size `slug`, such as "c" for the CPU
+// optimized "c-2".  The original sizes, such as "2gb", have no family.
+func doFamily(slug string) string {
+	if i := strings.Index(slug, "-"); i > 0 {
+		return slug[:i]
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package catalog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/NetSys/quilt/cluster/provider/digitalocean"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestDigitalOcean(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"sizes": [{"slug": "2gb", "memory": 2048, `+
+				`"vcpus": 2, "disk": 40, "price_hourly": 0.02976, `+
+				`"regions": ["nyc1", "sfo2"], "available": true}, `+
+				`{"slug": "c-2", "memory": 4096, "vcpus": 2, `+
+				`"disk": 25, "price_hourly": 0.06, "regions": ["nyc1"], `+
+				`"available": true}, `+
+				`{"slug": "old", "memory": 512, "vcpus": 1, `+
+				`"regions": ["nyc1"], "available": false}], "links": {}}`)
+		}))
+	defer server.Close()
+
+	client := digitalocean.Client{BaseURL: server.URL, HTTP: http.DefaultClient}
+	c, err := DigitalOcean{Client: client}.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Catalog{db.DigitalOcean: {
+		{Size: "2gb", Region: "nyc1", Price: 0.02976, CPU: 2, RAM: 2,
+			EphemeralDisk: 40, EphemeralDiskType: "SSD"},
+		{Size: "2gb", Region: "sfo2", Price: 0.02976, CPU: 2, RAM: 2,
+			EphemeralDisk: 40, EphemeralDiskType: "SSD"},
+		{Size: "c-2", Region: "nyc1", Price: 0.06, CPU: 2, RAM: 4,
+			EphemeralDisk: 25, EphemeralDiskType: "SSD", Family: "c"},
+	}}
+	if !reflect.DeepEqual(c, exp) {
+		t.Errorf("expected %v, got %v", exp, c)
+	}
+}
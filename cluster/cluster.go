@@ -1,6 +1,9 @@
 package cluster
 
 import (
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/NetSys/quilt/cluster/provider"
@@ -11,10 +14,19 @@ import (
 )
 
 var myIP = util.MyIP
-var sleep = time.Sleep
+var timeNow = time.Now
+
+// Machines that fail to boot or stop are retried after a delay that doubles with
+// each failure in a row, so that a provider that's down, or throttling our API
+// calls, isn't hammered while the rest of the cluster carries on.
+const (
+	minRetryDelay = 30 * time.Second
+	maxRetryDelay = 16 * time.Minute
+)
 
 // Store the providers in a variable so we can change it in the tests
-var allProviders = []db.Provider{db.Amazon, db.Google, db.Vagrant}
+var allProviders = []db.Provider{db.Amazon, db.Google, db.Vagrant,
+	db.DigitalOcean, db.Static, db.Local}
 
 type cluster struct {
 	conn    db.Conn
@@ -23,31 +35,65 @@ type cluster struct {
 
 	namespace string
 	providers map[db.Provider]provider.Provider
+
+	// The cloud machines that failed to stop.  Unlike machines that failed to
+	// boot, they have no database rows to hold their retries.
+	stopRetries map[cloudID]retry
+}
+
+// A cloudID identifies a cloud machine.
+type cloudID struct {
+	provider db.Provider
+	id       string
 }
 
-// Run continually checks 'conn' for cluster changes and recreates the cluster as
-// needed.
+type retry struct {
+	failures int
+	at       time.Time
+	region   string
+}
+
+// A cloudRegion is a region of a provider.  The empty region stands for all of the
+// provider's regions.
+type cloudRegion struct {
+	provider db.Provider
+	region   string
+}
+
+// regionSet holds the regions whose machines couldn't be listed.
+type regionSet map[cloudRegion]struct{}
+
+func (rs regionSet) contains(p db.Provider, region string) bool {
+	_, all := rs[cloudRegion{p, ""}]
+	_, one := rs[cloudRegion{p, region}]
+	return all || one
+}
+
+// Run continually checks 'conn' for cluster changes, and manages a cluster for each
+// namespace in the database.
 func Run(conn db.Conn) {
-	var clst *cluster
+	clusters := map[string]*cluster{}
 	for range conn.TriggerTick(60, db.ClusterTable).C {
-		var dbCluster db.Cluster
-		err := conn.Transact(func(db db.Database) error {
-			var err error
-			dbCluster, err = db.GetCluster()
-			return err
+		namespaces := map[string]struct{}{}
+		conn.Transact(func(view db.Database) error {
+			for _, dbCluster := range view.SelectFromCluster(nil) {
+				namespaces[dbCluster.Namespace] = struct{}{}
+			}
+			return nil
 		})
-		if err != nil {
-			continue
-		}
 
-		if clst == nil {
-			clst = newCluster(conn, dbCluster.Namespace)
+		for namespace := range namespaces {
+			if _, ok := clusters[namespace]; !ok {
+				clusters[namespace] = newCluster(conn, namespace)
+			}
 		}
 
-		if clst != nil && clst.namespace != dbCluster.Namespace {
-			clst.fm.stop()
-			clst.trigger.Stop()
-			clst = newCluster(conn, dbCluster.Namespace)
+		for namespace, clst := range clusters {
+			if _, ok := namespaces[namespace]; !ok {
+				clst.fm.stop()
+				clst.trigger.Stop()
+				delete(clusters, namespace)
+			}
 		}
 	}
 }
@@ -57,9 +103,10 @@ func newCluster(conn db.Conn, namespace string) *cluster {
 		conn: conn,
 		trigger: conn.TriggerTick(30, db.ClusterTable, db.MachineTable,
 			db.ACLTable),
-		fm:        createForeman(conn),
-		namespace: namespace,
-		providers: make(map[db.Provider]provider.Provider),
+		fm:          createForeman(conn, namespace),
+		namespace:   namespace,
+		providers:   make(map[db.Provider]provider.Provider),
+		stopRetries: make(map[cloudID]retry),
 	}
 
 	for _, p := range allProviders {
@@ -85,21 +132,38 @@ func (clst *cluster) listen() {
 	}
 }
 
-func (clst cluster) get() ([]provider.Machine, error) {
+// get lists the machines of each provider, and returns the regions that couldn't
+// be listed.  A provider or region that fails doesn't stop the others from being
+// listed.
+func (clst cluster) get() ([]provider.Machine, regionSet) {
 	var cloudMachines []provider.Machine
-	for _, p := range clst.providers {
+	unlisted := regionSet{}
+	for name, p := range clst.providers {
 		providerMachines, err := p.List()
+		if regionErr, ok := err.(provider.RegionError); ok {
+			for region := range regionErr {
+				unlisted[cloudRegion{name, region}] = struct{}{}
+			}
+		} else if err != nil {
+			unlisted[cloudRegion{name, ""}] = struct{}{}
+			providerMachines = nil
+		}
+
 		if err != nil {
-			return []provider.Machine{}, err
+			log.WithError(err).WithField("provider", name).Error(
+				"Failed to list machines.")
 		}
 		cloudMachines = append(cloudMachines, providerMachines...)
 	}
-	return cloudMachines, nil
+	return cloudMachines, unlisted
 }
 
-func (clst cluster) updateCloud(machines []provider.Machine, boot bool) {
+// updateCloud boots or stops `machines` depending on the value of `boot`, and
+// returns the error, if any, of each.
+func (clst cluster) updateCloud(machines []provider.Machine, boot bool) []error {
+	errs := make([]error, len(machines))
 	if len(machines) == 0 {
-		return
+		return errs
 	}
 
 	actionString := "halt"
@@ -110,34 +174,126 @@ func (clst cluster) updateCloud(machines []provider.Machine, boot bool) {
 	log.WithField("count", len(machines)).
 		Infof("Attempt to %s machines.", actionString)
 
-	noFailures := true
-	groupedMachines := provider.GroupBy(machines)
-	for p, providerMachines := range groupedMachines {
+	// The indices in `machines` of each provider's machines.
+	byProvider := make(map[db.Provider][]int)
+	for i, m := range machines {
+		byProvider[m.Provider] = append(byProvider[m.Provider], i)
+	}
+
+	for p, indices := range byProvider {
+		var providerMachines []provider.Machine
+		for _, i := range indices {
+			providerMachines = append(providerMachines, machines[i])
+		}
+
+		var providerErrs []error
 		providerInst, ok := clst.providers[p]
-		if !ok {
-			noFailures = false
-			log.Warnf("Provider %s is unavailable.", p)
-			continue
+		switch {
+		case !ok:
+			err := fmt.Errorf("provider %s is unavailable", p)
+			for range indices {
+				providerErrs = append(providerErrs, err)
+			}
+		case boot:
+			providerErrs = providerInst.Boot(providerMachines)
+		default:
+			providerErrs = providerInst.Stop(providerMachines)
 		}
-		var err error
-		if boot {
-			err = providerInst.Boot(providerMachines)
-		} else {
-			err = providerInst.Stop(providerMachines)
+
+		for j, i := range indices {
+			errs[i] = providerErrs[j]
 		}
+	}
+
+	failures := 0
+	for _, err := range errs {
 		if err != nil {
-			noFailures = false
-			log.WithError(err).
-				Warnf("Unable to %s machines on %s.", actionString, p)
+			failures++
 		}
 	}
 
-	if noFailures {
+	if failures == 0 {
 		log.Infof("Successfully %sed machines.", actionString)
 	} else {
-		log.Infof("Due to failures, sleeping for 1 minute")
-		sleep(60 * time.Second)
+		log.Warnf("Failed to %s %d of %d machines.", actionString, failures,
+			len(machines))
 	}
+	return errs
+}
+
+// boot boots `dbms`, and records in the database when those that failed will be
+// retried.
+func (clst cluster) boot(dbms []db.Machine) {
+	var machines []provider.Machine
+	for _, dbm := range dbms {
+		machines = append(machines, bootMachine(dbm))
+	}
+	errs := clst.updateCloud(machines, true)
+
+	clst.conn.Transact(func(view db.Database) error {
+		rows := map[int]db.Machine{}
+		for _, dbm := range view.SelectFromMachine(nil) {
+			rows[dbm.ID] = dbm
+		}
+
+		for i, dbm := range dbms {
+			// The machine may have been removed while it was booting.
+			dbm, ok := rows[dbm.ID]
+			if !ok {
+				continue
+			}
+
+			if errs[i] == nil {
+				dbm.BootError = ""
+				dbm.BootFailures = 0
+				dbm.RetryAt = time.Time{}
+			} else {
+				dbm.BootError = errs[i].Error()
+				dbm.BootFailures++
+				dbm.RetryAt = timeNow().Add(retryDelay(dbm.BootFailures))
+				log.WithError(errs[i]).WithField("machine", dbm).Warnf(
+					"Failed to boot machine, retrying at %s.",
+					dbm.RetryAt.Format("15:04:05"))
+			}
+			view.Commit(dbm)
+		}
+		return nil
+	})
+}
+
+// stop stops `machines`, and remembers when those that failed will be retried.
+func (clst cluster) stop(machines []provider.Machine) {
+	errs := clst.updateCloud(machines, false)
+	for i, m := range machines {
+		id := cloudID{m.Provider, m.ID}
+		if errs[i] == nil {
+			delete(clst.stopRetries, id)
+			continue
+		}
+
+		r := clst.stopRetries[id]
+		r.region = m.Region
+		r.failures++
+		r.at = timeNow().Add(retryDelay(r.failures))
+		clst.stopRetries[id] = r
+		log.WithError(errs[i]).WithField("machine", m).Warnf(
+			"Failed to stop machine, retrying at %s.",
+			r.at.Format("15:04:05"))
+	}
+}
+
+// retryDelay returns how long to wait before retrying a machine that has failed
+// `failures` times in a row.
+func retryDelay(failures int) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < failures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
 }
 
 func (clst cluster) sync() {
@@ -157,8 +313,8 @@ func (clst cluster) sync() {
 		if len(bootSet) == 0 && len(terminateSet) == 0 {
 			break
 		}
-		clst.updateCloud(bootSet, true)
-		clst.updateCloud(terminateSet, false)
+		clst.boot(bootSet)
+		clst.stop(terminateSet)
 	}
 
 	// ACLs must be processed after Quilt learns about what machines are in
@@ -169,8 +325,8 @@ func (clst cluster) sync() {
 	var appACLs []db.PortRange
 	var machines []db.Machine
 	clst.conn.Transact(func(view db.Database) error {
-		machines = view.SelectFromMachine(nil)
-		aclRow, _ := view.GetACL()
+		machines = view.SelectFromMachine(clst.inNamespace)
+		aclRow, _ := view.GetACL(clst.namespace)
 		adminACLs = aclRow.Admin
 		appACLs = aclRow.ApplicationPorts
 		return nil
@@ -178,25 +334,55 @@ func (clst cluster) sync() {
 	clst.syncACLs(adminACLs, appACLs, machines)
 }
 
-func (clst cluster) syncMachines() (bootSet, terminateSet []provider.Machine) {
-	cloudMachines, err := clst.get()
-	if err != nil {
-		log.WithError(err).Error("Failed to list machines.")
-		return
-	}
+// syncMachines returns the machines to boot and stop, leaving out those that
+// failed and aren't due to be retried yet.  The machines of the regions that
+// couldn't be listed are left as they are.
+func (clst cluster) syncMachines() (bootSet []db.Machine,
+	terminateSet []provider.Machine) {
+	cloudMachines, unlisted := clst.get()
 
+	now := timeNow()
+	var terminate []provider.Machine
 	clst.conn.Transact(func(view db.Database) error {
-		dbMachines := view.SelectFromMachine(nil)
+		dbMachines := view.SelectFromMachine(func(m db.Machine) bool {
+			return clst.inNamespace(m) &&
+				!unlisted.contains(m.Provider, m.Region)
+		})
 
 		var pairs []join.Pair
-		pairs, bootSet, terminateSet = syncDB(cloudMachines, dbMachines)
+		var boot, gone []db.Machine
+		pairs, boot, terminate, gone = syncDB(cloudMachines, dbMachines)
+		for _, dbm := range boot {
+			if !now.Before(dbm.RetryAt) {
+				bootSet = append(bootSet, dbm)
+			}
+		}
+
+		for _, dbm := range gone {
+			log.WithField("machine", dbm).Info("Draining machine is gone.")
+			view.Remove(dbm)
+		}
+
 		for _, pair := range pairs {
 			dbm := pair.L.(db.Machine)
 			m := pair.R.(provider.Machine)
 
+			if m.Draining && !dbm.Draining {
+				log.WithField("machine", dbm).Info(
+					"Machine will be reclaimed, draining it.")
+				replaceMachine(view, dbm)
+				dbm.Draining = true
+			}
+
+			dbm.BootError = ""
+			dbm.BootFailures = 0
+			dbm.RetryAt = time.Time{}
+
 			dbm.CloudID = m.ID
 			dbm.PublicIP = m.PublicIP
 			dbm.PrivateIP = m.PrivateIP
+			dbm.Bid = m.Bid
+			dbm.Price = m.Price
 
 			// If we overwrite the machine's size before the machine
 			// has fully booted, the Stitch will flip it back
@@ -214,9 +400,52 @@ func (clst cluster) syncMachines() (bootSet, terminateSet []provider.Machine) {
 		return nil
 	})
 
+	live := map[cloudID]struct{}{}
+	for _, m := range cloudMachines {
+		live[cloudID{m.Provider, m.ID}] = struct{}{}
+	}
+
+	// Forget the machines that have gone since they failed to stop.
+	for id, r := range clst.stopRetries {
+		_, ok := live[id]
+		if !ok && !unlisted.contains(id.provider, r.region) {
+			delete(clst.stopRetries, id)
+		}
+	}
+
+	for _, m := range terminate {
+		r, ok := clst.stopRetries[cloudID{m.Provider, m.ID}]
+		if !ok || !now.Before(r.at) {
+			terminateSet = append(terminateSet, m)
+		}
+	}
 	return bootSet, terminateSet
 }
 
+// replaceMachine inserts a machine like `dbm` for the cluster to boot in its place.
+func replaceMachine(view db.Database, dbm db.Machine) {
+	replacement := view.InsertMachine()
+	replacement.Namespace = dbm.Namespace
+	replacement.Role = dbm.Role
+	replacement.Provider = dbm.Provider
+	replacement.Region = dbm.Region
+	replacement.Size = dbm.Size
+	replacement.DiskSize = dbm.DiskSize
+	replacement.Market = dbm.Market
+	replacement.MaxPrice = dbm.MaxPrice
+	replacement.Image = dbm.Image
+	replacement.BootScriptExtra = dbm.BootScriptExtra
+	replacement.Host = dbm.Host
+	replacement.SSHKeys = dbm.SSHKeys
+	replacement.Protected = dbm.Protected
+	view.Commit(replacement)
+}
+
+// inNamespace returns whether `m` belongs to this cluster.
+func (clst cluster) inNamespace(m db.Machine) bool {
+	return m.Namespace == clst.namespace
+}
+
 func (clst cluster) syncACLs(adminACLs []string, appACLs []db.PortRange,
 	machines []db.Machine) {
 
@@ -237,8 +466,17 @@ func (clst cluster) syncACLs(adminACLs []string, appACLs []db.PortRange,
 		})
 	}
 	for _, appACL := range appACLs {
+		cidrIP := "0.0.0.0/0"
+		if appACL.CidrIP != "" {
+			var err error
+			cidrIP, err = canonicalCIDR(appACL.CidrIP)
+			if err != nil {
+				log.WithError(err).Warn("Invalid application ACL.")
+				continue
+			}
+		}
 		acls = append(acls, provider.ACL{
-			CidrIP:  "0.0.0.0/0",
+			CidrIP:  cidrIP,
 			MinPort: appACL.MinPort,
 			MaxPort: appACL.MaxPort,
 		})
@@ -272,8 +510,27 @@ func (clst cluster) syncACLs(adminACLs []string, appACLs []db.PortRange,
 	}
 }
 
+// canonicalCIDR returns the network `cidr` describes, such as "1.2.3.0/24" for
+// "1.2.3.4/24", so that the providers see the same ACL however it was written.  A
+// bare IP address is the network of just that address.
+func canonicalCIDR(cidr string) (string, error) {
+	if !strings.Contains(cidr, "/") {
+		cidr += "/32"
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+	return network.String(), nil
+}
+
+// syncDB pairs the database machines with the cloud machines.  The unpaired cloud
+// machines must be terminated, and the unpaired database machines booted, except
+// for draining machines, which are gone and must be removed from the database.
 func syncDB(cloudMachines []provider.Machine, dbMachines []db.Machine) (
-	pairs []join.Pair, bootSet []provider.Machine, terminateSet []provider.Machine) {
+	pairs []join.Pair, bootSet []db.Machine, terminateSet []provider.Machine,
+	gone []db.Machine) {
 	scoreFun := func(left, right interface{}) int {
 		dbm := left.(db.Machine)
 		m := right.(provider.Machine)
@@ -281,12 +538,26 @@ func syncDB(cloudMachines []provider.Machine, dbMachines []db.Machine) (
 		switch {
 		case dbm.Provider != m.Provider:
 			return -1
+		case (dbm.Draining || m.Draining) && dbm.CloudID != m.ID:
+			// A draining machine's replacement is booted separately, so
+			// neither may take the other's place.
+			return -1
 		case m.Region != "" && dbm.Region != m.Region:
 			return -1
 		case m.Size != "" && dbm.Size != m.Size:
 			return -1
 		case m.DiskSize != 0 && dbm.DiskSize != m.DiskSize:
 			return -1
+		case dbm.Market != m.Market:
+			return -1
+		case dbm.MaxPrice != 0 && m.Bid != 0 && dbm.MaxPrice != m.Bid:
+			return -1
+		case dbm.Image != m.Image:
+			return -1
+		case provider.BootScriptHash(dbm.BootScriptExtra) != m.BootScriptHash:
+			return -1
+		case dbm.Host != "" && dbm.Host != m.Host:
+			return -1
 		case dbm.CloudID == m.ID:
 			return 0
 		case dbm.PublicIP == m.PublicIP:
@@ -307,13 +578,30 @@ func syncDB(cloudMachines []provider.Machine, dbMachines []db.Machine) (
 
 	for _, dbm := range dbmIface {
 		m := dbm.(db.Machine)
-		bootSet = append(bootSet, provider.Machine{
-			Size:     m.Size,
-			Provider: m.Provider,
-			Region:   m.Region,
-			DiskSize: m.DiskSize,
-			SSHKeys:  m.SSHKeys})
+		if m.Draining {
+			gone = append(gone, m)
+			continue
+		}
+
+		bootSet = append(bootSet, m)
 	}
 
-	return pairs, bootSet, terminateSet
+	return pairs, bootSet, terminateSet, gone
+}
+
+// bootMachine returns the cloud machine to boot for `dbm`.
+func bootMachine(dbm db.Machine) provider.Machine {
+	return provider.Machine{
+		Size:     dbm.Size,
+		Provider: dbm.Provider,
+		Region:   dbm.Region,
+		DiskSize: dbm.DiskSize,
+		Market:   dbm.Market,
+		MaxPrice: dbm.MaxPrice,
+		Image:    dbm.Image,
+		SSHKeys:  dbm.SSHKeys,
+		Host:     dbm.Host,
+
+		BootScriptExtra: dbm.BootScriptExtra,
+	}
 }
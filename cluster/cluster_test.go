@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -35,6 +36,14 @@ type fakeProvider struct {
 	bootRequests []bootRequest
 	stopRequests []string
 	aclRequests  []provider.ACL
+
+	// If set, every boot or stop fails with these errors.
+	bootErr error
+	stopErr error
+
+	// If set, listing fails with this error.  A RegionError only hides the
+	// machines of its regions.
+	listErr error
 }
 
 func newFakeProvider(cloudConfig string) *fakeProvider {
@@ -51,31 +60,55 @@ func (p *fakeProvider) clearLogs() {
 }
 
 func (p *fakeProvider) List() ([]provider.Machine, error) {
+	regionErr, partial := p.listErr.(provider.RegionError)
+	if p.listErr != nil && !partial {
+		return nil, p.listErr
+	}
+
 	var machines []provider.Machine
 	for _, machine := range p.machines {
-		machines = append(machines, machine)
+		if _, ok := regionErr[machine.Region]; !ok {
+			machines = append(machines, machine)
+		}
 	}
-	return machines, nil
+	return machines, p.listErr
 }
 
-func (p *fakeProvider) Boot(bootSet []provider.Machine) error {
+func (p *fakeProvider) Boot(bootSet []provider.Machine) []error {
+	if p.bootErr != nil {
+		errs := make([]error, len(bootSet))
+		for i := range errs {
+			errs[i] = p.bootErr
+		}
+		return errs
+	}
+
 	for _, bootSet := range bootSet {
 		p.idCounter++
 		bootSet.ID = string(p.idCounter)
+
+		// Like the real providers, only the hash of the boot script is listed.
+		bootSet.BootScriptHash = provider.BootScriptHash(bootSet.BootScriptExtra)
+		bootSet.BootScriptExtra = ""
 		p.machines[string(p.idCounter)] = bootSet
 		p.bootRequests = append(p.bootRequests, bootRequest{size: bootSet.Size,
 			cloudConfig: p.cloudConfig})
 	}
 
-	return nil
+	return make([]error, len(bootSet))
 }
 
-func (p *fakeProvider) Stop(machines []provider.Machine) error {
-	for _, machine := range machines {
-		delete(p.machines, machine.ID)
+func (p *fakeProvider) Stop(machines []provider.Machine) []error {
+	errs := make([]error, len(machines))
+	for i, machine := range machines {
 		p.stopRequests = append(p.stopRequests, machine.ID)
+		if p.stopErr != nil {
+			errs[i] = p.stopErr
+			continue
+		}
+		delete(p.machines, machine.ID)
 	}
-	return nil
+	return errs
 }
 
 func (p *fakeProvider) SetACLs(acls []provider.ACL) error {
@@ -85,22 +118,21 @@ func (p *fakeProvider) SetACLs(acls []provider.ACL) error {
 
 func (p *fakeProvider) Connect(namespace string) error { return nil }
 
-func (p *fakeProvider) ChooseSize(ram stitch.Range, cpu stitch.Range,
-	maxPrice float64) string {
-	return ""
+func (p *fakeProvider) ChooseSize(m stitch.Machine) (string, string) {
+	return "", ""
 }
 
 func newTestCluster() cluster {
 	conn := db.New()
 	clst := cluster{
-		conn:      conn,
-		providers: make(map[db.Provider]provider.Provider),
+		conn:        conn,
+		providers:   make(map[db.Provider]provider.Provider),
+		stopRetries: make(map[cloudID]retry),
 	}
 
 	clst.providers[FakeAmazon] = newFakeProvider(amazonCloudConfig)
 	clst.providers[FakeVagrant] = newFakeProvider(vagrantCloudConfig)
 
-	sleep = func(t time.Duration) {}
 	return clst
 }
 
@@ -123,7 +155,13 @@ func TestSyncDB(t *testing.T) {
 	checkSyncDB := func(cloudMachines []provider.Machine,
 		databaseMachines []db.Machine, expectedBoot,
 		expectedStop []provider.Machine) {
-		_, bootResult, stopResult := syncDB(cloudMachines, databaseMachines)
+		_, bootDBMs, stopResult, _ := syncDB(cloudMachines,
+			databaseMachines)
+
+		var bootResult []provider.Machine
+		for _, dbm := range bootDBMs {
+			bootResult = append(bootResult, bootMachine(dbm))
+		}
 		if !emptySlices(bootResult, expectedBoot) &&
 			!reflect.DeepEqual(bootResult, expectedBoot) {
 			t.Error(spew.Sprintf(
@@ -167,6 +205,40 @@ func TestSyncDB(t *testing.T) {
 	// Test partial stop
 	checkSyncDB([]provider.Machine{cmNoSize, cmLarge}, []db.Machine{}, noMachines,
 		[]provider.Machine{cmNoSize, cmLarge})
+
+	// Test replacing a machine in another market
+	dbOnDemand := db.Machine{Provider: FakeAmazon, Market: db.OnDemand}
+	cmOnDemand := provider.Machine{Provider: FakeAmazon, Market: db.OnDemand}
+	cmSpot := provider.Machine{Provider: FakeAmazon, Market: db.Spot}
+	checkSyncDB([]provider.Machine{cmSpot}, []db.Machine{dbOnDemand},
+		[]provider.Machine{cmOnDemand}, []provider.Machine{cmSpot})
+
+	// Test that a replacement doesn't take the place of the machine it replaces,
+	// whichever is paired first
+	dbDraining := db.Machine{Provider: FakeAmazon, CloudID: "1", Draining: true}
+	cmDraining := provider.Machine{Provider: FakeAmazon, ID: "1", Draining: true}
+	checkSyncDB([]provider.Machine{cmDraining},
+		[]db.Machine{dbNoSize, dbDraining}, []provider.Machine{cmNoSize},
+		noMachines)
+
+	// Test replacing a machine booted with another image or boot script
+	dbImage := db.Machine{Provider: FakeAmazon, Image: "ami-new"}
+	cmImage := provider.Machine{Provider: FakeAmazon, Image: "ami-new"}
+	checkSyncDB([]provider.Machine{cmNoSize}, []db.Machine{dbImage},
+		[]provider.Machine{cmImage}, []provider.Machine{cmNoSize})
+	checkSyncDB([]provider.Machine{cmImage}, []db.Machine{dbNoSize},
+		[]provider.Machine{cmNoSize}, []provider.Machine{cmImage})
+
+	dbScript := db.Machine{Provider: FakeAmazon, BootScriptExtra: "echo new"}
+	cmScript := provider.Machine{Provider: FakeAmazon,
+		BootScriptHash: provider.BootScriptHash("echo old")}
+	checkSyncDB([]provider.Machine{cmScript}, []db.Machine{dbScript},
+		[]provider.Machine{{Provider: FakeAmazon, BootScriptExtra: "echo new"}},
+		[]provider.Machine{cmScript})
+
+	cmScript.BootScriptHash = provider.BootScriptHash("echo new")
+	checkSyncDB([]provider.Machine{cmScript}, []db.Machine{dbScript},
+		noMachines, noMachines)
 }
 
 func TestSync(t *testing.T) {
@@ -266,6 +338,39 @@ func TestSync(t *testing.T) {
 	})
 	checkSync(clst, FakeAmazon, []bootRequest{amazonXLargeBoot},
 		[]string{toRemove.CloudID})
+
+	// Test replacing a machine whose boot script changed.  The machine booted
+	// with the old script doesn't pair with the new row.
+	clst.conn.Transact(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.Role = db.Worker
+		m.Provider = FakeVagrant
+		m.Size = "vagrant.large"
+		m.BootScriptExtra = "echo old"
+		view.Commit(m)
+
+		return nil
+	})
+	checkSync(clst, FakeVagrant, []bootRequest{vagrantLargeBoot}, noStops)
+	checkSync(clst, FakeVagrant, noBoots, noStops)
+
+	clst.conn.Transact(func(view db.Database) error {
+		toRemove = view.SelectFromMachine(func(m db.Machine) bool {
+			return m.BootScriptExtra == "echo old"
+		})[0]
+		view.Remove(toRemove)
+
+		m := view.InsertMachine()
+		m.Role = db.Worker
+		m.Provider = FakeVagrant
+		m.Size = "vagrant.large"
+		m.BootScriptExtra = "echo new"
+		view.Commit(m)
+
+		return nil
+	})
+	checkSync(clst, FakeVagrant, []bootRequest{vagrantLargeBoot},
+		[]string{toRemove.CloudID})
 }
 
 func TestACLs(t *testing.T) {
@@ -280,6 +385,21 @@ func TestACLs(t *testing.T) {
 				MinPort: 80,
 				MaxPort: 80,
 			},
+			{
+				MinPort: 8000,
+				MaxPort: 8080,
+				CidrIP:  "1.2.3.4/24",
+			},
+			{
+				MinPort: 22,
+				MaxPort: 22,
+				CidrIP:  "4.3.2.1",
+			},
+			{
+				MinPort: 443,
+				MaxPort: 443,
+				CidrIP:  "invalid",
+			},
 		},
 		[]db.Machine{
 			{
@@ -306,6 +426,16 @@ func TestACLs(t *testing.T) {
 			MinPort: 80,
 			MaxPort: 80,
 		},
+		{
+			CidrIP:  "1.2.3.0/24",
+			MinPort: 8000,
+			MaxPort: 8080,
+		},
+		{
+			CidrIP:  "4.3.2.1/32",
+			MinPort: 22,
+			MaxPort: 22,
+		},
 		{
 			CidrIP:  "8.8.8.8/32",
 			MinPort: 1,
@@ -322,3 +452,245 @@ func TestACLs(t *testing.T) {
 func emptySlices(slice1 interface{}, slice2 interface{}) bool {
 	return reflect.ValueOf(slice1).Len() == 0 && reflect.ValueOf(slice2).Len() == 0
 }
+
+func TestSyncNamespace(t *testing.T) {
+	clst := newTestCluster()
+	clst.namespace = "ns"
+	clst.conn.Transact(func(view db.Database) error {
+		for _, namespace := range []string{"ns", "other"} {
+			m := view.InsertMachine()
+			m.Namespace = namespace
+			m.Role = db.Master
+			m.Provider = FakeAmazon
+			m.Size = "m4.large"
+			view.Commit(m)
+		}
+		return nil
+	})
+
+	clst.sync()
+	providerInst := clst.providers[FakeAmazon].(*fakeProvider)
+	exp := []bootRequest{{size: "m4.large", cloudConfig: amazonCloudConfig}}
+	if !reflect.DeepEqual(providerInst.bootRequests, exp) {
+		t.Errorf("Expected only the machine in ns to boot, but booted %v",
+			providerInst.bootRequests)
+	}
+}
+
+func TestSyncDraining(t *testing.T) {
+	clst := newTestCluster()
+	clst.conn.Transact(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.Role = db.Worker
+		m.Provider = FakeAmazon
+		m.Size = "m4.large"
+		view.Commit(m)
+		return nil
+	})
+
+	clst.sync()
+	providerInst := clst.providers[FakeAmazon].(*fakeProvider)
+	providerInst.clearLogs()
+
+	// The cloud provider gives notice that it will reclaim the machine.
+	for id, m := range providerInst.machines {
+		m.Draining = true
+		providerInst.machines[id] = m
+	}
+
+	// The replacement is inserted, and then booted by the sync its insertion
+	// triggers.
+	clst.sync()
+	clst.sync()
+	var draining, replacements []db.Machine
+	clst.conn.Transact(func(view db.Database) error {
+		draining = view.SelectFromMachine(func(m db.Machine) bool {
+			return m.Draining
+		})
+		replacements = view.SelectFromMachine(func(m db.Machine) bool {
+			return !m.Draining
+		})
+		return nil
+	})
+	if len(draining) != 1 || len(replacements) != 1 ||
+		replacements[0].Role != db.Worker || replacements[0].Size != "m4.large" {
+		t.Errorf("Expected a draining machine and its replacement, got "+
+			"%v and %v", draining, replacements)
+	}
+
+	exp := []bootRequest{{size: "m4.large", cloudConfig: amazonCloudConfig}}
+	if !reflect.DeepEqual(providerInst.bootRequests, exp) ||
+		len(providerInst.stopRequests) != 0 {
+		t.Errorf("Expected only the replacement to boot, but booted %v and "+
+			"stopped %v", providerInst.bootRequests,
+			providerInst.stopRequests)
+	}
+
+	// Once the machine is reclaimed, it's removed rather than booted again.
+	delete(providerInst.machines, draining[0].CloudID)
+	providerInst.clearLogs()
+	clst.sync()
+
+	var machines []db.Machine
+	clst.conn.Transact(func(view db.Database) error {
+		machines = view.SelectFromMachine(nil)
+		return nil
+	})
+	if len(machines) != 1 || machines[0].ID != replacements[0].ID {
+		t.Errorf("Expected only the replacement to remain, got %v", machines)
+	}
+	if len(providerInst.bootRequests) != 0 {
+		t.Errorf("Expected no boots, got %v", providerInst.bootRequests)
+	}
+}
+
+func TestSyncRetries(t *testing.T) {
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	clst := newTestCluster()
+	amazon := clst.providers[FakeAmazon].(*fakeProvider)
+	vagrant := clst.providers[FakeVagrant].(*fakeProvider)
+	amazon.bootErr = errors.New("throttled")
+	clst.conn.Transact(func(view db.Database) error {
+		for _, p := range []db.Provider{FakeAmazon, FakeVagrant} {
+			m := view.InsertMachine()
+			m.Role = db.Worker
+			m.Provider = p
+			m.Size = "m4.large"
+			view.Commit(m)
+		}
+		return nil
+	})
+
+	getAmazon := func() db.Machine {
+		var dbm db.Machine
+		clst.conn.Transact(func(view db.Database) error {
+			dbm = view.SelectFromMachine(func(m db.Machine) bool {
+				return m.Provider == FakeAmazon
+			})[0]
+			return nil
+		})
+		return dbm
+	}
+
+	// A provider that fails doesn't hold up the others.
+	clst.sync()
+	if len(vagrant.bootRequests) != 1 {
+		t.Errorf("Expected the Vagrant machine to boot, got %v",
+			vagrant.bootRequests)
+	}
+
+	dbm := getAmazon()
+	if dbm.BootError != "throttled" || dbm.BootFailures != 1 ||
+		!dbm.RetryAt.Equal(now.Add(minRetryDelay)) {
+		t.Errorf("Expected the Amazon machine to be retried after %s, got %v",
+			minRetryDelay, dbm)
+	}
+
+	// The machine isn't retried until its delay has passed, which doubles with
+	// each failure.
+	clst.sync()
+	if dbm := getAmazon(); dbm.BootFailures != 1 {
+		t.Errorf("Expected the Amazon machine not to be retried, got %v", dbm)
+	}
+
+	now = now.Add(minRetryDelay)
+	clst.sync()
+	dbm = getAmazon()
+	if dbm.BootFailures != 2 || !dbm.RetryAt.Equal(now.Add(2*minRetryDelay)) {
+		t.Errorf("Expected the Amazon machine to be retried after %s, got %v",
+			2*minRetryDelay, dbm)
+	}
+
+	// Once the machine boots, its failures are forgotten.
+	amazon.bootErr = nil
+	now = now.Add(2 * minRetryDelay)
+	clst.sync()
+	dbm = getAmazon()
+	if len(amazon.bootRequests) != 1 || dbm.CloudID == "" ||
+		dbm.BootError != "" || dbm.BootFailures != 0 || !dbm.RetryAt.IsZero() {
+		t.Errorf("Expected the Amazon machine to boot, got %v", dbm)
+	}
+
+	// Machines that fail to stop are retried the same way.
+	clst.conn.Transact(func(view db.Database) error {
+		view.Remove(dbm)
+		return nil
+	})
+	amazon.stopErr = errors.New("throttled")
+	clst.sync()
+	clst.sync()
+	if len(amazon.stopRequests) != 1 {
+		t.Errorf("Expected one stop, got %v", amazon.stopRequests)
+	}
+
+	amazon.stopErr = nil
+	now = now.Add(minRetryDelay)
+	clst.sync()
+	if len(amazon.stopRequests) != 2 || len(amazon.machines) != 0 {
+		t.Errorf("Expected the machine to stop, got %v", amazon.machines)
+	}
+
+	if delay := retryDelay(100); delay != maxRetryDelay {
+		t.Errorf("Expected the delay to be capped at %s, got %s",
+			maxRetryDelay, delay)
+	}
+}
+
+func TestSyncListErrors(t *testing.T) {
+	clst := newTestCluster()
+	amazon := clst.providers[FakeAmazon].(*fakeProvider)
+	vagrant := clst.providers[FakeVagrant].(*fakeProvider)
+	clst.conn.Transact(func(view db.Database) error {
+		for _, region := range []string{"us-west-1", "us-west-2"} {
+			m := view.InsertMachine()
+			m.Role = db.Worker
+			m.Provider = FakeAmazon
+			m.Region = region
+			view.Commit(m)
+		}
+		return nil
+	})
+	clst.sync()
+	amazon.clearLogs()
+
+	// A provider that can't be listed doesn't hold up the others, and its
+	// machines aren't booted again.
+	amazon.listErr = errors.New("unreachable")
+	clst.conn.Transact(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.Role = db.Worker
+		m.Provider = FakeVagrant
+		view.Commit(m)
+		return nil
+	})
+	clst.sync()
+	if len(vagrant.bootRequests) != 1 || len(amazon.bootRequests) != 0 {
+		t.Errorf("Expected only the Vagrant machine to boot, got %v and %v",
+			vagrant.bootRequests, amazon.bootRequests)
+	}
+
+	// Nor does a region that can't be listed hold up the provider's other
+	// regions.
+	amazon.listErr = provider.RegionError{"us-west-2": errors.New("unreachable")}
+	clst.conn.Transact(func(view db.Database) error {
+		for _, m := range view.SelectFromMachine(nil) {
+			if m.Provider == FakeAmazon {
+				view.Remove(m)
+			}
+		}
+		return nil
+	})
+	clst.sync()
+	if len(amazon.stopRequests) != 1 || len(amazon.machines) != 1 {
+		t.Errorf("Expected only the us-west-1 machine to stop, got %v",
+			amazon.machines)
+	}
+	for _, m := range amazon.machines {
+		if m.Region != "us-west-2" {
+			t.Errorf("Expected the us-west-2 machine to be left, got %v", m)
+		}
+	}
+}
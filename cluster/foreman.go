@@ -30,7 +30,8 @@ type clientImpl struct {
 }
 
 type foreman struct {
-	conn db.Conn
+	conn      db.Conn
+	namespace string
 
 	minions map[string]*minion
 	spec    string
@@ -49,9 +50,10 @@ type minion struct {
 	mark bool /* Mark and sweep garbage collection. */
 }
 
-func createForeman(conn db.Conn) foreman {
+func createForeman(conn db.Conn, namespace string) foreman {
 	return foreman{
 		conn:      conn,
+		namespace: namespace,
 		minions:   make(map[string]*minion),
 		newClient: newClient,
 	}
@@ -65,9 +67,7 @@ func (fm *foreman) stop() {
 
 func (fm *foreman) init() {
 	fm.conn.Transact(func(view db.Database) error {
-		machines := view.SelectFromMachine(func(m db.Machine) bool {
-			return m.PublicIP != "" && m.PrivateIP != "" && m.CloudID != ""
-		})
+		machines := view.SelectFromMachine(fm.isBooted)
 
 		fm.updateMinionMap(machines)
 
@@ -89,15 +89,20 @@ func (fm *foreman) init() {
 	})
 }
 
+// isBooted returns whether `m` is a machine in the foreman's namespace that's
+// finished booting.
+func (fm *foreman) isBooted(m db.Machine) bool {
+	return m.Namespace == fm.namespace && m.PublicIP != "" && m.PrivateIP != "" &&
+		m.CloudID != ""
+}
+
 func (fm *foreman) runOnce() {
 	var machines []db.Machine
 	fm.conn.Transact(func(view db.Database) error {
-		machines = view.SelectFromMachine(func(m db.Machine) bool {
-			return m.PublicIP != "" && m.PrivateIP != "" && m.CloudID != ""
-		})
+		machines = view.SelectFromMachine(fm.isBooted)
 
 		fm.spec = ""
-		clst, _ := view.GetCluster()
+		clst, _ := view.GetCluster(fm.namespace)
 		fm.spec = clst.Spec
 		return nil
 	})
@@ -145,6 +150,7 @@ func (fm *foreman) runOnce() {
 			Provider:  string(m.machine.Provider),
 			Size:      m.machine.Size,
 			Region:    m.machine.Region,
+			Draining:  m.machine.Draining,
 		}
 
 		if newConfig == m.config {
@@ -227,7 +227,7 @@ func TestConfigConsistency(t *testing.T) {
 }
 
 func startTest() (foreman, *clients) {
-	fm := createForeman(db.New())
+	fm := createForeman(db.New(), "")
 	clients := &clients{make(map[string]*fakeClient), 0}
 	fm.newClient = func(ip string) (client, error) {
 		if fc, ok := clients.clients[ip]; ok {
@@ -242,7 +242,7 @@ func startTest() (foreman, *clients) {
 }
 
 func startTestWithRole(role pb.MinionConfig_Role) foreman {
-	fm := createForeman(db.New())
+	fm := createForeman(db.New(), "")
 	clientInst := &clients{make(map[string]*fakeClient), 0}
 	fm.newClient = func(ip string) (client, error) {
 		fc := &fakeClient{clientInst, ip, pb.MinionConfig{Role: role},
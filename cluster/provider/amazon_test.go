@@ -3,6 +3,7 @@ package provider
 
 import (
 	"encoding/base64"
+	"errors"
 	"reflect"
 	"sort"
 	"testing"
@@ -33,6 +34,9 @@ func TestList(t *testing.T) {
 			State: &ec2.InstanceState{
 				Name: aws.String(ec2.InstanceStateNameRunning),
 			},
+			Placement: &ec2.Placement{
+				AvailabilityZone: aws.String("us-west-1a"),
+			},
 		},
 		// A booted spot instance (with a lost spot tag).
 		{
@@ -43,6 +47,38 @@ func TestList(t *testing.T) {
 				Name: aws.String(ec2.InstanceStateNameRunning),
 			},
 		},
+		// A booted spot instance that Amazon is about to reclaim.
+		{
+			InstanceId:            aws.String("inst5"),
+			SpotInstanceRequestId: aws.String("spot5"),
+			State: &ec2.InstanceState{
+				Name: aws.String(ec2.InstanceStateNameRunning),
+			},
+		},
+		// A booted on-demand instance, with a custom image.
+		{
+			InstanceId:       aws.String("inst3"),
+			PublicIpAddress:  aws.String("publicIP3"),
+			PrivateIpAddress: aws.String("privateIP3"),
+			InstanceType:     aws.String("size3"),
+			State: &ec2.InstanceState{
+				Name: aws.String(ec2.InstanceStateNamePending),
+			},
+			Tags: []*ec2.Tag{
+				{
+					Key:   aws.String(imageKey),
+					Value: aws.String("ami-custom"),
+				},
+			},
+		},
+		// A terminated on-demand instance.
+		{
+			InstanceId:   aws.String("inst4"),
+			InstanceType: aws.String("size4"),
+			State: &ec2.InstanceState{
+				Name: aws.String(ec2.InstanceStateNameTerminated),
+			},
+		},
 	}
 	mockClient.On("DescribeInstances", mock.Anything).Return(
 		&ec2.DescribeInstancesOutput{
@@ -59,12 +95,17 @@ func TestList(t *testing.T) {
 				// A spot request with tags and a corresponding instance.
 				{
 					SpotInstanceRequestId: aws.String("spot1"),
+					SpotPrice:             aws.String("0.5"),
 					State: aws.String(ec2.SpotInstanceStateActive),
 					Tags: []*ec2.Tag{
 						{
 							Key:   aws.String(testNamespace),
 							Value: aws.String(""),
 						},
+						{
+							Key:   aws.String(bootScriptKey),
+							Value: aws.String("hash"),
+						},
 					},
 					InstanceId: aws.String("inst1"),
 				},
@@ -87,6 +128,16 @@ func TestList(t *testing.T) {
 						},
 					},
 				},
+				// A spot request whose instance will be interrupted.
+				{
+					SpotInstanceRequestId: aws.String("spot5"),
+					State: aws.String(ec2.SpotInstanceStateActive),
+					Status: &ec2.SpotInstanceStatus{
+						Code: aws.String(
+							"marked-for-termination"),
+					},
+					InstanceId: aws.String("inst5"),
+				},
 				// A spot request in another namespace.
 				{
 					SpotInstanceRequestId: aws.String("spot4"),
@@ -102,6 +153,23 @@ func TestList(t *testing.T) {
 		}, nil,
 	)
 
+	mockClient.On("DescribeSpotPriceHistory", mock.Anything).Return(
+		&ec2.DescribeSpotPriceHistoryOutput{
+			SpotPriceHistory: []*ec2.SpotPrice{
+				{
+					AvailabilityZone: aws.String("us-west-1a"),
+					InstanceType:     aws.String("size"),
+					SpotPrice:        aws.String("0.03"),
+				},
+				{
+					AvailabilityZone: aws.String("us-west-1b"),
+					InstanceType:     aws.String("size"),
+					SpotPrice:        aws.String("0.04"),
+				},
+			},
+		}, nil,
+	)
+
 	emptyClient := new(mocks.EC2Client)
 	emptyClient.On("DescribeInstances", mock.Anything).Return(
 		&ec2.DescribeInstancesOutput{}, nil,
@@ -129,21 +197,102 @@ func TestList(t *testing.T) {
 			PrivateIP: "privateIP",
 			Size:      "size",
 			Region:    "us-west-1",
+			Market:    db.Spot,
+			Bid:       0.5,
+			Price:     0.03,
+
+			BootScriptHash: "hash",
 		},
 		{
 			ID:       "spot2",
 			Provider: db.Amazon,
 			Region:   "us-west-1",
 			Size:     "size2",
+			Market:   db.Spot,
 		},
 		{
 			ID:       "spot3",
 			Provider: db.Amazon,
 			Region:   "us-west-1",
+			Market:   db.Spot,
+		},
+		{
+			ID:       "spot5",
+			Provider: db.Amazon,
+			Region:   "us-west-1",
+			Market:   db.Spot,
+			Draining: true,
+		},
+		{
+			ID:        "inst3",
+			Provider:  db.Amazon,
+			PublicIP:  "publicIP3",
+			PrivateIP: "privateIP3",
+			Size:      "size3",
+			Region:    "us-west-1",
+			Market:    db.OnDemand,
+			Image:     "ami-custom",
 		},
 	}, spots)
 }
 
+func TestListRegionError(t *testing.T) {
+	t.Parallel()
+
+	okClient := new(mocks.EC2Client)
+	okClient.On("DescribeInstances", mock.Anything).Return(
+		&ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{{
+				Instances: []*ec2.Instance{{
+					InstanceId: aws.String("inst1"),
+					State: &ec2.InstanceState{
+						Name: aws.String(
+							ec2.InstanceStateNameRunning),
+					},
+				}},
+			}},
+		}, nil,
+	)
+	okClient.On("DescribeSpotInstanceRequests", mock.Anything).Return(
+		&ec2.DescribeSpotInstanceRequestsOutput{}, nil,
+	)
+
+	errClient := new(mocks.EC2Client)
+	errClient.On("DescribeSpotInstanceRequests", mock.Anything).Return(
+		nil, errors.New("unavailable"),
+	)
+
+	amazonCluster := newAmazonCluster(func(region string) EC2Client {
+		switch region {
+		case "us-west-1":
+			return okClient
+		case "us-west-2":
+			return errClient
+		}
+
+		emptyClient := new(mocks.EC2Client)
+		emptyClient.On("DescribeInstances", mock.Anything).Return(
+			&ec2.DescribeInstancesOutput{}, nil,
+		)
+		emptyClient.On("DescribeSpotInstanceRequests", mock.Anything).Return(
+			&ec2.DescribeSpotInstanceRequestsOutput{}, nil,
+		)
+		return emptyClient
+	})
+	amazonCluster.namespace = testNamespace
+
+	// The machines of the other regions are listed despite the failure.
+	machines, err := amazonCluster.List()
+	assert.Equal(t, RegionError{"us-west-2": errors.New("unavailable")}, err)
+	assert.EqualError(t, err, "us-west-2: unavailable")
+	assert.Equal(t, []Machine{{
+		ID:       "inst1",
+		Provider: db.Amazon,
+		Region:   "us-west-1",
+		Market:   db.OnDemand,
+	}}, machines)
+}
+
 func TestNewACLs(t *testing.T) {
 	t.Parallel()
 
@@ -327,11 +476,32 @@ func TestBoot(t *testing.T) {
 			},
 		}, nil,
 	)
+	mockClient.On("RunInstances", mock.Anything).Return(
+		&ec2.Reservation{
+			Instances: []*ec2.Instance{
+				{
+					InstanceId: aws.String("inst1"),
+				},
+			},
+		}, nil,
+	)
 	mockClient.On("CreateTags", mock.Anything).Return(
 		&ec2.CreateTagsOutput{}, nil,
 	)
+	onDemandInst := &ec2.Instance{
+		InstanceId: aws.String("inst1"),
+		State: &ec2.InstanceState{
+			Name: aws.String(ec2.InstanceStateNamePending),
+		},
+	}
 	mockClient.On("DescribeInstances", mock.Anything).Return(
-		&ec2.DescribeInstancesOutput{}, nil,
+		&ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{
+					Instances: []*ec2.Instance{onDemandInst},
+				},
+			},
+		}, nil,
 	)
 	mockClient.On("DescribeSpotInstanceRequests", mock.Anything).Return(
 		&ec2.DescribeSpotInstanceRequestsOutput{
@@ -365,21 +535,43 @@ func TestBoot(t *testing.T) {
 	})
 	amazonCluster.namespace = testNamespace
 
-	err := amazonCluster.Boot([]Machine{
+	errs := amazonCluster.Boot([]Machine{
+		{
+			Region:   "us-west-1",
+			Size:     "m4.large",
+			DiskSize: 32,
+			Market:   db.Spot,
+		},
 		{
 			Region:   "us-west-1",
 			Size:     "m4.large",
 			DiskSize: 32,
+			Market:   db.Spot,
 		},
 		{
 			Region:   "us-west-1",
 			Size:     "m4.large",
 			DiskSize: 32,
+			Market:   db.OnDemand,
+			Image:    "ami-custom",
 		},
 	})
-	assert.Nil(t, err)
+	assert.Equal(t, []error{nil, nil, nil}, errs)
 
-	cfg := cloudConfigUbuntu(nil, "xenial")
+	cfg := cloudConfigUbuntu(Machine{}, "xenial")
+	mockClient.AssertCalled(t, "RunInstances",
+		&ec2.RunInstancesInput{
+			ImageId:      aws.String("ami-custom"),
+			InstanceType: aws.String("m4.large"),
+			UserData: aws.String(base64.StdEncoding.EncodeToString(
+				[]byte(cfg))),
+			SecurityGroupIds: aws.StringSlice([]string{"groupId"}),
+			BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+				blockDevice(32)},
+			MinCount: aws.Int64(1),
+			MaxCount: aws.Int64(1),
+		},
+	)
 	mockClient.AssertCalled(t, "RequestSpotInstances",
 		&ec2.RequestSpotInstancesInput{
 			SpotPrice: aws.String(spotPrice),
@@ -406,6 +598,17 @@ func TestBoot(t *testing.T) {
 			Resources: aws.StringSlice([]string{"spot1", "spot2"}),
 		},
 	)
+	mockClient.AssertCalled(t, "CreateTags",
+		&ec2.CreateTagsInput{
+			Tags: []*ec2.Tag{
+				{
+					Key:   aws.String(imageKey),
+					Value: aws.String("ami-custom"),
+				},
+			},
+			Resources: aws.StringSlice([]string{"inst1"}),
+		},
+	)
 }
 
 func TestStop(t *testing.T) {
@@ -451,17 +654,30 @@ func TestStop(t *testing.T) {
 		return mockClient
 	})
 
-	err := amazonCluster.Stop([]Machine{
+	errs := amazonCluster.Stop([]Machine{
 		{
 			Region: "us-west-1",
 			ID:     toStopIDs[0],
+			Market: db.Spot,
 		},
 		{
 			Region: "us-west-1",
 			ID:     toStopIDs[1],
+			Market: db.Spot,
+		},
+		{
+			Region: "us-west-1",
+			ID:     "inst2",
+			Market: db.OnDemand,
 		},
 	})
-	assert.Nil(t, err)
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+
+	mockClient.AssertCalled(t, "TerminateInstances",
+		&ec2.TerminateInstancesInput{
+			InstanceIds: aws.StringSlice([]string{"inst2"}),
+		},
+	)
 
 	mockClient.AssertCalled(t, "TerminateInstances",
 		&ec2.TerminateInstancesInput{
@@ -475,3 +691,129 @@ func TestStop(t *testing.T) {
 		},
 	)
 }
+
+func TestBootMaxPrice(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mocks.EC2Client)
+	mockClient.On("DescribeSecurityGroups", mock.Anything).Return(
+		&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{
+				{
+					GroupId: aws.String("groupId"),
+				},
+			},
+		}, nil,
+	)
+	mockClient.On("DescribeSpotPriceHistory", mock.Anything).Return(
+		&ec2.DescribeSpotPriceHistoryOutput{
+			SpotPriceHistory: []*ec2.SpotPrice{
+				{
+					AvailabilityZone: aws.String("us-west-1a"),
+					InstanceType:     aws.String("m4.large"),
+					SpotPrice:        aws.String("0.1"),
+				},
+				{
+					AvailabilityZone: aws.String("us-west-1b"),
+					InstanceType:     aws.String("m4.large"),
+					SpotPrice:        aws.String("0.07"),
+				},
+			},
+		}, nil,
+	)
+	mockClient.On("RequestSpotInstances", mock.Anything).Return(
+		&ec2.RequestSpotInstancesOutput{
+			SpotInstanceRequests: []*ec2.SpotInstanceRequest{
+				{
+					SpotInstanceRequestId: aws.String("spot1"),
+				},
+			},
+		}, nil,
+	)
+	mockClient.On("CreateTags", mock.Anything).Return(
+		&ec2.CreateTagsOutput{}, nil,
+	)
+	mockClient.On("DescribeInstances", mock.Anything).Return(
+		&ec2.DescribeInstancesOutput{}, nil,
+	)
+	mockClient.On("DescribeSpotInstanceRequests", mock.Anything).Return(
+		&ec2.DescribeSpotInstanceRequestsOutput{
+			SpotInstanceRequests: []*ec2.SpotInstanceRequest{
+				{
+					SpotInstanceRequestId: aws.String("spot1"),
+					State: aws.String(ec2.SpotInstanceStateOpen),
+					Tags: []*ec2.Tag{
+						{
+							Key:   aws.String(testNamespace),
+							Value: aws.String(""),
+						},
+					},
+				},
+			},
+		}, nil,
+	)
+
+	amazonCluster := newAmazonCluster(func(region string) EC2Client {
+		return mockClient
+	})
+	amazonCluster.namespace = testNamespace
+
+	// The cheapest zone is within the cap, so the cap is bid.
+	errs := amazonCluster.Boot([]Machine{{
+		Region:   "us-west-1",
+		Size:     "m4.large",
+		DiskSize: 32,
+		Market:   db.Spot,
+		MaxPrice: 0.08,
+	}})
+	assert.Equal(t, []error{nil}, errs)
+
+	cfg := cloudConfigUbuntu(Machine{}, "xenial")
+	mockClient.AssertCalled(t, "RequestSpotInstances",
+		&ec2.RequestSpotInstancesInput{
+			SpotPrice: aws.String("0.08"),
+			LaunchSpecification: &ec2.RequestSpotLaunchSpecification{
+				ImageId:      aws.String(amis["us-west-1"]),
+				InstanceType: aws.String("m4.large"),
+				UserData: aws.String(base64.StdEncoding.EncodeToString(
+					[]byte(cfg))),
+				SecurityGroupIds: aws.StringSlice([]string{"groupId"}),
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+					blockDevice(32)},
+			},
+			InstanceCount: aws.Int64(1),
+		},
+	)
+
+	// Caps below the market are refused, without holding up the other machines.
+	errs = amazonCluster.Boot([]Machine{
+		{
+			Region:   "us-west-1",
+			Size:     "m4.large",
+			DiskSize: 32,
+			Market:   db.Spot,
+			MaxPrice: 0.05,
+		},
+		{
+			Region:   "us-west-1",
+			Size:     "m4.large",
+			DiskSize: 32,
+			Market:   db.OnDemand,
+			MaxPrice: 0.08,
+		},
+		{
+			Region:   "us-west-1",
+			Size:     "m4.large",
+			DiskSize: 32,
+			Market:   db.Spot,
+			MaxPrice: 0.08,
+		},
+	})
+	assert.EqualError(t, errs[0], "max price $0.05 is below the current spot "+
+		"price $0.07 of m4.large in us-west-1")
+	assert.EqualError(t, errs[1], "max price $0.08 is below the current "+
+		"ondemand price $0.14 of m4.large in us-west-1")
+	assert.Nil(t, errs[2])
+	mockClient.AssertNumberOfCalls(t, "RequestSpotInstances", 2)
+	mockClient.AssertNotCalled(t, "RunInstances", mock.Anything)
+}
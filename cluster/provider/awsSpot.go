@@ -4,10 +4,12 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/NetSys/quilt/constants"
+	"github.com/NetSys/quilt/catalog"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/join"
 	"github.com/NetSys/quilt/stitch"
@@ -41,6 +43,9 @@ type EC2Client interface {
 	DescribeSpotInstanceRequests(*ec2.DescribeSpotInstanceRequestsInput) (
 		*ec2.DescribeSpotInstanceRequestsOutput, error)
 
+	DescribeSpotPriceHistory(*ec2.DescribeSpotPriceHistoryInput) (
+		*ec2.DescribeSpotPriceHistoryOutput, error)
+
 	DescribeVolumes(*ec2.DescribeVolumesInput) (
 		*ec2.DescribeVolumesOutput, error)
 
@@ -52,8 +57,11 @@ type EC2Client interface {
 
 	RequestSpotInstances(*ec2.RequestSpotInstancesInput) (
 		*ec2.RequestSpotInstancesOutput, error)
+
+	RunInstances(*ec2.RunInstancesInput) (*ec2.Reservation, error)
 }
 
+// The spot bid for machines without a MaxPrice.
 const spotPrice = "0.5"
 
 // Ubuntu 16.04, 64-bit hvm-ssd
@@ -96,17 +104,19 @@ type amazonCluster struct {
 }
 
 type awsID struct {
-	spotID string
+	// The spot request ID of a spot machine, or the instance ID of an
+	// on-demand machine.
+	id     string
 	region string
 }
 
-func getSpotIDs(ids []awsID) []string {
-	var spotIDs []string
+func getIDs(ids []awsID) []string {
+	var strs []string
 	for _, id := range ids {
-		spotIDs = append(spotIDs, id.spotID)
+		strs = append(strs, id.id)
 	}
 
-	return spotIDs
+	return strs
 }
 
 func groupByRegion(ids []awsID) map[string][]awsID {
@@ -125,7 +135,13 @@ func groupByRegion(ids []awsID) map[string][]awsID {
 func (clst *amazonCluster) Connect(namespace string) error {
 	clst.namespace = strings.ToLower(namespace)
 
-	if _, err := clst.List(); err != nil {
+	// A region that's down doesn't stop the others from being used.
+	_, err := clst.List()
+	if regionErr, ok := err.(RegionError); ok && len(regionErr) < len(amis) {
+		err = nil
+	}
+
+	if err != nil {
 		return errors.New("AWS failed to connect")
 	}
 	return nil
@@ -139,42 +155,132 @@ func (clst amazonCluster) getSession(region string) EC2Client {
 	return clst.sessions[region]
 }
 
-func (clst amazonCluster) Boot(bootSet []Machine) error {
+func (clst amazonCluster) Boot(bootSet []Machine) []error {
+	errs := make([]error, len(bootSet))
 	if len(bootSet) <= 0 {
-		return nil
+		return errs
 	}
 
 	type bootReq struct {
 		cfg      string
+		image    string
 		size     string
 		region   string
 		diskSize int
+		market   db.Market
+		maxPrice float64
 	}
 
-	bootReqMap := make(map[bootReq]int64) // From boot request to an instance count.
-	for _, m := range bootSet {
+	// From boot request to the indices in `bootSet` of its machines, and to what
+	// is recorded about how they're booted.
+	bootReqMap := make(map[bootReq][]int)
+	records := make(map[bootReq]map[string]string)
+	for i, m := range bootSet {
+		image := m.Image
+		if image == "" {
+			image = amis[m.Region]
+		}
+
 		br := bootReq{
-			cfg:      cloudConfigUbuntu(m.SSHKeys, "xenial"),
+			cfg:      cloudConfigUbuntu(m, "xenial"),
+			image:    image,
 			size:     m.Size,
 			region:   m.Region,
 			diskSize: m.DiskSize,
+			market:   m.Market,
+			maxPrice: m.MaxPrice,
+		}
+		bootReqMap[br] = append(bootReqMap[br], i)
+		records[br] = bootRecord(m)
+	}
+
+	// fail records `err` as the result of the machines of `br`, which are left
+	// out of the rest of the boot.  The other requests, perhaps in healthy
+	// regions, carry on.
+	fail := func(br bootReq, err error) {
+		for _, i := range bootReqMap[br] {
+			errs[i] = err
+		}
+		delete(bootReqMap, br)
+	}
+
+	// Refuse to boot a machine if its price cap is below the market, as spot
+	// requests would never be fulfilled, and on-demand instances would cost more
+	// than allowed.
+	for br := range bootReqMap {
+		if br.maxPrice == 0 {
+			continue
+		}
+
+		price, err := clst.currentPrice(br.region, br.size, br.market)
+		if err != nil {
+			fail(br, err)
+		} else if price > br.maxPrice {
+			fail(br, fmt.Errorf("max price $%g is below the current %s "+
+				"price $%g of %s in %s", br.maxPrice, br.market, price,
+				br.size, br.region))
 		}
-		bootReqMap[br] = bootReqMap[br] + 1
 	}
 
-	var awsIDs []awsID
-	for br, count := range bootReqMap {
+	var ids []awsID
+	for br, indices := range bootReqMap {
+		count := int64(len(indices))
 		session := clst.getSession(br.region)
 		groupID, _, err := clst.GetCreateSecurityGroup(session)
 		if err != nil {
-			return err
+			fail(br, err)
+			continue
 		}
 
 		cloudConfig64 := base64.StdEncoding.EncodeToString([]byte(br.cfg))
+		if br.market == db.OnDemand {
+			resp, err := session.RunInstances(&ec2.RunInstancesInput{
+				ImageId:          aws.String(br.image),
+				InstanceType:     aws.String(br.size),
+				UserData:         &cloudConfig64,
+				SecurityGroupIds: []*string{aws.String(groupID)},
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+					blockDevice(br.diskSize),
+				},
+				MinCount: &count,
+				MaxCount: &count,
+			})
+			if err != nil {
+				fail(br, err)
+				continue
+			}
+
+			var instIDs []string
+			for _, inst := range resp.Instances {
+				instIDs = append(instIDs, *inst.InstanceId)
+				ids = append(ids, awsID{
+					id:     *inst.InstanceId,
+					region: br.region})
+			}
+
+			// An instance without its record is replaced once it's
+			// listed, so failing to tag it fails the boot.
+			if len(records[br]) != 0 {
+				_, err := session.CreateTags(&ec2.CreateTagsInput{
+					Tags:      ec2Tags(records[br]),
+					Resources: aws.StringSlice(instIDs),
+				})
+				if err != nil {
+					fail(br, err)
+				}
+			}
+			continue
+		}
+
+		bid := spotPrice
+		if br.maxPrice != 0 {
+			bid = strconv.FormatFloat(br.maxPrice, 'f', -1, 64)
+		}
+
 		resp, err := session.RequestSpotInstances(&ec2.RequestSpotInstancesInput{
-			SpotPrice: aws.String(spotPrice),
+			SpotPrice: aws.String(bid),
 			LaunchSpecification: &ec2.RequestSpotLaunchSpecification{
-				ImageId:          aws.String(amis[br.region]),
+				ImageId:          aws.String(br.image),
 				InstanceType:     aws.String(br.size),
 				UserData:         &cloudConfig64,
 				SecurityGroupIds: []*string{aws.String(groupID)},
@@ -186,220 +292,480 @@ func (clst amazonCluster) Boot(bootSet []Machine) error {
 		})
 
 		if err != nil {
-			return err
+			fail(br, err)
+			continue
 		}
 
+		var spotIDs []awsID
 		for _, request := range resp.SpotInstanceRequests {
-			awsIDs = append(awsIDs, awsID{
-				spotID: *request.SpotInstanceRequestId,
+			spotIDs = append(spotIDs, awsID{
+				id:     *request.SpotInstanceRequestId,
 				region: br.region})
 		}
-	}
 
-	if err := clst.tagSpotRequests(awsIDs); err != nil {
-		return err
+		// On-demand instances are found by their security group, so only the
+		// spot requests need to be tagged with the namespace.
+		if err := clst.tagSpotRequests(spotIDs, records[br]); err != nil {
+			fail(br, err)
+			continue
+		}
+		ids = append(ids, spotIDs...)
 	}
 
-	return clst.wait(awsIDs, true)
+	waitErrs := clst.wait(ids, true)
+	for br := range bootReqMap {
+		if err := waitErrs[br.region]; err != nil {
+			fail(br, err)
+		}
+	}
+	return errs
 }
 
-func (clst amazonCluster) Stop(machines []Machine) error {
-	var awsIDs []awsID
-	for _, m := range machines {
-		awsIDs = append(awsIDs, awsID{
-			region: m.Region,
-			spotID: m.ID,
-		})
-	}
-	for region, ids := range groupByRegion(awsIDs) {
-		session := clst.getSession(region)
-		spotIDs := getSpotIDs(ids)
+func (clst amazonCluster) Stop(machines []Machine) []error {
+	errs := make([]error, len(machines))
 
-		spots, err := session.DescribeSpotInstanceRequests(
-			&ec2.DescribeSpotInstanceRequestsInput{
-				SpotInstanceRequestIds: aws.StringSlice(spotIDs),
-			})
-		if err != nil {
-			return err
+	// The indices in `machines` of the spot and on-demand machines in each region.
+	spots := make(map[string][]int)
+	insts := make(map[string][]int)
+	for i, m := range machines {
+		if m.Market == db.OnDemand {
+			insts[m.Region] = append(insts[m.Region], i)
+		} else {
+			spots[m.Region] = append(spots[m.Region], i)
 		}
+	}
 
-		instIds := []string{}
-		for _, spot := range spots.SpotInstanceRequests {
-			if spot.InstanceId != nil {
-				instIds = append(instIds, *spot.InstanceId)
-			}
+	machineIDs := func(indices []int) []string {
+		var ids []string
+		for _, i := range indices {
+			ids = append(ids, machines[i].ID)
 		}
+		return ids
+	}
 
-		if len(instIds) > 0 {
-			_, err = session.TerminateInstances(&ec2.TerminateInstancesInput{
-				InstanceIds: aws.StringSlice(instIds),
-			})
+	// A region that fails doesn't stop the others.
+	var stopping []int
+	record := func(indices []int, err error) {
+		for _, i := range indices {
 			if err != nil {
-				return err
+				errs[i] = err
+			} else {
+				stopping = append(stopping, i)
 			}
 		}
+	}
 
-		_, err = session.CancelSpotInstanceRequests(
-			&ec2.CancelSpotInstanceRequestsInput{
-				SpotInstanceRequestIds: aws.StringSlice(spotIDs),
-			})
-		if err != nil {
-			return err
+	for region, indices := range spots {
+		record(indices, clst.stopSpots(region, machineIDs(indices)))
+	}
+
+	for region, indices := range insts {
+		session := clst.getSession(region)
+		_, err := session.TerminateInstances(&ec2.TerminateInstancesInput{
+			InstanceIds: aws.StringSlice(machineIDs(indices)),
+		})
+		record(indices, err)
+	}
+
+	var ids []awsID
+	for _, i := range stopping {
+		ids = append(ids, awsID{id: machines[i].ID, region: machines[i].Region})
+	}
+	waitErrs := clst.wait(ids, false)
+	for _, i := range stopping {
+		if err := waitErrs[machines[i].Region]; err != nil {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+// stopSpots terminates the instances of the spot requests `spotIDs`, and cancels
+// the requests.
+func (clst amazonCluster) stopSpots(region string, spotIDs []string) error {
+	session := clst.getSession(region)
+	spots, err := session.DescribeSpotInstanceRequests(
+		&ec2.DescribeSpotInstanceRequestsInput{
+			SpotInstanceRequestIds: aws.StringSlice(spotIDs),
+		})
+	if err != nil {
+		return err
+	}
+
+	instIds := []string{}
+	for _, spot := range spots.SpotInstanceRequests {
+		if spot.InstanceId != nil {
+			instIds = append(instIds, *spot.InstanceId)
 		}
+	}
 
-		if err := clst.wait(ids, false); err != nil {
+	if len(instIds) > 0 {
+		_, err = session.TerminateInstances(&ec2.TerminateInstancesInput{
+			InstanceIds: aws.StringSlice(instIds),
+		})
+		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	_, err = session.CancelSpotInstanceRequests(
+		&ec2.CancelSpotInstanceRequestsInput{
+			SpotInstanceRequestIds: aws.StringSlice(spotIDs),
+		})
+	return err
 }
 
+// List returns the machines of each region.  A region that fails doesn't stop the
+// others from being listed.
 func (clst amazonCluster) List() ([]Machine, error) {
 	machines := []Machine{}
+	failed := RegionError{}
 	for region := range amis {
-		session := clst.getSession(region)
-
-		spots, err := session.DescribeSpotInstanceRequests(nil)
+		regionMachines, err := clst.listRegion(region)
 		if err != nil {
-			return nil, err
+			failed[region] = err
+			continue
 		}
+		machines = append(machines, regionMachines...)
+	}
 
-		insts, err := session.DescribeInstances(&ec2.DescribeInstancesInput{
-			Filters: []*ec2.Filter{
-				{
-					Name:   aws.String("instance.group-name"),
-					Values: []*string{aws.String(clst.namespace)},
-				},
+	if len(failed) != 0 {
+		return machines, failed
+	}
+	return machines, nil
+}
+
+func (clst amazonCluster) listRegion(region string) ([]Machine, error) {
+	machines := []Machine{}
+	session := clst.getSession(region)
+
+	spots, err := session.DescribeSpotInstanceRequests(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	insts, err := session.DescribeInstances(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance.group-name"),
+				Values: []*string{aws.String(clst.namespace)},
 			},
-		})
-		if err != nil {
-			return nil, err
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instMap := make(map[string]*ec2.Instance)
+	for _, res := range insts.Reservations {
+		for _, inst := range res.Instances {
+			instMap[*inst.InstanceId] = inst
 		}
+	}
+
+	// From the index of a spot machine in `machines` to the availability
+	// zone of its instance, so that its current price can be found.
+	zones := map[int]string{}
+	sizes := map[string]struct{}{}
+	for _, spot := range spots.SpotInstanceRequests {
+		if *spot.State != ec2.SpotInstanceStateActive &&
+			*spot.State != ec2.SpotInstanceStateOpen {
+			continue
+		}
+
+		var inst *ec2.Instance
+		if spot.InstanceId != nil {
+			inst = instMap[*spot.InstanceId]
+		}
+
+		// Due to a race condition in the AWS API, it's possible that
+		// spot requests might lose their Tags. If handled naively,
+		// those spot requests would technically be without a namespace,
+		// meaning the instances they create would be live forever as
+		// zombies.
+		//
+		// To mitigate this issue, we rely not only on the spot request
+		// tags, but additionally on the instance security group. If a
+		// spot request has a running instance in the appropriate
+		// security group, it is by definition in our namespace.
+		// Thus, we only check the tags for spot requests without
+		// running instances.
+		if inst == nil {
+			var isOurs bool
+			for _, tag := range spot.Tags {
+				ns := clst.namespace
+				if tag != nil && tag.Key != nil &&
+					*tag.Key == ns {
+					isOurs = true
+					break
+				}
+			}
 
-		instMap := make(map[string]*ec2.Instance)
-		for _, res := range insts.Reservations {
-			for _, inst := range res.Instances {
-				instMap[*inst.InstanceId] = inst
+			if !isOurs {
+				continue
 			}
 		}
 
-		for _, spot := range spots.SpotInstanceRequests {
-			if *spot.State != ec2.SpotInstanceStateActive &&
-				*spot.State != ec2.SpotInstanceStateOpen {
+		machine := Machine{
+			ID:       *spot.SpotInstanceRequestId,
+			Region:   region,
+			Provider: db.Amazon,
+			Market:   db.Spot,
+			Draining: interrupting(spot),
+			Bid:      parsePrice(spot.SpotPrice),
+		}
+		readBootRecord(&machine, tagRecord(spot.Tags))
+
+		if inst != nil {
+			if !isLive(inst) {
 				continue
 			}
 
-			var inst *ec2.Instance
-			if spot.InstanceId != nil {
-				inst = instMap[*spot.InstanceId]
+			if err := describeInstance(session, inst,
+				&machine); err != nil {
+				return nil, err
 			}
 
-			// Due to a race condition in the AWS API, it's possible that
-			// spot requests might lose their Tags. If handled naively,
-			// those spot requests would technically be without a namespace,
-			// meaning the instances they create would be live forever as
-			// zombies.
-			//
-			// To mitigate this issue, we rely not only on the spot request
-			// tags, but additionally on the instance security group. If a
-			// spot request has a running instance in the appropriate
-			// security group, it is by definition in our namespace.
-			// Thus, we only check the tags for spot requests without
-			// running instances.
-			if inst == nil {
-				var isOurs bool
-				for _, tag := range spot.Tags {
-					ns := clst.namespace
-					if tag != nil && tag.Key != nil &&
-						*tag.Key == ns {
-						isOurs = true
-						break
-					}
-				}
+			if inst.Placement != nil &&
+				inst.Placement.AvailabilityZone != nil &&
+				machine.Size != "" {
+				zones[len(machines)] =
+					*inst.Placement.AvailabilityZone
+				sizes[machine.Size] = struct{}{}
+			}
+		}
 
-				if !isOurs {
-					continue
-				}
+		machines = append(machines, machine)
+	}
+
+	if len(zones) != 0 {
+		prices, err := spotPrices(session, sizes)
+		if err != nil {
+			// The prices are informational, so the machines are
+			// still listed without them.
+			log.WithError(err).Warn("Failed to get spot prices.")
+		}
+		for i, zone := range zones {
+			machines[i].Price = prices[sizeZone{machines[i].Size,
+				zone}]
+		}
+	}
+
+	// On-demand instances are launched directly into the namespace's
+	// security group, so they're ours if they're in it.
+	for _, res := range insts.Reservations {
+		for _, inst := range res.Instances {
+			if inst.SpotInstanceRequestId != nil || !isLive(inst) {
+				continue
 			}
 
 			machine := Machine{
-				ID:       *spot.SpotInstanceRequestId,
+				ID:       *inst.InstanceId,
 				Region:   region,
 				Provider: db.Amazon,
+				Market:   db.OnDemand,
 			}
+			readBootRecord(&machine, tagRecord(inst.Tags))
+			err := describeInstance(session, inst, &machine)
+			if err != nil {
+				return nil, err
+			}
+			machines = append(machines, machine)
+		}
+	}
 
-			if inst != nil {
-				if *inst.State.Name != ec2.InstanceStateNamePending &&
-					*inst.State.Name != ec2.InstanceStateNameRunning {
-					continue
-				}
+	return machines, nil
+}
 
-				if inst.PublicIpAddress != nil {
-					machine.PublicIP = *inst.PublicIpAddress
-				}
+// interrupting returns whether Amazon has given notice that it will stop or
+// terminate the instance of `spot`.  The notice comes about two minutes ahead.
+func interrupting(spot *ec2.SpotInstanceRequest) bool {
+	return spot.Status != nil && spot.Status.Code != nil &&
+		strings.HasPrefix(*spot.Status.Code, "marked-for-")
+}
 
-				if inst.PrivateIpAddress != nil {
-					machine.PrivateIP = *inst.PrivateIpAddress
-				}
+// A sizeZone is an instance size in an availability zone, which together determine
+// the spot price.
+type sizeZone struct {
+	size, zone string
+}
 
-				if inst.InstanceType != nil {
-					machine.Size = *inst.InstanceType
-				}
+// spotPrices returns the current spot price of each of `sizes` in each availability
+// zone of the session's region.
+func spotPrices(session EC2Client, sizes map[string]struct{}) (
+	map[sizeZone]float64, error) {
 
-				if len(inst.BlockDeviceMappings) != 0 {
-					volumeID := inst.BlockDeviceMappings[0].
-						Ebs.VolumeId
-					filters := []*ec2.Filter{
-						{
-							Name: aws.String("volume-id"),
-							Values: []*string{
-								aws.String(*volumeID),
-							},
-						},
-					}
-
-					volumeInfo, err := session.DescribeVolumes(
-						&ec2.DescribeVolumesInput{
-							Filters: filters,
-						})
-					if err != nil {
-						return nil, err
-					}
-					if len(volumeInfo.Volumes) == 1 {
-						machine.DiskSize = int(
-							*volumeInfo.Volumes[0].Size)
-					}
-				}
+	var sizeList []string
+	for size := range sizes {
+		sizeList = append(sizeList, size)
+	}
+	sort.Strings(sizeList)
+
+	// A start time of now returns only the current prices.
+	resp, err := session.DescribeSpotPriceHistory(
+		&ec2.DescribeSpotPriceHistoryInput{
+			InstanceTypes:       aws.StringSlice(sizeList),
+			ProductDescriptions: []*string{aws.String("Linux/UNIX")},
+			StartTime:           aws.Time(time.Now()),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	prices := map[sizeZone]float64{}
+	for _, price := range resp.SpotPriceHistory {
+		if price.InstanceType == nil || price.AvailabilityZone == nil {
+			continue
+		}
+
+		key := sizeZone{*price.InstanceType, *price.AvailabilityZone}
+		prices[key] = parsePrice(price.SpotPrice)
+	}
+	return prices, nil
+}
+
+// currentPrice returns the current hourly price of a `size` machine in `region`, or
+// zero if it's unknown.  Spot requests are fulfilled in whichever availability zone
+// is cheapest, so the lowest spot price of the region is used.  On-demand prices
+// come from the catalog.
+func (clst amazonCluster) currentPrice(region, size string, market db.Market) (
+	float64, error) {
+
+	if market == db.OnDemand {
+		descs := catalog.InRegion(catalog.Get(db.Amazon), region)
+		for _, desc := range descs {
+			if desc.Size == size {
+				return desc.Price, nil
 			}
+		}
+		return 0, nil
+	}
 
-			machines = append(machines, machine)
+	prices, err := spotPrices(clst.getSession(region),
+		map[string]struct{}{size: {}})
+	if err != nil {
+		return 0, err
+	}
+
+	var lowest float64
+	for _, price := range prices {
+		if lowest == 0 || price < lowest {
+			lowest = price
+		}
+	}
+	return lowest, nil
+}
+
+// parsePrice parses a price reported by Amazon, or returns zero if there's none.
+func parsePrice(price *string) float64 {
+	if price == nil {
+		return 0
+	}
+
+	parsed, err := strconv.ParseFloat(*price, 64)
+	if err != nil {
+		log.WithError(err).Warnf("Failed to parse price %q.", *price)
+		return 0
+	}
+	return parsed
+}
+
+// isLive returns whether `inst` is booting or running.
+func isLive(inst *ec2.Instance) bool {
+	return *inst.State.Name == ec2.InstanceStateNamePending ||
+		*inst.State.Name == ec2.InstanceStateNameRunning
+}
+
+// describeInstance fills in the addresses, size, and disk size of `machine` from
+// the instance `inst`.
+func describeInstance(session EC2Client, inst *ec2.Instance, machine *Machine) error {
+	if inst.PublicIpAddress != nil {
+		machine.PublicIP = *inst.PublicIpAddress
+	}
+
+	if inst.PrivateIpAddress != nil {
+		machine.PrivateIP = *inst.PrivateIpAddress
+	}
+
+	if inst.InstanceType != nil {
+		machine.Size = *inst.InstanceType
+	}
+
+	if len(inst.BlockDeviceMappings) != 0 {
+		volumeID := inst.BlockDeviceMappings[0].Ebs.VolumeId
+		filters := []*ec2.Filter{
+			{
+				Name:   aws.String("volume-id"),
+				Values: []*string{aws.String(*volumeID)},
+			},
+		}
+
+		volumeInfo, err := session.DescribeVolumes(&ec2.DescribeVolumesInput{
+			Filters: filters,
+		})
+		if err != nil {
+			return err
+		}
+		if len(volumeInfo.Volumes) == 1 {
+			machine.DiskSize = int(*volumeInfo.Volumes[0].Size)
 		}
 	}
 
-	return machines, nil
+	return nil
+}
+
+func (clst *amazonCluster) ChooseSize(m stitch.Machine) (string, string) {
+	return pickBestSize(catalog.Get(db.Amazon), m)
 }
 
-func (clst *amazonCluster) ChooseSize(ram stitch.Range, cpu stitch.Range,
-	maxPrice float64) string {
-	return pickBestSize(constants.AwsDescriptions, ram, cpu, maxPrice)
+// ec2Tags returns the tags that hold `record`, sorted by key.
+func ec2Tags(record map[string]string) []*ec2.Tag {
+	var keys []string
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var tags []*ec2.Tag
+	for _, key := range keys {
+		tags = append(tags, &ec2.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(record[key]),
+		})
+	}
+	return tags
+}
+
+// tagRecord returns the record held by `tags`.
+func tagRecord(tags []*ec2.Tag) map[string]string {
+	record := map[string]string{}
+	for _, tag := range tags {
+		if tag != nil && tag.Key != nil && tag.Value != nil {
+			record[*tag.Key] = *tag.Value
+		}
+	}
+	return record
 }
 
-func (clst *amazonCluster) tagSpotRequests(awsIDs []awsID) error {
+// tagSpotRequests tags the spot requests `awsIDs` with the namespace, and with the
+// `record` of how they're booted.
+func (clst *amazonCluster) tagSpotRequests(awsIDs []awsID,
+	record map[string]string) error {
+
+	tags := append([]*ec2.Tag{{
+		Key:   aws.String(clst.namespace),
+		Value: aws.String(""),
+	}}, ec2Tags(record)...)
+
 OuterLoop:
 	for region, ids := range groupByRegion(awsIDs) {
 		session := clst.getSession(region)
-		spotIDs := getSpotIDs(ids)
+		spotIDs := getIDs(ids)
 
 		var err error
 		for i := 0; i < 30; i++ {
 			_, err = session.CreateTags(&ec2.CreateTagsInput{
-				Tags: []*ec2.Tag{
-					{
-						Key:   aws.String(clst.namespace),
-						Value: aws.String(""),
-					},
-				},
+				Tags:      tags,
 				Resources: aws.StringSlice(spotIDs),
 			})
 			if err == nil {
@@ -420,39 +786,56 @@ OuterLoop:
 	return nil
 }
 
-/* Wait for the spot request 'ids' to have booted or terminated depending on the value
- * of 'boot' */
-func (clst *amazonCluster) wait(awsIDs []awsID, boot bool) error {
-OuterLoop:
-	for i := 0; i < 100; i++ {
+/* Wait for the machines 'ids' to have booted or terminated depending on the value
+ * of 'boot'.  Each region waits separately, and the error of each that timed out is
+ * returned. */
+func (clst *amazonCluster) wait(awsIDs []awsID, boot bool) map[string]error {
+	pending := groupByRegion(awsIDs)
+	for i := 0; i < 100 && len(pending) != 0; i++ {
+		if i != 0 {
+			time.Sleep(10 * time.Second)
+		}
+
+		// The regions that couldn't be listed are checked again next time.
 		machines, err := clst.List()
-		if err != nil {
+		failed, _ := err.(RegionError)
+		if err != nil && failed == nil {
 			log.WithError(err).Warn("Failed to get machines.")
-			time.Sleep(10 * time.Second)
 			continue
+		} else if err != nil {
+			log.WithError(err).Warn("Failed to get machines of some regions.")
 		}
 
 		exists := make(map[awsID]struct{})
 		for _, inst := range machines {
 			id := awsID{
-				spotID: inst.ID,
+				id:     inst.ID,
 				region: inst.Region,
 			}
 
 			exists[id] = struct{}{}
 		}
 
-		for _, id := range awsIDs {
-			if _, ok := exists[id]; ok != boot {
-				time.Sleep(10 * time.Second)
-				continue OuterLoop
+	RegionLoop:
+		for region, ids := range pending {
+			if _, ok := failed[region]; ok {
+				continue
 			}
-		}
 
-		return nil
+			for _, id := range ids {
+				if _, ok := exists[id]; ok != boot {
+					continue RegionLoop
+				}
+			}
+			delete(pending, region)
+		}
 	}
 
-	return errors.New("timed out")
+	errs := map[string]error{}
+	for region := range pending {
+		errs[region] = errors.New("timed out")
+	}
+	return errs
 }
 
 func (clst *amazonCluster) GetCreateSecurityGroup(session EC2Client) (
@@ -617,7 +1000,19 @@ func syncACLs(desiredACLs []ACL, desiredGroupID string,
 		})
 	}
 
-	_, toAdd, rangesToRemove := join.HashJoin(ipPermSlice(desiredRangeRules),
+	// ICMP rules ignore ports, so ACLs that share a CIDR but differ in their
+	// port ranges result in duplicate ICMP permissions.
+	var uniqueRangeRules []*ec2.IpPermission
+	seen := make(map[interface{}]struct{})
+	for _, perm := range desiredRangeRules {
+		key := permToACLKey(perm)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			uniqueRangeRules = append(uniqueRangeRules, perm)
+		}
+	}
+
+	_, toAdd, rangesToRemove := join.HashJoin(ipPermSlice(uniqueRangeRules),
 		ipPermSlice(currRangeRules), permToACLKey, permToACLKey)
 	for _, intf := range toAdd {
 		rangesToAdd = append(rangesToAdd, intf.(*ec2.IpPermission))
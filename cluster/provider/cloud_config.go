@@ -29,6 +29,9 @@ initialize_ovs() {
 initialize_docker() {
 	mkdir -p /etc/systemd/system/docker.service.d
 
+	# Newer versions of docker only start the daemon with dockerd.
+	dockerd=$(command -v dockerd || echo "/usr/bin/docker daemon")
+
 	cat <<- EOF > /etc/systemd/system/docker.service.d/override.conf
 	[Unit]
 	Description=docker
@@ -36,7 +39,7 @@ initialize_docker() {
 	[Service]
 	# The below empty ExecStart deletes the official one installed by docker daemon.
 	ExecStart=
-	ExecStart=/usr/bin/docker daemon --bridge=none -H unix:///var/run/docker.sock
+	ExecStart=$dockerd --bridge=none -H unix:///var/run/docker.sock
 
 	[Install]
 	WantedBy=multi-user.target
@@ -68,6 +71,12 @@ initialize_minion() {
 }
 
 install_docker() {
+	# Images may come with docker installed.
+	if command -v docker > /dev/null; then
+		echo "Docker is already installed." >> /var/log/bootscript.log
+		return
+	fi
+
 	echo "deb https://apt.dockerproject.org/repo ubuntu-{{.UbuntuVersion}} main" > /etc/apt/sources.list.d/docker.list
 	apt-get update
 	apt-get install docker-engine=1.12.3-0~{{.UbuntuVersion}} -y --force-yes
@@ -77,6 +86,14 @@ install_docker() {
 setup_user() {
 	user=$1
 	ssh_keys=$2
+
+	# Images may come with the user, in which case only the keys are added.
+	if id -u $user > /dev/null 2>&1; then
+		install -d -o $user -m 700 /home/$user/.ssh
+		printf "$ssh_keys" >> /home/$user/.ssh/authorized_keys
+		return
+	fi
+
 	sudo groupadd $user
 	sudo useradd $user -s /bin/bash -g $user
 	sudo usermod -aG sudo $user
@@ -103,7 +120,15 @@ install_docker
 initialize_ovs
 initialize_docker
 initialize_minion
-
+{{if .BootScriptExtra}}
+# Run the machine's extra boot script.  It's written to a file first, so that it can
+# neither see nor exit this script.
+mkdir -p /var/lib/quilt
+cat << 'QUILT_BOOT_SCRIPT_EXTRA' > /var/lib/quilt/boot-script-extra
+{{.BootScriptExtra}}
+QUILT_BOOT_SCRIPT_EXTRA
+bash /var/lib/quilt/boot-script-extra >> /var/log/bootscript.log 2>&1
+{{end}}
 # Allow the user to use docker without sudo
 sudo usermod -aG docker quilt
 
@@ -120,18 +145,21 @@ echo -n "Completed Boot Script: " >> /var/log/bootscript.log
 date >> /var/log/bootscript.log
     `
 
-func cloudConfigUbuntu(keys []string, ubuntuVersion string) string {
+// cloudConfigUbuntu returns the boot script for `m`.
+func cloudConfigUbuntu(m Machine, ubuntuVersion string) string {
 	t := template.Must(template.New("cloudConfig").Parse(cloudConfigTemplate))
 
 	var cloudConfigBytes bytes.Buffer
 	err := t.Execute(&cloudConfigBytes, struct {
-		QuiltImage    string
-		UbuntuVersion string
-		SSHKeys       string
+		QuiltImage      string
+		UbuntuVersion   string
+		SSHKeys         string
+		BootScriptExtra string
 	}{
-		QuiltImage:    quiltImage,
-		UbuntuVersion: ubuntuVersion,
-		SSHKeys:       strings.Join(keys, "\n"),
+		QuiltImage:      quiltImage,
+		UbuntuVersion:   ubuntuVersion,
+		SSHKeys:         strings.Join(m.SSHKeys, "\n"),
+		BootScriptExtra: m.BootScriptExtra,
 	})
 	if err != nil {
 		panic(err)
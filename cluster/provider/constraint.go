@@ -1,23 +1,137 @@
 package provider
 
 import (
-	"github.com/NetSys/quilt/constants"
+	"fmt"
+	"strings"
+
+	"github.com/NetSys/quilt/catalog"
 	"github.com/NetSys/quilt/stitch"
 )
 
-// XXX: cache results?
-func pickBestSize(descriptions []constants.Description, ram stitch.Range,
-	cpu stitch.Range, maxPrice float64) string {
-	var best constants.Description
+type sizeCheck struct {
+	accepts func(catalog.Description) bool
+
+	// Why a size that isn't accepted doesn't fit, such as "no local SSD".
+	rejection string
+}
+
+// pickBestSize returns the cheapest size in `m.Region` that fits the constraints
+// of `m`, or the empty string if there isn't one, along with an explanation.  The
+// size in `m.Size`, if any, is returned instead as long as it fits, so that price
+// changes don't replace running machines.
+func pickBestSize(descriptions []catalog.Description, m stitch.Machine) (string,
+	string) {
+
+	checks := []sizeCheck{
+		{func(d catalog.Description) bool { return m.RAM.Accepts(d.RAM) },
+			"the wrong amount of RAM"},
+		{func(d catalog.Description) bool {
+			return m.CPU.Accepts(float64(d.CPU))
+		}, "the wrong number of CPUs"},
+		{func(d catalog.Description) bool {
+			return m.GPU.Accepts(float64(d.GPU))
+		}, "the wrong number of GPUs"},
+		{func(d catalog.Description) bool {
+			return !m.LocalSSD ||
+				(d.EphemeralDisk > 0 && d.EphemeralDiskType == "SSD")
+		}, "no local SSD"},
+		{func(d catalog.Description) bool { return d.Network >= m.MinNetwork },
+			"too little network bandwidth"},
+		{func(d catalog.Description) bool {
+			return len(m.Families) == 0 ||
+				catalog.InFamilies(d.Family, m.Families)
+		}, "a family that isn't allowed"},
+		{func(d catalog.Description) bool {
+			return !catalog.InFamilies(d.Family, m.ExcludeFamilies)
+		}, "an excluded family"},
+		{func(d catalog.Description) bool {
+			return m.MaxPrice == 0 || d.Price <= m.MaxPrice
+		}, fmt.Sprintf("a price above $%v", m.MaxPrice)},
+	}
+
+	descriptions = catalog.InRegion(descriptions, m.Region)
+	rejections := make([]int, len(checks))
+	var fits, previous []catalog.Description
 	for _, d := range descriptions {
-		if ram.Accepts(d.RAM) &&
-			cpu.Accepts(float64(d.CPU)) &&
-			(best.Size == "" || d.Price < best.Price) {
+		accepted := true
+		for i, check := range checks {
+			if !check.accepts(d) {
+				rejections[i]++
+				accepted = false
+				break
+			}
+		}
+
+		if accepted {
+			fits = append(fits, d)
+			if d.PreviousGeneration {
+				previous = append(previous, d)
+			}
+		}
+	}
+
+	var ruledOut []string
+	for i, n := range rejections {
+		if n > 0 {
+			ruledOut = append(ruledOut, fmt.Sprintf("%d for %s", n,
+				checks[i].rejection))
+		}
+	}
+
+	why := ""
+	if len(ruledOut) > 0 {
+		why = " (ruled out: " + strings.Join(ruledOut, ", ") + ")"
+	}
+
+	where := ""
+	if m.Region != "" {
+		where = " in " + m.Region
+	}
+
+	if len(fits) == 0 {
+		return "", fmt.Sprintf("none of the %d sizes%s fit%s",
+			len(descriptions), where, why)
+	}
+
+	for _, d := range fits {
+		if d.Size == m.Size {
+			return d.Size, fmt.Sprintf("%s is the current size, and still "+
+				"fits%s, at $%v per hour", d.Size, where, d.Price)
+		}
+	}
+
+	kind, note := "", ""
+	if m.PreferPreviousGeneration {
+		if len(previous) > 0 {
+			fits = previous
+			kind = "previous generation "
+		} else {
+			note = "; no previous generation size fits"
+		}
+	}
+
+	best := fits[0]
+	for _, d := range fits {
+		if d.Price < best.Price {
 			best = d
 		}
 	}
-	if maxPrice == 0 || best.Price <= maxPrice {
-		return best.Size
+
+	reason := fmt.Sprintf("%s is the cheapest of the %d %ssizes%s that fit",
+		best.Size, len(fits), kind, where)
+	if len(fits) == 1 {
+		reason = fmt.Sprintf("%s is the only %ssize%s that fits", best.Size,
+			kind, where)
+	}
+	reason += fmt.Sprintf(", at $%v per hour%s%s", best.Price, why, note)
+	return best.Size, reason
+}
+
+// ignoring appends to `reason` the size preferences of `m` that are ignored by a
+// provider that can't honor them.
+func ignoring(reason string, m stitch.Machine) string {
+	if prefs := m.SizePreferences(); len(prefs) > 0 {
+		reason += fmt.Sprintf(" (ignoring %s)", strings.Join(prefs, ", "))
 	}
-	return ""
+	return reason
 }
@@ -0,0 +1,312 @@
+package provider
+
+////// SET UP API ACCESS:
+//
+// 1) In the DigitalOcean control panel navigate to:
+//    API > Tokens
+//
+// 2) Generate a new token with read and write scopes.
+//
+// 3) Save the token as "~/.digitalocean/key"
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NetSys/quilt/catalog"
+	"github.com/NetSys/quilt/cluster/provider/digitalocean"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/join"
+	"github.com/NetSys/quilt/stitch"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/satori/go.uuid"
+)
+
+// DOClient defines an interface that can be mocked out for interacting with
+// DigitalOcean.
+type DOClient interface {
+	CreateDroplet(digitalocean.DropletCreateRequest) (digitalocean.Droplet, error)
+
+	CreateFirewall(digitalocean.Firewall) (digitalocean.Firewall, error)
+
+	DeleteDroplet(id int) error
+
+	ListDroplets(tag string) ([]digitalocean.Droplet, error)
+
+	ListFirewalls() ([]digitalocean.Firewall, error)
+
+	UpdateFirewall(digitalocean.Firewall) (digitalocean.Firewall, error)
+}
+
+// Ubuntu 16.04, 64-bit
+const doImage = "ubuntu-16-04-x64"
+
+// Droplets are tagged with the namespace, and the namespace's firewall applies to
+// all droplets with that tag.
+type doCluster struct {
+	client    DOClient
+	namespace string
+}
+
+func (clst *doCluster) Connect(namespace string) error {
+	keyfile := filepath.Join(os.Getenv("HOME"), ".digitalocean", "key")
+	key, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		return err
+	}
+
+	clst.client = digitalocean.New(strings.TrimSpace(string(key)))
+	clst.namespace = strings.ToLower(namespace)
+
+	if _, err := clst.List(); err != nil {
+		return errors.New("DigitalOcean failed to connect")
+	}
+	return nil
+}
+
+func (clst doCluster) List() ([]Machine, error) {
+	droplets, err := clst.client.ListDroplets(clst.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var machines []Machine
+	for _, d := range droplets {
+		machine := Machine{
+			ID:        strconv.Itoa(d.ID),
+			PublicIP:  d.IP("public"),
+			PrivateIP: d.IP("private"),
+			Size:      d.SizeSlug,
+			Provider:  db.DigitalOcean,
+			Region:    d.Region.Slug,
+		}
+
+		// The boot record is held in tags of the form "key:value".
+		record := map[string]string{}
+		for _, tag := range d.Tags {
+			if kv := strings.SplitN(tag, ":", 2); len(kv) == 2 {
+				record[kv[0]] = kv[1]
+			}
+		}
+		readBootRecord(&machine, record)
+		machines = append(machines, machine)
+	}
+	return machines, nil
+}
+
+func (clst doCluster) Boot(bootSet []Machine) []error {
+	errs := make([]error, len(bootSet))
+	var ids []string
+	var booting []int
+	for i, m := range bootSet {
+		image := m.Image
+		if image == "" {
+			image = doImage
+		}
+
+		tags := []string{clst.namespace}
+		for key, value := range bootRecord(m) {
+			tags = append(tags, key+":"+value)
+		}
+		sort.Strings(tags[1:])
+
+		d, err := clst.client.CreateDroplet(digitalocean.DropletCreateRequest{
+			Name:              "quilt-" + uuid.NewV4().String(),
+			Region:            m.Region,
+			Size:              m.Size,
+			Image:             image,
+			UserData:          cloudConfigUbuntu(m, "xenial"),
+			PrivateNetworking: true,
+			Tags:              tags,
+		})
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		ids = append(ids, strconv.Itoa(d.ID))
+		booting = append(booting, i)
+	}
+
+	if err := clst.wait(ids, true); err != nil {
+		for _, i := range booting {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+func (clst doCluster) Stop(machines []Machine) []error {
+	errs := make([]error, len(machines))
+	var ids []string
+	var stopping []int
+	for i, m := range machines {
+		id, err := strconv.Atoi(m.ID)
+		if err == nil {
+			err = clst.client.DeleteDroplet(id)
+		}
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		ids = append(ids, m.ID)
+		stopping = append(stopping, i)
+	}
+
+	if err := clst.wait(ids, false); err != nil {
+		for _, i := range stopping {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+// wait for the droplets `ids` to have booted or been deleted depending on the
+// value of `boot`.
+func (clst doCluster) wait(ids []string, boot bool) error {
+OuterLoop:
+	for i := 0; i < 100; i++ {
+		machines, err := clst.List()
+		if err != nil {
+			log.WithError(err).Warn("Failed to get machines.")
+			time.Sleep(10 * time.Second)
+			continue
+		}
+
+		exists := map[string]struct{}{}
+		for _, m := range machines {
+			exists[m.ID] = struct{}{}
+		}
+
+		for _, id := range ids {
+			if _, ok := exists[id]; ok != boot {
+				time.Sleep(10 * time.Second)
+				continue OuterLoop
+			}
+		}
+
+		return nil
+	}
+
+	return errors.New("timed out")
+}
+
+func (clst doCluster) SetACLs(acls []ACL) error {
+	firewalls, err := clst.client.ListFirewalls()
+	if err != nil {
+		return err
+	}
+
+	var curr *digitalocean.Firewall
+	for i := range firewalls {
+		if firewalls[i].Name == clst.namespace {
+			curr = &firewalls[i]
+		}
+	}
+
+	if curr != nil {
+		_, toAdd, toRemove := join.HashJoin(ACLSlice(acls),
+			ACLSlice(doParseACLs(*curr)), nil, nil)
+		if len(toAdd) == 0 && len(toRemove) == 0 {
+			return nil
+		}
+	}
+
+	fw := clst.firewall(acls)
+	log.WithField("ACLs", acls).Debug("DigitalOcean: Setting ACLs")
+	if curr == nil {
+		_, err = clst.client.CreateFirewall(fw)
+	} else {
+		fw.ID = curr.ID
+		_, err = clst.client.UpdateFirewall(fw)
+	}
+	return err
+}
+
+// firewall returns the namespace's firewall with `acls` applied.  Droplets in the
+// namespace may always reach each other, and anything outside of it.
+func (clst doCluster) firewall(acls []ACL) digitalocean.Firewall {
+	fw := digitalocean.Firewall{
+		Name: clst.namespace,
+		Tags: []string{clst.namespace},
+	}
+
+	everywhere := digitalocean.Sources{Addresses: []string{"0.0.0.0/0", "::/0"}}
+	namespace := digitalocean.Sources{Tags: []string{clst.namespace}}
+	for _, protocol := range []string{"icmp", "tcp", "udp"} {
+		ports := "all"
+		if protocol == "icmp" {
+			ports = ""
+		}
+
+		fw.InboundRules = append(fw.InboundRules, digitalocean.InboundRule{
+			Protocol: protocol,
+			Ports:    ports,
+			Sources:  namespace,
+		})
+		fw.OutboundRules = append(fw.OutboundRules, digitalocean.OutboundRule{
+			Protocol:     protocol,
+			Ports:        ports,
+			Destinations: everywhere,
+		})
+	}
+
+	for _, acl := range acls {
+		ports := strconv.Itoa(acl.MinPort)
+		if acl.MinPort != acl.MaxPort {
+			ports = fmt.Sprintf("%d-%d", acl.MinPort, acl.MaxPort)
+		}
+
+		for _, protocol := range []string{"tcp", "udp"} {
+			fw.InboundRules = append(fw.InboundRules,
+				digitalocean.InboundRule{
+					Protocol: protocol,
+					Ports:    ports,
+					Sources: digitalocean.Sources{
+						Addresses: []string{acl.CidrIP},
+					},
+				})
+		}
+	}
+	return fw
+}
+
+// doParseACLs returns the ACLs applied by the TCP rules of `fw`.  The UDP rules
+// always mirror them.
+func doParseACLs(fw digitalocean.Firewall) (acls []ACL) {
+	for _, rule := range fw.InboundRules {
+		if rule.Protocol != "tcp" {
+			continue
+		}
+
+		minPort, maxPort := 1, 65535
+		if rule.Ports != "all" && rule.Ports != "0" && rule.Ports != "" {
+			portRange := strings.Split(rule.Ports, "-")
+			minPort, _ = strconv.Atoi(portRange[0])
+			maxPort = minPort
+			if len(portRange) > 1 {
+				maxPort, _ = strconv.Atoi(portRange[1])
+			}
+		}
+
+		for _, cidrIP := range rule.Sources.Addresses {
+			acls = append(acls, ACL{
+				CidrIP:  cidrIP,
+				MinPort: minPort,
+				MaxPort: maxPort,
+			})
+		}
+	}
+	return acls
+}
+
+func (clst doCluster) ChooseSize(m stitch.Machine) (string, string) {
+	return pickBestSize(catalog.Get(db.DigitalOcean), m)
+}
@@ -0,0 +1,259 @@
+// Package digitalocean is a minimal client for the parts of the DigitalOcean v2 API
+// used to manage Quilt clusters.
+package digitalocean
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultBaseURL is the address of the DigitalOcean API.
+const DefaultBaseURL = "https://api.digitalocean.com/v2"
+
+// A Droplet is a DigitalOcean virtual machine.
+type Droplet struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	SizeSlug string   `json:"size_slug"`
+	Region   Region   `json:"region"`
+	Networks Networks `json:"networks"`
+	Tags     []string `json:"tags"`
+}
+
+// A Region is a DigitalOcean datacenter.
+type Region struct {
+	Slug string `json:"slug"`
+}
+
+// Networks are the network interfaces of a droplet.
+type Networks struct {
+	V4 []NetworkV4 `json:"v4"`
+}
+
+// NetworkV4 is an IPv4 address of a droplet.  Its Type is either "public" or
+// "private".
+type NetworkV4 struct {
+	IPAddress string `json:"ip_address"`
+	Type      string `json:"type"`
+}
+
+// IP returns the droplet's address of type `netType`, or the empty string if it
+// has none.
+func (d Droplet) IP(netType string) string {
+	for _, net := range d.Networks.V4 {
+		if net.Type == netType {
+			return net.IPAddress
+		}
+	}
+	return ""
+}
+
+// A Size is a kind of droplet.  Memory is in MB, and Disk in GB.
+type Size struct {
+	Slug        string   `json:"slug"`
+	Memory      int      `json:"memory"`
+	VCPUs       int      `json:"vcpus"`
+	Disk        int      `json:"disk"`
+	PriceHourly float64  `json:"price_hourly"`
+	Regions     []string `json:"regions"`
+	Available   bool     `json:"available"`
+}
+
+// A DropletCreateRequest describes a droplet to be created.
+type DropletCreateRequest struct {
+	Name              string   `json:"name"`
+	Region            string   `json:"region"`
+	Size              string   `json:"size"`
+	Image             string   `json:"image"`
+	UserData          string   `json:"user_data,omitempty"`
+	PrivateNetworking bool     `json:"private_networking"`
+	Tags              []string `json:"tags,omitempty"`
+}
+
+// A Firewall is a DigitalOcean cloud firewall.  It applies to the droplets with any
+// of its Tags.
+type Firewall struct {
+	ID            string         `json:"id,omitempty"`
+	Name          string         `json:"name"`
+	InboundRules  []InboundRule  `json:"inbound_rules"`
+	OutboundRules []OutboundRule `json:"outbound_rules"`
+	Tags          []string       `json:"tags"`
+}
+
+// An InboundRule allows traffic from Sources to Ports.  Ports is a single port, a
+// range such as "8000-9000", or "all".
+type InboundRule struct {
+	Protocol string  `json:"protocol"`
+	Ports    string  `json:"ports,omitempty"`
+	Sources  Sources `json:"sources"`
+}
+
+// An OutboundRule allows traffic to Destinations.
+type OutboundRule struct {
+	Protocol     string  `json:"protocol"`
+	Ports        string  `json:"ports,omitempty"`
+	Destinations Sources `json:"destinations"`
+}
+
+// Sources are the addresses and droplet tags a firewall rule applies to.
+type Sources struct {
+	Addresses []string `json:"addresses,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// A Client makes requests to the DigitalOcean API.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+}
+
+// New returns a Client that authenticates with the API token `token`.
+func New(token string) Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	return Client{
+		BaseURL: DefaultBaseURL,
+		HTTP:    oauth2.NewClient(oauth2.NoContext, ts),
+	}
+}
+
+// ListDroplets returns the droplets tagged with `tag`.
+func (c Client) ListDroplets(tag string) ([]Droplet, error) {
+	var droplets []Droplet
+	path := "/droplets?per_page=200&tag_name=" + url.QueryEscape(tag)
+	for path != "" {
+		var resp struct {
+			Droplets []Droplet `json:"droplets"`
+			Links    links     `json:"links"`
+		}
+		if err := c.do("GET", path, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		droplets = append(droplets, resp.Droplets...)
+		path = strings.TrimPrefix(resp.Links.Pages.Next, c.BaseURL)
+	}
+	return droplets, nil
+}
+
+// CreateDroplet creates the droplet described by `req`.
+func (c Client) CreateDroplet(req DropletCreateRequest) (Droplet, error) {
+	var resp struct {
+		Droplet Droplet `json:"droplet"`
+	}
+	err := c.do("POST", "/droplets", req, &resp)
+	return resp.Droplet, err
+}
+
+// DeleteDroplet deletes the droplet with ID `id`.
+func (c Client) DeleteDroplet(id int) error {
+	return c.do("DELETE", fmt.Sprintf("/droplets/%d", id), nil, nil)
+}
+
+// ListFirewalls returns all of the account's firewalls.
+func (c Client) ListFirewalls() ([]Firewall, error) {
+	var firewalls []Firewall
+	path := "/firewalls?per_page=200"
+	for path != "" {
+		var resp struct {
+			Firewalls []Firewall `json:"firewalls"`
+			Links     links      `json:"links"`
+		}
+		if err := c.do("GET", path, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		firewalls = append(firewalls, resp.Firewalls...)
+		path = strings.TrimPrefix(resp.Links.Pages.Next, c.BaseURL)
+	}
+	return firewalls, nil
+}
+
+// CreateFirewall creates `fw`.
+func (c Client) CreateFirewall(fw Firewall) (Firewall, error) {
+	var resp struct {
+		Firewall Firewall `json:"firewall"`
+	}
+	err := c.do("POST", "/firewalls", fw, &resp)
+	return resp.Firewall, err
+}
+
+// UpdateFirewall replaces the firewall with ID `fw.ID` with `fw`.
+func (c Client) UpdateFirewall(fw Firewall) (Firewall, error) {
+	var resp struct {
+		Firewall Firewall `json:"firewall"`
+	}
+	err := c.do("PUT", "/firewalls/"+fw.ID, fw, &resp)
+	return resp.Firewall, err
+}
+
+// ListSizes returns the sizes of droplet that DigitalOcean offers.
+func (c Client) ListSizes() ([]Size, error) {
+	var sizes []Size
+	path := "/sizes?per_page=200"
+	for path != "" {
+		var resp struct {
+			Sizes []Size `json:"sizes"`
+			Links links  `json:"links"`
+		}
+		if err := c.do("GET", path, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		sizes = append(sizes, resp.Sizes...)
+		path = strings.TrimPrefix(resp.Links.Pages.Next, c.BaseURL)
+	}
+	return sizes, nil
+}
+
+type links struct {
+	Pages struct {
+		Next string `json:"next"`
+	} `json:"pages"`
+}
+
+// do sends `body` as JSON to `path`, and decodes the response into `result`, if
+// it isn't nil.
+func (c Client) do(method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(bodyJSON)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status,
+			apiErr.Message)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,113 @@
+package digitalocean
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListDroplets(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("tag_name") != "ns" {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+
+			// The first page links to the second.
+			if r.URL.Query().Get("page") == "" {
+				fmt.Fprintf(w, `{"droplets": [{"id": 1}], "links": `+
+					`{"pages": {"next": "%s/droplets?page=2&`+
+					`tag_name=ns"}}}`, server.URL)
+				return
+			}
+			fmt.Fprint(w, `{"droplets": [{"id": 2}], "links": {}}`)
+		}))
+	defer server.Close()
+
+	c := Client{BaseURL: server.URL, HTTP: http.DefaultClient}
+	droplets, err := c.ListDroplets("ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []Droplet{{ID: 1}, {ID: 2}}
+	if !reflect.DeepEqual(droplets, exp) {
+		t.Errorf("expected %v, got %v", exp, droplets)
+	}
+}
+
+func TestCreateDroplet(t *testing.T) {
+	req := DropletCreateRequest{Name: "name", Region: "sfo2", Size: "2gb",
+		Image: "image", Tags: []string{"ns"}}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var body DropletCreateRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.Method != "POST" || r.URL.Path != "/droplets" ||
+				!reflect.DeepEqual(body, req) {
+				t.Errorf("unexpected request %s %s: %v", r.Method,
+					r.URL, body)
+			}
+			fmt.Fprint(w, `{"droplet": {"id": 3, "name": "name"}}`)
+		}))
+	defer server.Close()
+
+	c := Client{BaseURL: server.URL, HTTP: http.DefaultClient}
+	droplet, err := c.CreateDroplet(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Droplet{ID: 3, Name: "name"}
+	if !reflect.DeepEqual(droplet, exp) {
+		t.Errorf("expected %v, got %v", exp, droplet)
+	}
+}
+
+func TestListSizes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/sizes" {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+			fmt.Fprint(w, `{"sizes": [{"slug": "2gb", "memory": 2048, `+
+				`"vcpus": 2, "disk": 40, "price_hourly": 0.02976, `+
+				`"regions": ["nyc1", "sfo2"], "available": true}], `+
+				`"links": {}}`)
+		}))
+	defer server.Close()
+
+	c := Client{BaseURL: server.URL, HTTP: http.DefaultClient}
+	sizes, err := c.ListSizes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []Size{{Slug: "2gb", Memory: 2048, VCPUs: 2, Disk: 40,
+		PriceHourly: 0.02976, Regions: []string{"nyc1", "sfo2"},
+		Available: true}}
+	if !reflect.DeepEqual(sizes, exp) {
+		t.Errorf("expected %v, got %v", exp, sizes)
+	}
+}
+
+func TestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"id": "unauthorized", "message": "bad token"}`)
+		}))
+	defer server.Close()
+
+	c := Client{BaseURL: server.URL, HTTP: http.DefaultClient}
+	err := c.DeleteDroplet(1)
+	exp := "DELETE /droplets/1: 401 Unauthorized: bad token"
+	if err == nil || err.Error() != exp {
+		t.Errorf("expected error %q, got %v", exp, err)
+	}
+}
@@ -0,0 +1,160 @@
+//go:generate mockery -name=DOClient
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/NetSys/quilt/cluster/provider/digitalocean"
+	"github.com/NetSys/quilt/cluster/provider/mocks"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestDOList(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mocks.DOClient)
+	mockClient.On("ListDroplets", testNamespace).Return([]digitalocean.Droplet{
+		{
+			ID:       1,
+			SizeSlug: "2gb",
+			Region:   digitalocean.Region{Slug: "sfo2"},
+			Networks: digitalocean.Networks{
+				V4: []digitalocean.NetworkV4{
+					{IPAddress: "publicIP", Type: "public"},
+					{IPAddress: "privateIP", Type: "private"},
+				},
+			},
+		},
+		// A droplet that hasn't been assigned addresses yet.
+		{
+			ID:       2,
+			SizeSlug: "4gb",
+			Region:   digitalocean.Region{Slug: "nyc1"},
+			Tags: []string{testNamespace, "quilt-image:custom",
+				"quilt-boot-script:hash"},
+		},
+	}, nil)
+
+	clst := doCluster{client: mockClient, namespace: testNamespace}
+	machines, err := clst.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []Machine{
+		{
+			ID:        "1",
+			PublicIP:  "publicIP",
+			PrivateIP: "privateIP",
+			Size:      "2gb",
+			Provider:  db.DigitalOcean,
+			Region:    "sfo2",
+		},
+		{
+			ID:             "2",
+			Size:           "4gb",
+			Provider:       db.DigitalOcean,
+			Region:         "nyc1",
+			Image:          "custom",
+			BootScriptHash: "hash",
+		},
+	}, machines)
+}
+
+func TestDOBoot(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mocks.DOClient)
+	mockClient.On("CreateDroplet", mock.Anything).Return(
+		digitalocean.Droplet{ID: 1}, nil).Once()
+	mockClient.On("CreateDroplet", mock.Anything).Return(
+		digitalocean.Droplet{ID: 2}, nil).Once()
+	mockClient.On("ListDroplets", testNamespace).Return(
+		[]digitalocean.Droplet{{ID: 1}, {ID: 2}}, nil)
+
+	clst := doCluster{client: mockClient, namespace: testNamespace}
+	custom := Machine{Region: "nyc1", Size: "4gb", Image: "custom",
+		BootScriptExtra: "extra"}
+	errs := clst.Boot([]Machine{{Region: "sfo2", Size: "2gb"}, custom})
+	assert.Equal(t, []error{nil, nil}, errs)
+
+	isReq := func(m Machine, image string, tags []string) interface{} {
+		return mock.MatchedBy(func(req digitalocean.DropletCreateRequest) bool {
+			return req.Region == m.Region && req.Size == m.Size &&
+				req.Image == image && req.PrivateNetworking &&
+				req.UserData == cloudConfigUbuntu(m, "xenial") &&
+				reflect.DeepEqual(req.Tags, tags)
+		})
+	}
+	mockClient.AssertCalled(t, "CreateDroplet", isReq(
+		Machine{Region: "sfo2", Size: "2gb"}, doImage,
+		[]string{testNamespace}))
+	mockClient.AssertCalled(t, "CreateDroplet", isReq(custom, "custom",
+		[]string{testNamespace, "quilt-boot-script:" + BootScriptHash("extra"),
+			"quilt-image:custom"}))
+}
+
+func TestDOStop(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mocks.DOClient)
+	mockClient.On("DeleteDroplet", mock.Anything).Return(nil)
+	mockClient.On("ListDroplets", testNamespace).Return(
+		[]digitalocean.Droplet{{ID: 3}}, nil)
+
+	clst := doCluster{client: mockClient, namespace: testNamespace}
+	errs := clst.Stop([]Machine{{ID: "1"}, {ID: "2"}})
+	assert.Equal(t, []error{nil, nil}, errs)
+
+	mockClient.AssertCalled(t, "DeleteDroplet", 1)
+	mockClient.AssertCalled(t, "DeleteDroplet", 2)
+	mockClient.AssertNotCalled(t, "DeleteDroplet", 3)
+}
+
+func TestDOSetACLs(t *testing.T) {
+	t.Parallel()
+
+	acls := []ACL{
+		{CidrIP: "1.2.3.4/32", MinPort: 1, MaxPort: 65535},
+		{CidrIP: "0.0.0.0/0", MinPort: 80, MaxPort: 80},
+	}
+	clst := doCluster{namespace: testNamespace}
+
+	// The firewall is created if it doesn't exist.
+	mockClient := new(mocks.DOClient)
+	mockClient.On("ListFirewalls").Return(nil, nil)
+	mockClient.On("CreateFirewall", mock.Anything).Return(
+		digitalocean.Firewall{}, nil)
+	clst.client = mockClient
+
+	assert.Nil(t, clst.SetACLs(acls))
+	mockClient.AssertCalled(t, "CreateFirewall", clst.firewall(acls))
+
+	// Nothing changes if the firewall is up to date.  DigitalOcean reports
+	// rules open to every port as "0".
+	fw := clst.firewall(acls)
+	fw.ID = "id"
+	for i, rule := range fw.InboundRules {
+		if rule.Ports == "1-65535" {
+			fw.InboundRules[i].Ports = "0"
+		}
+	}
+
+	mockClient = new(mocks.DOClient)
+	mockClient.On("ListFirewalls").Return([]digitalocean.Firewall{fw}, nil)
+	clst.client = mockClient
+
+	assert.Nil(t, clst.SetACLs(acls))
+	mockClient.AssertNotCalled(t, "UpdateFirewall", mock.Anything)
+
+	// Otherwise, the firewall is replaced.
+	mockClient.On("UpdateFirewall", mock.Anything).Return(
+		digitalocean.Firewall{}, nil)
+	newACLs := acls[:1]
+
+	assert.Nil(t, clst.SetACLs(newACLs))
+	exp := clst.firewall(newACLs)
+	exp.ID = "id"
+	mockClient.AssertCalled(t, "UpdateFirewall", exp)
+}
@@ -22,11 +22,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/NetSys/quilt/constants"
+	"github.com/NetSys/quilt/catalog"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/join"
 	"github.com/NetSys/quilt/stitch"
@@ -102,17 +103,19 @@ func (clst *gceCluster) Connect(namespace string) error {
 // listing that way doesn't get you information about the instances
 func (clst *gceCluster) List() ([]Machine, error) {
 	var mList []Machine
+	failed := RegionError{}
 	for _, zone := range supportedZones {
 		list, err := gceService.Instances.List(clst.projID, zone).
 			Filter(fmt.Sprintf("description eq %s", clst.ns)).Do()
 		if err != nil {
-			return nil, err
+			failed[zone] = err
+			continue
 		}
 		for _, item := range list.Items {
 			// XXX: This make some iffy assumptions about NetworkInterfaces
 			machineSplitURL := strings.Split(item.MachineType, "/")
 			mtype := machineSplitURL[len(machineSplitURL)-1]
-			mList = append(mList, Machine{
+			machine := Machine{
 				ID: item.Name,
 				PublicIP: item.NetworkInterfaces[0].
 					AccessConfigs[0].NatIP,
@@ -120,9 +123,24 @@ func (clst *gceCluster) List() ([]Machine, error) {
 				Size:      mtype,
 				Region:    zone,
 				Provider:  db.Google,
-			})
+			}
+
+			record := map[string]string{}
+			if item.Metadata != nil {
+				for _, meta := range item.Metadata.Items {
+					if meta != nil && meta.Value != nil {
+						record[meta.Key] = *meta.Value
+					}
+				}
+			}
+			readBootRecord(&machine, record)
+			mList = append(mList, machine)
 		}
 	}
+
+	if len(failed) != 0 {
+		return mList, failed
+	}
 	return mList, nil
 }
 
@@ -130,54 +148,58 @@ func (clst *gceCluster) List() ([]Machine, error) {
 //
 // XXX: currently ignores cloudConfig
 // XXX: should probably have a better clean up routine if an error is encountered
-func (clst *gceCluster) Boot(bootSet []Machine) error {
+func (clst *gceCluster) Boot(bootSet []Machine) []error {
+	errs := make([]error, len(bootSet))
 	var names []string
-	for _, m := range bootSet {
+	var booting []int
+	for i, m := range bootSet {
 		name := "quilt-" + uuid.NewV4().String()
-		_, err := clst.instanceNew(name, m.Size, m.Region,
-			cloudConfigUbuntu(m.SSHKeys, "xenial"))
+		image := m.Image
+		if image == "" {
+			image = clst.imgURL
+		}
+
+		_, err := clst.instanceNew(name, m.Size, m.Region, image,
+			cloudConfigUbuntu(m, "xenial"), bootRecord(m))
 		if err != nil {
-			log.WithFields(log.Fields{
-				"error": err,
-				"id":    m.ID,
-			}).Error("Failed to start instance.")
+			errs[i] = err
 			continue
 		}
 		names = append(names, name)
+		booting = append(booting, i)
 	}
+
 	if err := clst.wait(names, true); err != nil {
-		return err
+		for _, i := range booting {
+			errs[i] = err
+		}
 	}
-	return nil
+	return errs
 }
 
-// Deletes the instances, it is a blocking call.
-//
-// If an error occurs while deleting, it will finish the ones that have
-// successfully started before returning.
-//
-// XXX: should probably have a better clean up routine if an error is encountered
-func (clst *gceCluster) Stop(machines []Machine) error {
+func (clst *gceCluster) Stop(machines []Machine) []error {
+	errs := make([]error, len(machines))
 	var names []string
-	for _, m := range machines {
+	var stopping []int
+	for i, m := range machines {
 		if _, err := clst.instanceDel(m.ID, m.Region); err != nil {
-			log.WithFields(log.Fields{
-				"error": err,
-				"id":    m.ID,
-			}).Error("Failed to delete instance.")
+			errs[i] = err
 			continue
 		}
 		names = append(names, m.ID)
+		stopping = append(stopping, i)
 	}
+
 	if err := clst.wait(names, false); err != nil {
-		return err
+		for _, i := range stopping {
+			errs[i] = err
+		}
 	}
-	return nil
+	return errs
 }
 
-func (clst *gceCluster) ChooseSize(ram stitch.Range, cpu stitch.Range,
-	maxPrice float64) string {
-	return pickBestSize(constants.GoogleDescriptions, ram, cpu, maxPrice)
+func (clst *gceCluster) ChooseSize(m stitch.Machine) (string, string) {
+	return pickBestSize(catalog.Get(db.Google), m)
 }
 
 // Get() and operationWait() don't always present the same results, so
@@ -282,7 +304,26 @@ func (clst *gceCluster) instanceGet(name, zone string) (*compute.Instance, error
 // XXX: all kinds of hardcoded junk in here
 // XXX: currently only defines the bare minimum
 func (clst *gceCluster) instanceNew(name string, size string, zone string,
-	cloudConfig string) (*compute.Operation, error) {
+	image string, cloudConfig string, record map[string]string) (
+	*compute.Operation, error) {
+
+	items := []*compute.MetadataItems{
+		{
+			Key:   "startup-script",
+			Value: &cloudConfig,
+		},
+	}
+
+	var keys []string
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := record[key]
+		items = append(items, &compute.MetadataItems{Key: key, Value: &value})
+	}
+
 	instance := &compute.Instance{
 		Name:        name,
 		Description: clst.ns,
@@ -295,7 +336,7 @@ func (clst *gceCluster) instanceNew(name string, size string, zone string,
 				Boot:       true,
 				AutoDelete: true,
 				InitializeParams: &compute.AttachedDiskInitializeParams{
-					SourceImage: clst.imgURL,
+					SourceImage: image,
 				},
 			},
 		},
@@ -312,14 +353,7 @@ func (clst *gceCluster) instanceNew(name string, size string, zone string,
 					clst.ns),
 			},
 		},
-		Metadata: &compute.Metadata{
-			Items: []*compute.MetadataItems{
-				{
-					Key:   "startup-script",
-					Value: &cloudConfig,
-				},
-			},
-		},
+		Metadata: &compute.Metadata{Items: items},
 	}
 
 	op, err := gceService.Instances.
@@ -657,18 +691,30 @@ func (clst *gceCluster) fwInit() error {
 }
 
 func groupACLsByPorts(acls []ACL) map[ACL][]string {
-	grouped := make(map[ACL][]string)
+	cidrSets := make(map[ACL]map[string]struct{})
 	for _, acl := range acls {
 		key := ACL{
 			MinPort: acl.MinPort,
 			MaxPort: acl.MaxPort,
 		}
-		if _, ok := grouped[key]; !ok {
-			grouped[key] = nil
+		if _, ok := cidrSets[key]; !ok {
+			cidrSets[key] = make(map[string]struct{})
 		}
 		if acl.CidrIP != "" {
-			grouped[key] = append(grouped[key], acl.CidrIP)
+			cidrSets[key][acl.CidrIP] = struct{}{}
+		}
+	}
+
+	// The source ranges are sorted so that they can be compared against the
+	// firewall's current configuration.
+	grouped := make(map[ACL][]string)
+	for key, cidrSet := range cidrSets {
+		var cidrIPs []string
+		for cidrIP := range cidrSet {
+			cidrIPs = append(cidrIPs, cidrIP)
 		}
+		sort.Strings(cidrIPs)
+		grouped[key] = cidrIPs
 	}
 	return grouped
 }
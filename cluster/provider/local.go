@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/minion/docker"
+	"github.com/NetSys/quilt/stitch"
+
+	dkc "github.com/fsouza/go-dockerclient"
+	"github.com/satori/go.uuid"
+)
+
+// Local machines are privileged containers on the local docker daemon, each of
+// which runs its own docker daemon for the minion.
+const (
+	localImage          = "docker:1.12-dind"
+	localSize           = "local"
+	localNamespaceLabel = "quilt.local.namespace"
+)
+
+// The boot script of local machines.  The image isn't Ubuntu, and has no systemd, so
+// cloudConfigUbuntu can't be used.  The script becomes the container's process, so
+// the machine stops when the minion does.
+var localBootTemplate = `
+set -e
+
+# Let users in with their SSH keys, as on cloud machines.
+apk add --no-cache openssh sudo > /dev/null
+ssh-keygen -A
+addgroup -S docker 2> /dev/null || true
+adduser -D -s /bin/sh -G docker quilt
+sed -i 's/^quilt:!/quilt:*/' /etc/shadow
+echo "quilt ALL=(ALL) NOPASSWD: ALL" > /etc/sudoers.d/quilt
+mkdir -p /home/quilt/.ssh
+cat << 'QUILT_SSH_KEYS' > /home/quilt/.ssh/authorized_keys
+{{.SSHKeys}}
+QUILT_SSH_KEYS
+chown -R quilt:docker /home/quilt/.ssh
+/usr/sbin/sshd
+
+# The dind wrapper sets up the cgroups and mounts docker needs to nest.
+dind dockerd --bridge=none -H unix:///var/run/docker.sock \
+	> /var/log/dockerd.log 2>&1 &
+until docker info > /dev/null 2>&1; do sleep 1; done
+{{if .BootScriptExtra}}
+cat << 'QUILT_BOOT_SCRIPT_EXTRA' > /var/lib/quilt-boot-script-extra
+{{.BootScriptExtra}}
+QUILT_BOOT_SCRIPT_EXTRA
+sh /var/lib/quilt-boot-script-extra
+{{end}}
+mkdir -p /var/run/netns
+docker pull {{.QuiltImage}}
+exec docker run --net=host --name=minion --privileged \
+	-v /var/run/docker.sock:/var/run/docker.sock \
+	-v /proc:/hostproc:ro -v /var/run/netns:/var/run/netns:rw {{.QuiltImage}} \
+	quilt minion
+`
+
+type localCluster struct {
+	namespace string
+	dk        docker.Client
+}
+
+func (clst *localCluster) Connect(namespace string) error {
+	clst.namespace = namespace
+	clst.dk = docker.New("unix:///var/run/docker.sock")
+
+	// Check that the daemon is reachable.
+	_, err := clst.List()
+	return err
+}
+
+func (clst localCluster) List() ([]Machine, error) {
+	containers, err := clst.dk.List(map[string][]string{
+		"label": {localNamespaceLabel + "=" + clst.namespace},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var machines []Machine
+	for _, c := range containers {
+		if c.Labels[localNamespaceLabel] != clst.namespace {
+			continue
+		}
+
+		machine := Machine{
+			ID:        c.ID,
+			PublicIP:  c.IP,
+			PrivateIP: c.IP,
+			Size:      localSize,
+			Provider:  db.Local,
+		}
+		readBootRecord(&machine, c.Labels)
+		machines = append(machines, machine)
+	}
+	return machines, nil
+}
+
+func (clst localCluster) Boot(bootSet []Machine) []error {
+	errs := make([]error, len(bootSet))
+	for i, m := range bootSet {
+		labels := bootRecord(m)
+		labels[localNamespaceLabel] = clst.namespace
+
+		image := m.Image
+		if image == "" {
+			image = localImage
+		}
+
+		_, errs[i] = clst.dk.Run(docker.RunOptions{
+			Name:       "quilt-" + uuid.NewV4().String(),
+			Image:      image,
+			Args:       []string{"sh", "-c", localBootScript(m)},
+			Labels:     labels,
+			Privileged: true,
+		})
+	}
+	return errs
+}
+
+func localBootScript(m Machine) string {
+	t := template.Must(template.New("localBoot").Parse(localBootTemplate))
+
+	var script bytes.Buffer
+	err := t.Execute(&script, struct {
+		QuiltImage      string
+		SSHKeys         string
+		BootScriptExtra string
+	}{
+		QuiltImage:      quiltImage,
+		SSHKeys:         strings.Join(m.SSHKeys, "\n"),
+		BootScriptExtra: m.BootScriptExtra,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return script.String()
+}
+
+func (clst localCluster) Stop(machines []Machine) []error {
+	errs := make([]error, len(machines))
+	for i, m := range machines {
+		// The volume holds the machine's images and containers, so it's
+		// removed too.
+		errs[i] = clst.dk.RemoveContainer(dkc.RemoveContainerOptions{
+			ID:            m.ID,
+			Force:         true,
+			RemoveVolumes: true,
+		})
+	}
+	return errs
+}
+
+func (clst localCluster) SetACLs(acls []ACL) error {
+	return nil
+}
+
+func (clst localCluster) ChooseSize(m stitch.Machine) (string, string) {
+	return localSize, ignoring(localSize+" is the only size, as Local machines "+
+		"are containers on this host", m)
+}
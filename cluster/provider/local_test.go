@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/minion/docker"
+)
+
+func TestLocal(t *testing.T) {
+	t.Parallel()
+
+	md, dk := docker.NewMock()
+	clst := localCluster{namespace: testNamespace, dk: dk}
+	other := localCluster{namespace: "other", dk: dk}
+
+	errs := clst.Boot([]Machine{
+		{SSHKeys: []string{"key"}},
+		{Image: "custom", BootScriptExtra: "echo hi"},
+	})
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, []error{nil}, other.Boot([]Machine{{}}))
+
+	machines, err := clst.List()
+	assert.Nil(t, err)
+	assert.Len(t, machines, 2)
+
+	images := map[string]string{}
+	for _, m := range machines {
+		assert.Equal(t, db.Provider(db.Local), m.Provider)
+		assert.Equal(t, localSize, m.Size)
+
+		c := md.Containers[m.ID]
+		if m.Image == "" {
+			assert.Equal(t, localImage, c.Config.Image)
+			assert.Empty(t, m.BootScriptHash)
+		} else {
+			assert.Equal(t, m.Image, c.Config.Image)
+			assert.Equal(t, BootScriptHash("echo hi"), m.BootScriptHash)
+		}
+		assert.True(t, c.HostConfig.Privileged)
+		assert.Equal(t, "sh", c.Args[0])
+		images[c.Config.Image] = c.Args[2]
+	}
+
+	assert.Contains(t, images, localImage)
+	assert.Contains(t, images, "custom")
+	assert.True(t, strings.Contains(images[localImage], "\nkey\n"))
+	assert.False(t, strings.Contains(images[localImage], "echo hi"))
+	assert.True(t, strings.Contains(images["custom"], "\necho hi\n"))
+
+	assert.Equal(t, []error{nil, nil}, clst.Stop(machines))
+
+	machines, err = clst.List()
+	assert.Nil(t, err)
+	assert.Empty(t, machines)
+
+	machines, err = other.List()
+	assert.Nil(t, err)
+	assert.Len(t, machines, 1)
+}
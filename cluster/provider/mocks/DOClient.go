@@ -0,0 +1,132 @@
+package mocks
+
+import "github.com/NetSys/quilt/cluster/provider/digitalocean"
+import "github.com/stretchr/testify/mock"
+
+// DOClient is an autogenerated mock type for the DOClient type
+type DOClient struct {
+	mock.Mock
+}
+
+// CreateDroplet provides a mock function with given fields: _a0
+func (_m *DOClient) CreateDroplet(_a0 digitalocean.DropletCreateRequest) (digitalocean.Droplet, error) {
+	ret := _m.Called(_a0)
+
+	var r0 digitalocean.Droplet
+	if rf, ok := ret.Get(0).(func(digitalocean.DropletCreateRequest) digitalocean.Droplet); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(digitalocean.Droplet)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(digitalocean.DropletCreateRequest) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateFirewall provides a mock function with given fields: _a0
+func (_m *DOClient) CreateFirewall(_a0 digitalocean.Firewall) (digitalocean.Firewall, error) {
+	ret := _m.Called(_a0)
+
+	var r0 digitalocean.Firewall
+	if rf, ok := ret.Get(0).(func(digitalocean.Firewall) digitalocean.Firewall); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(digitalocean.Firewall)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(digitalocean.Firewall) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteDroplet provides a mock function with given fields: id
+func (_m *DOClient) DeleteDroplet(id int) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListDroplets provides a mock function with given fields: tag
+func (_m *DOClient) ListDroplets(tag string) ([]digitalocean.Droplet, error) {
+	ret := _m.Called(tag)
+
+	var r0 []digitalocean.Droplet
+	if rf, ok := ret.Get(0).(func(string) []digitalocean.Droplet); ok {
+		r0 = rf(tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]digitalocean.Droplet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFirewalls provides a mock function with given fields:
+func (_m *DOClient) ListFirewalls() ([]digitalocean.Firewall, error) {
+	ret := _m.Called()
+
+	var r0 []digitalocean.Firewall
+	if rf, ok := ret.Get(0).(func() []digitalocean.Firewall); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]digitalocean.Firewall)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFirewall provides a mock function with given fields: _a0
+func (_m *DOClient) UpdateFirewall(_a0 digitalocean.Firewall) (digitalocean.Firewall, error) {
+	ret := _m.Called(_a0)
+
+	var r0 digitalocean.Firewall
+	if rf, ok := ret.Get(0).(func(digitalocean.Firewall) digitalocean.Firewall); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(digitalocean.Firewall)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(digitalocean.Firewall) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
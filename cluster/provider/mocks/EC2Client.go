@@ -170,6 +170,29 @@ func (_m *EC2Client) DescribeSpotInstanceRequests(_a0 *ec2.DescribeSpotInstanceR
 	return r0, r1
 }
 
+// DescribeSpotPriceHistory provides a mock function with given fields: _a0
+func (_m *EC2Client) DescribeSpotPriceHistory(_a0 *ec2.DescribeSpotPriceHistoryInput) (*ec2.DescribeSpotPriceHistoryOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *ec2.DescribeSpotPriceHistoryOutput
+	if rf, ok := ret.Get(0).(func(*ec2.DescribeSpotPriceHistoryInput) *ec2.DescribeSpotPriceHistoryOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeSpotPriceHistoryOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ec2.DescribeSpotPriceHistoryInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DescribeVolumes provides a mock function with given fields: _a0
 func (_m *EC2Client) DescribeVolumes(_a0 *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
 	ret := _m.Called(_a0)
@@ -239,6 +262,29 @@ func (_m *EC2Client) RevokeSecurityGroupIngress(_a0 *ec2.RevokeSecurityGroupIngr
 	return r0, r1
 }
 
+// RunInstances provides a mock function with given fields: _a0
+func (_m *EC2Client) RunInstances(_a0 *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *ec2.Reservation
+	if rf, ok := ret.Get(0).(func(*ec2.RunInstancesInput) *ec2.Reservation); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.Reservation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ec2.RunInstancesInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TerminateInstances provides a mock function with given fields: _a0
 func (_m *EC2Client) TerminateInstances(_a0 *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
 	ret := _m.Called(_a0)
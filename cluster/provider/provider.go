@@ -1,7 +1,10 @@
 package provider
 
 import (
+	"crypto/sha1"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/stitch"
@@ -14,9 +17,29 @@ type Machine struct {
 	PrivateIP string
 	Size      string
 	DiskSize  int
+	Market    db.Market
+	MaxPrice  float64 // The most to pay per hour, or zero for no limit.
+	Image     string  // The image to boot from, or empty for the default.
 	SSHKeys   []string
 	Provider  db.Provider
 	Region    string
+
+	// A script run at the end of the machine's boot script.  It isn't listed, as
+	// only its hash is recorded on the machine.
+	BootScriptExtra string
+	BootScriptHash  string
+
+	// The server a Static machine runs on.
+	Host string
+
+	// Set if the cloud provider has given notice that it will reclaim the
+	// machine.
+	Draining bool
+
+	// The spot bid the machine was booted with, and the current spot price for
+	// its size, per hour.
+	Bid   float64
+	Price float64
 }
 
 // ACL represents allowed traffic to a machine.
@@ -30,15 +53,40 @@ type ACL struct {
 type Provider interface {
 	Connect(namespace string) error
 
+	// List returns the namespace's machines.  If only some regions couldn't be
+	// listed, the machines of the others are returned with a RegionError.
 	List() ([]Machine, error)
 
-	Boot([]Machine) error
+	// Boot and Stop return the error, if any, of each machine, in order, so that
+	// the machines that failed can be retried without holding up the rest.
+	Boot([]Machine) []error
 
-	Stop([]Machine) error
+	Stop([]Machine) []error
 
 	SetACLs(acls []ACL) error
 
-	ChooseSize(ram stitch.Range, cpu stitch.Range, maxPrice float64) string
+	// ChooseSize returns the size to boot `m` as, and why it was chosen, or an
+	// empty size, and why none fit.  `m.Region` and `m.MaxPrice` must be those
+	// the machine will boot with.  If `m.Size` is set, it's the size the machine
+	// is already running as, which is kept as long as it still fits.
+	ChooseSize(m stitch.Machine) (size, reason string)
+}
+
+// A RegionError maps the regions that List couldn't list to their errors.
+type RegionError map[string]error
+
+func (err RegionError) Error() string {
+	var regions []string
+	for region := range err {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	var msgs []string
+	for _, region := range regions {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", region, err[region]))
+	}
+	return strings.Join(msgs, "; ")
 }
 
 // New returns an empty instance of the Provider represented by `dbp`
@@ -50,6 +98,12 @@ func New(dbp db.Provider) Provider {
 		return &gceCluster{}
 	case db.Vagrant:
 		return &vagrantCluster{}
+	case db.DigitalOcean:
+		return &doCluster{}
+	case db.Static:
+		return newStaticCluster()
+	case db.Local:
+		return &localCluster{}
 	default:
 		panic("Unimplemented")
 	}
@@ -69,6 +123,41 @@ func GroupBy(machines []Machine) map[db.Provider][]Machine {
 	return machineMap
 }
 
+// The keys under which providers record the image, as requested, and the hash of
+// the extra boot script of the machines they boot, so that List can report them.
+const (
+	imageKey      = "quilt-image"
+	bootScriptKey = "quilt-boot-script"
+)
+
+// BootScriptHash returns the hash of the extra boot script `script` that List
+// reports, or the empty string if there's no script.
+func BootScriptHash(script string) string {
+	if script == "" {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha1.Sum([]byte(script)))
+}
+
+// bootRecord returns what providers record about how `m` is booted.  Empty values
+// are left out.
+func bootRecord(m Machine) map[string]string {
+	record := map[string]string{}
+	if m.Image != "" {
+		record[imageKey] = m.Image
+	}
+	if hash := BootScriptHash(m.BootScriptExtra); hash != "" {
+		record[bootScriptKey] = hash
+	}
+	return record
+}
+
+// readBootRecord fills in the Image and BootScriptHash of `m` from `record`.
+func readBootRecord(m *Machine, record map[string]string) {
+	m.Image = record[imageKey]
+	m.BootScriptHash = record[bootScriptKey]
+}
+
 // DefaultRegion populates `m.Region` for the provided db.Machine if one isn't
 // specified. This is intended to allow users to omit the cloud provider region when
 // they don't particularly care where a system is placed.
@@ -83,7 +172,9 @@ func DefaultRegion(m db.Machine) db.Machine {
 		region = "us-west-1"
 	case "Google":
 		region = "us-east1-b"
-	case "Vagrant":
+	case "Vagrant", "Static", "Local":
+	case "DigitalOcean":
+		region = "sfo2"
 	default:
 		panic(fmt.Sprintf("Unknown Cloud Provider: %s", m.Provider))
 	}
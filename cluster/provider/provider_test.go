@@ -3,7 +3,7 @@ package provider
 import (
 	"testing"
 
-	"github.com/NetSys/quilt/constants"
+	"github.com/NetSys/quilt/catalog"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/stitch"
 )
@@ -39,6 +39,14 @@ func TestDefaultRegion(t *testing.T) {
 		t.Errorf("expected %s, found %s", exp, m.Region)
 	}
 
+	m.Region = ""
+	m.Provider = "DigitalOcean"
+	exp = "sfo2"
+	m = DefaultRegion(m)
+	if m.Region != exp {
+		t.Errorf("expected %s, found %s", exp, m.Region)
+	}
+
 	m.Region = ""
 	m.Provider = "Panic"
 	defer func() {
@@ -51,16 +59,17 @@ func TestDefaultRegion(t *testing.T) {
 }
 
 func TestConstraints(t *testing.T) {
-	checkConstraint := func(descriptions []constants.Description, ram stitch.Range,
+	checkConstraint := func(descriptions []catalog.Description, ram stitch.Range,
 		cpu stitch.Range, maxPrice float64, exp string) {
-		resSize := pickBestSize(descriptions, ram, cpu, maxPrice)
+		resSize, _ := pickBestSize(descriptions, stitch.Machine{RAM: ram,
+			CPU: cpu, MaxPrice: maxPrice})
 		if resSize != exp {
 			t.Errorf("bad size picked. Expected %s, got %s", exp, resSize)
 		}
 	}
 
 	// Test all constraints specified with valid price
-	testDescriptions := []constants.Description{
+	testDescriptions := []catalog.Description{
 		{Size: "size1", Price: 2, RAM: 2, CPU: 2},
 	}
 	checkConstraint(testDescriptions, stitch.Range{Min: 1, Max: 3},
@@ -83,7 +92,7 @@ func TestConstraints(t *testing.T) {
 		stitch.Range{Min: 2}, 1, "")
 
 	// Test multiple matches (should pick cheapest)
-	testDescriptions = []constants.Description{
+	testDescriptions = []catalog.Description{
 		{Size: "size2", Price: 2, RAM: 8, CPU: 4},
 		{Size: "size3", Price: 1, RAM: 4, CPU: 4},
 		{Size: "size4", Price: 0.5, RAM: 3, CPU: 4},
@@ -106,6 +115,139 @@ func TestConstraints(t *testing.T) {
 		stitch.Range{}, 0, "size4")
 }
 
+func TestConstraintsRegionGPU(t *testing.T) {
+	descriptions := []catalog.Description{
+		{Size: "cheap", Region: "us-east1", Price: 1, RAM: 4, CPU: 2},
+		{Size: "cheap", Region: "europe-west1", Price: 3, RAM: 4, CPU: 2},
+		{Size: "dear", Region: "us-east1", Price: 2, RAM: 4, CPU: 2},
+		{Size: "dear", Region: "europe-west1", Price: 2.5, RAM: 4, CPU: 2},
+		{Size: "gpu", Region: "us-east1", Price: 5, RAM: 4, CPU: 2, GPU: 1},
+	}
+
+	check := func(region string, gpu stitch.Range, exp string) {
+		res, _ := pickBestSize(descriptions, stitch.Machine{Region: region,
+			GPU: gpu})
+		if res != exp {
+			t.Errorf("region %s, GPU %v: expected %s, got %s",
+				region, gpu, exp, res)
+		}
+	}
+
+	check("us-east1", stitch.Range{}, "cheap")
+	check("europe-west1", stitch.Range{}, "dear")
+
+	// Zones are in the region they're named after.
+	check("europe-west1-b", stitch.Range{}, "dear")
+
+	// Regions missing from the catalog are priced as in any other region.
+	check("mars-north1", stitch.Range{}, "cheap")
+
+	check("us-east1", stitch.Range{Min: 1}, "gpu")
+	check("europe-west1", stitch.Range{Min: 1}, "")
+}
+
+func TestConstraintsPreferences(t *testing.T) {
+	descriptions := []catalog.Description{
+		{Size: "m4.large", Family: "m4", Price: 0.1, Network: 0.5},
+		{Size: "m3.large", Family: "m3", Price: 0.13, Network: 0.5,
+			EphemeralDisk: 32, EphemeralDiskType: "SSD",
+			PreviousGeneration: true},
+		{Size: "d2.xlarge", Family: "d2", Price: 0.69, Network: 0.5,
+			EphemeralDisk: 6000, EphemeralDiskType: "HDD"},
+		{Size: "c4.8xlarge", Family: "c4", Price: 1.6, Network: 10},
+	}
+
+	check := func(m stitch.Machine, exp string) {
+		res, _ := pickBestSize(descriptions, m)
+		if res != exp {
+			t.Errorf("%v: expected %s, got %s", m.SizePreferences(), exp,
+				res)
+		}
+	}
+
+	check(stitch.Machine{}, "m4.large")
+	check(stitch.Machine{LocalSSD: true}, "m3.large")
+	check(stitch.Machine{MinNetwork: 1}, "c4.8xlarge")
+	check(stitch.Machine{Families: []string{"d2", "c4"}}, "d2.xlarge")
+	check(stitch.Machine{ExcludeFamilies: []string{"m4", "m3"}}, "d2.xlarge")
+	check(stitch.Machine{PreferPreviousGeneration: true}, "m3.large")
+
+	// Without a previous generation size that fits, the cheapest is chosen.
+	check(stitch.Machine{PreferPreviousGeneration: true,
+		ExcludeFamilies: []string{"m3"}}, "m4.large")
+
+	check(stitch.Machine{LocalSSD: true, Families: []string{"c4"}}, "")
+}
+
+func TestConstraintsReason(t *testing.T) {
+	descriptions := []catalog.Description{
+		{Size: "m4.large", Region: "us-west-1", Family: "m4", Price: 0.117,
+			RAM: 8},
+		{Size: "m3.large", Region: "us-west-1", Family: "m3", Price: 0.14,
+			RAM: 7.5, PreviousGeneration: true},
+		{Size: "r3.large", Region: "us-west-1", Family: "r3", Price: 0.185,
+			RAM: 15, PreviousGeneration: true},
+		{Size: "m4.large", Region: "eu-west-1", Family: "m4", Price: 0.111,
+			RAM: 8},
+	}
+
+	check := func(m stitch.Machine, expSize, expReason string) {
+		size, reason := pickBestSize(descriptions, m)
+		if size != expSize || reason != expReason {
+			t.Errorf("\nGot: %s, %s\nExp: %s, %s", size, reason,
+				expSize, expReason)
+		}
+	}
+
+	check(stitch.Machine{Region: "us-west-1"}, "m4.large",
+		"m4.large is the cheapest of the 3 sizes in us-west-1 that fit, at "+
+			"$0.117 per hour")
+
+	check(stitch.Machine{Region: "us-west-1", RAM: stitch.Range{Min: 8},
+		PreferPreviousGeneration: true}, "r3.large",
+		"r3.large is the only previous generation size in us-west-1 that "+
+			"fits, at $0.185 per hour (ruled out: 1 for the wrong amount "+
+			"of RAM)")
+
+	check(stitch.Machine{Region: "us-west-1", ExcludeFamilies: []string{"m3",
+		"r3"}, PreferPreviousGeneration: true}, "m4.large",
+		"m4.large is the only size in us-west-1 that fits, at $0.117 per "+
+			"hour (ruled out: 2 for an excluded family); no previous "+
+			"generation size fits")
+
+	check(stitch.Machine{Region: "us-west-1", RAM: stitch.Range{Min: 8},
+		MaxPrice: 0.1}, "", "none of the 3 sizes in us-west-1 fit (ruled "+
+		"out: 1 for the wrong amount of RAM, 2 for a price above $0.1)")
+
+	// The size a machine is already running as is kept as long as it fits.
+	check(stitch.Machine{Region: "us-west-1", Size: "r3.large"}, "r3.large",
+		"r3.large is the current size, and still fits in us-west-1, at "+
+			"$0.185 per hour")
+	check(stitch.Machine{Region: "us-west-1", Size: "m3.large",
+		RAM: stitch.Range{Min: 8}}, "m4.large", "m4.large is the cheapest of "+
+		"the 2 sizes in us-west-1 that fit, at $0.117 per hour (ruled out: 1 "+
+		"for the wrong amount of RAM)")
+}
+
+func TestChooseFixedSize(t *testing.T) {
+	size, reason := localCluster{}.ChooseSize(stitch.Machine{
+		GPU: stitch.Range{Min: 1}, LocalSSD: true})
+	expReason := "local is the only size, as Local machines are containers " +
+		"on this host (ignoring gpu, localSSD)"
+	if size != localSize || reason != expReason {
+		t.Errorf("\nGot: %s, %s\nExp: %s, %s", size, reason, localSize,
+			expReason)
+	}
+
+	size, reason = vagrantCluster{}.ChooseSize(stitch.Machine{
+		RAM: stitch.Range{Min: 2}})
+	expReason = "2,1 has the least RAM and CPUs the machine accepts, as " +
+		"Vagrant machines may have any amount"
+	if size != "2,1" || reason != expReason {
+		t.Errorf("\nGot: %s, %s\nExp: 2,1, %s", size, reason, expReason)
+	}
+}
+
 func TestNewProviderSuccess(t *testing.T) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -115,6 +257,9 @@ func TestNewProviderSuccess(t *testing.T) {
 	New(db.Amazon)
 	New(db.Google)
 	New(db.Vagrant)
+	New(db.DigitalOcean)
+	New(db.Static)
+	New(db.Local)
 }
 
 func TestNewProviderFailure(t *testing.T) {
@@ -153,11 +298,35 @@ func TestGroupBy(t *testing.T) {
 }
 
 func TestCloudConfig(t *testing.T) {
-	cloudConfigTemplate = "({{.QuiltImage}}) ({{.SSHKeys}}) ({{.UbuntuVersion}})"
+	cloudConfigTemplate = "({{.QuiltImage}}) ({{.SSHKeys}}) ({{.UbuntuVersion}})" +
+		"{{if .BootScriptExtra}} ({{.BootScriptExtra}}){{end}}"
 
-	res := cloudConfigUbuntu([]string{"a", "b"}, "1")
+	res := cloudConfigUbuntu(Machine{SSHKeys: []string{"a", "b"}}, "1")
 	exp := "(quilt/quilt:latest) (a\nb) (1)"
 	if res != exp {
 		t.Errorf("res: %s\nexp: %s", res, exp)
 	}
+
+	res = cloudConfigUbuntu(Machine{BootScriptExtra: "echo hi"}, "1")
+	exp = "(quilt/quilt:latest) () (1) (echo hi)"
+	if res != exp {
+		t.Errorf("res: %s\nexp: %s", res, exp)
+	}
+}
+
+func TestVagrantNamespace(t *testing.T) {
+	clst := vagrantCluster{namespace: "a"}
+	id := "7f3c2b0e-9d4a-4c1e-8f6b-2a5d9e1c3b7a"
+	for instanceID, exp := range map[string]bool{
+		"a-" + id:   true,
+		"a-b-" + id: false,
+		"b-" + id:   false,
+		id:          false,
+		"a-":        false,
+	} {
+		if res := clst.inNamespace(instanceID); res != exp {
+			t.Errorf("inNamespace(%q) = %v, expected %v", instanceID, res,
+				exp)
+		}
+	}
 }
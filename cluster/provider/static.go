@@ -0,0 +1,681 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/stitch"
+
+	log "github.com/Sirupsen/logrus"
+	homedir "github.com/mitchellh/go-homedir"
+	"golang.org/x/crypto/ssh"
+)
+
+// The inventory of static hosts, relative to the home directory.  It's a JSON list
+// of staticHosts, e.g.:
+//
+//	[{"Address": "10.0.0.5", "User": "ubuntu", "KeyFile": "~/.ssh/rack",
+//	  "HostKey": "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI..."}]
+var staticInventory = filepath.Join(".quilt", "static-hosts.json")
+
+// The known hosts file, relative to the home directory.  Static hosts whose key
+// isn't in the inventory must be listed here.
+var staticKnownHosts = filepath.Join(".ssh", "known_hosts")
+
+// Each static host records the namespace it belongs to in this file, so that hosts
+// aren't claimed by two clusters, and are found again after the daemon restarts.
+const staticStateFile = "/var/lib/quilt/static-host"
+
+// The Size of all static machines, as Quilt has no say in what hardware they have.
+const staticSize = "static"
+
+// A staticHost is a server that Quilt can reach over SSH, but can't boot.
+type staticHost struct {
+	Address   string
+	PrivateIP string // Defaults to the Address.
+	User      string // Defaults to root.
+	KeyFile   string // Defaults to the keys in ~/.ssh.
+
+	// The host's public key, in authorized_keys format.  Defaults to the keys
+	// listed for the Address in ~/.ssh/known_hosts.
+	HostKey string
+}
+
+// parseStaticHost parses a stitch.Machine Host of the form "[user@]address".
+func parseStaticHost(str string) staticHost {
+	var host staticHost
+	if i := strings.LastIndex(str, "@"); i >= 0 {
+		host.User = str[:i]
+		str = str[i+1:]
+	}
+	host.Address = str
+	return host
+}
+
+// The contents of a host's staticStateFile.
+type staticState struct {
+	Namespace string
+	Host      string // The stitch.Machine Host the host was booted for.
+
+	// The image and the hash of the extra boot script the host was booted with.
+	Image      string `json:",omitempty"`
+	BootScript string `json:",omitempty"`
+}
+
+// staticSSH runs commands on static hosts.  It's an interface so that it can be
+// mocked out.
+type staticSSH interface {
+	// Run `cmd` as root on `host` with `stdin` as its input, and return its
+	// output.
+	Run(host staticHost, cmd, stdin string) (string, error)
+}
+
+type staticCluster struct {
+	namespace string
+	ssh       staticSSH
+
+	// The hosts in the inventory and those named by booted machines, by address.
+	hosts map[string]staticHost
+
+	// The iptables rules last applied to each host, by address.
+	rules map[string]string
+
+	mutex sync.Mutex
+}
+
+func newStaticCluster() *staticCluster {
+	return &staticCluster{
+		ssh:   nativeStaticSSH{},
+		hosts: map[string]staticHost{},
+		rules: map[string]string{},
+	}
+}
+
+// Connect reads the inventory.  A missing inventory is not an error, as the hosts
+// may be given in the spec instead.
+func (clst *staticCluster) Connect(namespace string) error {
+	clst.namespace = namespace
+
+	dir, err := homedir.Dir()
+	if err != nil {
+		return err
+	}
+
+	inventory, err := ioutil.ReadFile(filepath.Join(dir, staticInventory))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var hosts []staticHost
+	if err := json.Unmarshal(inventory, &hosts); err != nil {
+		return fmt.Errorf("malformed static host inventory: %s", err)
+	}
+
+	for _, host := range hosts {
+		clst.addHost(host)
+	}
+	return nil
+}
+
+func (clst *staticCluster) addHost(host staticHost) {
+	clst.mutex.Lock()
+	defer clst.mutex.Unlock()
+
+	// The inventory has the credentials for hosts it lists, so it's preferred
+	// to a spec that names the same host.
+	if _, ok := clst.hosts[host.Address]; !ok || host.KeyFile != "" {
+		clst.hosts[host.Address] = host
+	}
+}
+
+func (clst *staticCluster) getHosts() []staticHost {
+	clst.mutex.Lock()
+	defer clst.mutex.Unlock()
+
+	var hosts []staticHost
+	for _, host := range clst.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Sort(staticHostSlice(hosts))
+	return hosts
+}
+
+// List returns the hosts that belong to the namespace.  If any host can't be
+// reached, it may belong to the namespace, so an error is returned instead.
+func (clst *staticCluster) List() ([]Machine, error) {
+	hosts := clst.getHosts()
+	states, err := clst.getStates(hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	var machines []Machine
+	for i, host := range hosts {
+		state, ok := states[i]
+		if !ok || state.Namespace != clst.namespace {
+			continue
+		}
+
+		privateIP := host.PrivateIP
+		if privateIP == "" {
+			privateIP = host.Address
+		}
+
+		machines = append(machines, Machine{
+			ID:             host.Address,
+			PublicIP:       host.Address,
+			PrivateIP:      privateIP,
+			Size:           staticSize,
+			Provider:       db.Static,
+			Host:           state.Host,
+			Image:          state.Image,
+			BootScriptHash: state.BootScript,
+		})
+	}
+	return machines, nil
+}
+
+// getStates returns the state of each of `hosts` that could be reached, by index,
+// and an error naming the first that couldn't.
+func (clst *staticCluster) getStates(hosts []staticHost) (map[int]staticState,
+	error) {
+
+	states := map[int]staticState{}
+	errs := make([]error, len(hosts))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host staticHost) {
+			defer wg.Done()
+
+			out, err := clst.ssh.Run(host, fmt.Sprintf(
+				"cat %s 2> /dev/null || true", staticStateFile), "")
+			if err != nil {
+				errs[i] = fmt.Errorf("static host %s is unreachable: %s",
+					host.Address, err)
+				return
+			}
+
+			var state staticState
+			out = strings.TrimSpace(out)
+			if out != "" && json.Unmarshal([]byte(out), &state) != nil {
+				log.WithField("host", host.Address).Warn(
+					"Malformed static host state.")
+				return
+			}
+
+			mutex.Lock()
+			states[i] = state
+			mutex.Unlock()
+		}(i, host)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return states, err
+		}
+	}
+	return states, nil
+}
+
+// Boot claims a free host for each machine, and bootstraps it with the same boot
+// script used for cloud machines.  Machines with a Host only run on that host.
+func (clst *staticCluster) Boot(bootSet []Machine) []error {
+	for _, m := range bootSet {
+		if m.Host != "" {
+			clst.addHost(parseStaticHost(m.Host))
+		}
+	}
+
+	// Unreachable hosts can't be claimed, so they're skipped.
+	hosts := clst.getHosts()
+	states, err := clst.getStates(hosts)
+	if err != nil {
+		log.WithError(err).Warn("Failed to reach static host.")
+	}
+	claimed := map[int]bool{}
+
+	// claim returns the host `m` should run on, and whether it's already running
+	// there.  A host named in the spec may have been booted before the daemon
+	// restarted, and so forgot about it.
+	claim := func(m Machine) (staticHost, bool, error) {
+		for i, host := range hosts {
+			state, ok := states[i]
+			if !ok || claimed[i] {
+				continue
+			}
+
+			if m.Host == "" {
+				if state.Namespace == "" {
+					claimed[i] = true
+					return host, false, nil
+				}
+				continue
+			}
+
+			if parseStaticHost(m.Host).Address != host.Address {
+				continue
+			}
+
+			switch state {
+			case staticState{}:
+				claimed[i] = true
+				return host, false, nil
+			case clst.state(m):
+				claimed[i] = true
+				return host, true, nil
+			}
+		}
+
+		if m.Host != "" {
+			return staticHost{}, false, fmt.Errorf("static host %s is "+
+				"unreachable or in use", m.Host)
+		}
+		return staticHost{}, false, errors.New("no free static hosts")
+	}
+
+	errs := make([]error, len(bootSet))
+	var wg sync.WaitGroup
+	for i, m := range bootSet {
+		host, booted, err := claim(m)
+		if err != nil {
+			errs[i] = err
+			continue
+		} else if booted {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, host staticHost, m Machine) {
+			defer wg.Done()
+			errs[i] = clst.bootHost(host, m)
+		}(i, host, m)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// state returns the state of a host booted for `m`.
+func (clst *staticCluster) state(m Machine) staticState {
+	return staticState{
+		Namespace:  clst.namespace,
+		Host:       m.Host,
+		Image:      m.Image,
+		BootScript: BootScriptHash(m.BootScriptExtra),
+	}
+}
+
+func (clst *staticCluster) bootHost(host staticHost, m Machine) error {
+	log.WithField("host", host.Address).Info("Bootstrapping static host.")
+	state, err := json.Marshal(clst.state(m))
+	if err != nil {
+		panic(err)
+	}
+
+	// The host is claimed before it's bootstrapped.  With noclobber set, the
+	// shell only creates the state file if it doesn't exist yet, so only one
+	// cluster wins a host that several are booting at once.
+	_, err = clst.ssh.Run(host, fmt.Sprintf("mkdir -p %s && set -C && cat > %s",
+		filepath.Dir(staticStateFile), staticStateFile), string(state))
+	if err != nil {
+		return fmt.Errorf("static host %s is unreachable or in use: %s",
+			host.Address, err)
+	}
+
+	_, err = clst.ssh.Run(host, "bash -s", cloudConfigUbuntu(m, "xenial"))
+	if err != nil {
+		// Release the host so that booting it is tried again.
+		rmCmd := "rm -f " + staticStateFile
+		if _, rmErr := clst.ssh.Run(host, rmCmd, ""); rmErr != nil {
+			log.WithError(rmErr).WithField("host", host.Address).Warn(
+				"Failed to release static host.")
+		}
+		return fmt.Errorf("failed to bootstrap static host %s: %s",
+			host.Address, err)
+	}
+	return nil
+}
+
+// Stop tears down the minions of `machines`, and releases their hosts.  Docker and
+// the containers the minions started are left as they are.
+func (clst *staticCluster) Stop(machines []Machine) []error {
+	errs := make([]error, len(machines))
+	for i, m := range machines {
+		clst.mutex.Lock()
+		host, ok := clst.hosts[m.ID]
+		clst.mutex.Unlock()
+		if !ok {
+			errs[i] = fmt.Errorf("unknown static host %s", m.ID)
+			continue
+		}
+
+		cmd := "systemctl disable --now minion; docker rm -f minion; " +
+			staticACLTeardown + "; rm -f " + staticStateFile
+		if _, err := clst.ssh.Run(host, cmd, ""); err != nil {
+			errs[i] = fmt.Errorf("failed to stop static host %s: %s",
+				host.Address, err)
+			continue
+		}
+
+		clst.mutex.Lock()
+		delete(clst.rules, host.Address)
+		clst.mutex.Unlock()
+	}
+	return errs
+}
+
+// SetACLs allows only the traffic in `acls`, and traffic between the namespace's
+// hosts, through each host's iptables.  Without any ACLs, the rules are removed.
+func (clst *staticCluster) SetACLs(acls []ACL) error {
+	machines, err := clst.List()
+	if err != nil {
+		return err
+	}
+
+	script := staticACLTeardown
+	if len(acls) != 0 {
+		script = staticACLScript(acls, machines)
+	}
+	for _, m := range machines {
+		clst.mutex.Lock()
+		host := clst.hosts[m.ID]
+		applied := clst.rules[m.ID] == script
+		clst.mutex.Unlock()
+
+		if applied {
+			continue
+		}
+
+		log.WithField("host", m.ID).Debug("Static: Setting ACLs")
+		if _, err := clst.ssh.Run(host, "bash -s", script); err != nil {
+			return err
+		}
+
+		clst.mutex.Lock()
+		clst.rules[m.ID] = script
+		clst.mutex.Unlock()
+	}
+	return nil
+}
+
+// staticACLTeardown removes the "quilt" chain from the INPUT table, if it exists.
+const staticACLTeardown = "(iptables -D INPUT -j quilt; iptables -F quilt; " +
+	"iptables -X quilt) 2> /dev/null || true"
+
+// staticACLScript returns the script that replaces the rules of the "quilt" chain
+// of the INPUT table.  SSH from the daemon, whose address is taken from the
+// session running the script, is always accepted so that it isn't locked out.  If
+// its address is unknown, SSH is accepted from anywhere.
+func staticACLScript(acls []ACL, machines []Machine) string {
+	rules := []string{
+		"-i lo -j ACCEPT",
+		"-m state --state ESTABLISHED,RELATED -j ACCEPT",
+		"-p icmp -j ACCEPT",
+		"${daemon:+-s $daemon} -p tcp --dport 22 -j ACCEPT",
+	}
+
+	var ips []string
+	for _, m := range machines {
+		ips = append(ips, m.PublicIP, m.PrivateIP)
+	}
+	sort.Strings(ips)
+	for i, ip := range ips {
+		if i == 0 || ip != ips[i-1] {
+			rules = append(rules, fmt.Sprintf("-s %s -j ACCEPT", ip))
+		}
+	}
+
+	var aclRules []string
+	for _, acl := range acls {
+		for _, protocol := range []string{"tcp", "udp"} {
+			aclRules = append(aclRules, fmt.Sprintf(
+				"-s %s -p %s --dport %d:%d -j ACCEPT", acl.CidrIP,
+				protocol, acl.MinPort, acl.MaxPort))
+		}
+	}
+	sort.Strings(aclRules)
+	rules = append(append(rules, aclRules...), "-j DROP")
+
+	script := "daemon=${SSH_CLIENT%% *}\n" +
+		"iptables -N quilt 2> /dev/null\n" +
+		"iptables -C INPUT -j quilt 2> /dev/null || " +
+		"iptables -I INPUT -j quilt\n" +
+		"iptables -F quilt\n"
+	for _, rule := range rules {
+		script += "iptables -A quilt " + rule + "\n"
+	}
+	return script
+}
+
+func (clst *staticCluster) ChooseSize(m stitch.Machine) (string, string) {
+	return staticSize, ignoring(staticSize+" is the only size, as Static "+
+		"machines run on whichever servers are in the inventory", m)
+}
+
+type staticHostSlice []staticHost
+
+func (hosts staticHostSlice) Len() int {
+	return len(hosts)
+}
+
+func (hosts staticHostSlice) Less(i, j int) bool {
+	return hosts[i].Address < hosts[j].Address
+}
+
+func (hosts staticHostSlice) Swap(i, j int) {
+	hosts[i], hosts[j] = hosts[j], hosts[i]
+}
+
+type nativeStaticSSH struct{}
+
+func (nativeStaticSSH) Run(host staticHost, cmd, stdin string) (string, error) {
+	signers, err := staticSigners(host.KeyFile)
+	if err != nil {
+		return "", err
+	}
+
+	hostKeys, err := staticHostKeys(host)
+	if err != nil {
+		return "", err
+	}
+
+	user := host.User
+	if user == "" {
+		user = "root"
+	}
+
+	client, err := ssh.Dial("tcp", net.JoinHostPort(host.Address, "22"),
+		&ssh.ClientConfig{
+			User: user,
+			Auth: []ssh.AuthMethod{ssh.PublicKeys(signers...)},
+			HostKeyCallback: func(_ string, _ net.Addr,
+				key ssh.PublicKey) error {
+				return checkHostKey(host, hostKeys, key)
+			},
+			Timeout: 10 * time.Second,
+		})
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	// SSH_CLIENT is passed on, as sudo would clear it, so that the ACL script
+	// can tell the daemon's address.
+	if user != "root" {
+		cmd = `sudo SSH_CLIENT="$SSH_CLIENT" sh -c '` +
+			strings.Replace(cmd, "'", `'\''`, -1) + "'"
+	}
+
+	session.Stdin = strings.NewReader(stdin)
+	out, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", err, out)
+	}
+	return string(out), nil
+}
+
+// staticHostKeys returns the public keys `host` may have: its HostKey if it's
+// given, and otherwise those listed for it in the known hosts file.
+func staticHostKeys(host staticHost) ([]ssh.PublicKey, error) {
+	if host.HostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(host.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("malformed host key for static "+
+				"host %s: %s", host.Address, err)
+		}
+		return []ssh.PublicKey{key}, nil
+	}
+
+	dir, err := homedir.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	knownHosts, err := ioutil.ReadFile(filepath.Join(dir, staticKnownHosts))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return knownHostKeys(knownHosts, host.Address)
+}
+
+// knownHostKeys returns the keys that `knownHosts`, in the format of OpenSSH's
+// known_hosts file, lists for `address` on the default port.  Both plain and
+// hashed host names are matched, but wildcard patterns aren't.
+func knownHostKeys(knownHosts []byte, address string) ([]ssh.PublicKey, error) {
+	var keys []ssh.PublicKey
+	for rest := knownHosts; len(rest) > 0; {
+		marker, hosts, key, _, next, err := ssh.ParseKnownHosts(rest)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("malformed known hosts: %s", err)
+		}
+		rest = next
+
+		// Certificate authorities and revoked keys aren't host keys.
+		if marker != "" {
+			continue
+		}
+
+		for _, pattern := range hosts {
+			if knownHostMatches(pattern, address) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys, nil
+}
+
+// knownHostMatches returns true if the known_hosts host `pattern` names
+// `address`.  Hashed names have the form "|1|<salt>|<hash>", where the hash is the
+// HMAC-SHA1 of the name keyed by the salt.
+func knownHostMatches(pattern, address string) bool {
+	if !strings.HasPrefix(pattern, "|1|") {
+		return pattern == address || pattern == "["+address+"]:22"
+	}
+
+	parts := strings.Split(pattern[len("|1|"):], "|")
+	if len(parts) != 2 {
+		return false
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+
+	hash, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(address))
+	return hmac.Equal(mac.Sum(nil), hash)
+}
+
+// checkHostKey returns an error unless `key`, presented by `host`, is one of
+// `hostKeys`.  Hosts with no known keys are refused rather than trusted.
+func checkHostKey(host staticHost, hostKeys []ssh.PublicKey, key ssh.PublicKey) error {
+	if len(hostKeys) == 0 {
+		return fmt.Errorf("unknown static host %s: add its key to "+
+			"~/.ssh/known_hosts, or as its HostKey in the inventory",
+			host.Address)
+	}
+
+	for _, hostKey := range hostKeys {
+		if bytes.Equal(hostKey.Marshal(), key.Marshal()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the host key of static host %s doesn't match the "+
+		"known key", host.Address)
+}
+
+// staticSigners returns the signer for `keyFile`, or for the default keys in ~/.ssh
+// if it's empty.
+func staticSigners(keyFile string) ([]ssh.Signer, error) {
+	dir, err := homedir.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	if keyFile != "" {
+		keyFile, err = homedir.Expand(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		paths = []string{keyFile}
+	} else {
+		for _, name := range []string{"id_rsa", "id_ecdsa", "id_ed25519"} {
+			paths = append(paths, filepath.Join(dir, ".ssh", name))
+		}
+	}
+
+	var signers []ssh.Signer
+	for _, path := range paths {
+		key, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+		}
+		signers = append(signers, signer)
+	}
+
+	if len(signers) == 0 {
+		return nil, errors.New("no SSH keys found")
+	}
+	return signers, nil
+}
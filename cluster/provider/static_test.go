@@ -0,0 +1,331 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/NetSys/quilt/db"
+)
+
+// fakeStaticSSH simulates static hosts by their state files.
+type fakeStaticSSH struct {
+	states      map[string]staticState // By address.
+	unreachable map[string]bool
+	broken      map[string]bool // Hosts that fail to bootstrap.
+
+	// The commands run on each host, by address.
+	cmds  map[string][]string
+	mutex sync.Mutex
+}
+
+func newFakeStaticSSH() *fakeStaticSSH {
+	return &fakeStaticSSH{
+		states:      map[string]staticState{},
+		unreachable: map[string]bool{},
+		broken:      map[string]bool{},
+		cmds:        map[string][]string{},
+	}
+}
+
+func (f *fakeStaticSSH) Run(host staticHost, cmd, stdin string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.unreachable[host.Address] {
+		return "", errors.New("unreachable")
+	}
+	f.cmds[host.Address] = append(f.cmds[host.Address], cmd)
+
+	switch {
+	case strings.HasPrefix(cmd, "cat "+staticStateFile):
+		state, ok := f.states[host.Address]
+		if !ok {
+			return "", nil
+		}
+		out, _ := json.Marshal(state)
+		return string(out), nil
+	case strings.HasPrefix(cmd, "mkdir"):
+		// The state file is written with noclobber set.
+		if _, ok := f.states[host.Address]; ok {
+			return "", errors.New("cannot overwrite existing file")
+		}
+
+		var state staticState
+		if err := json.Unmarshal([]byte(stdin), &state); err != nil {
+			return "", err
+		}
+		f.states[host.Address] = state
+	case cmd == "bash -s" && f.broken[host.Address]:
+		return "", errors.New("bootstrap failed")
+	case strings.HasPrefix(cmd, "systemctl disable"),
+		cmd == "rm -f "+staticStateFile:
+		delete(f.states, host.Address)
+	}
+	return "", nil
+}
+
+func newTestStaticCluster(fake *fakeStaticSSH, hosts ...staticHost) *staticCluster {
+	clst := newStaticCluster()
+	clst.namespace = testNamespace
+	clst.ssh = fake
+	for _, host := range hosts {
+		clst.addHost(host)
+	}
+	return clst
+}
+
+func TestStaticList(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStaticSSH()
+	fake.states["1.1.1.1"] = staticState{Namespace: testNamespace}
+	fake.states["2.2.2.2"] = staticState{Namespace: testNamespace, Host: "2.2.2.2"}
+	fake.states["3.3.3.3"] = staticState{Namespace: "other"}
+	fake.states["4.4.4.4"] = staticState{Namespace: testNamespace}
+
+	clst := newTestStaticCluster(fake,
+		staticHost{Address: "1.1.1.1", PrivateIP: "10.0.0.1"},
+		staticHost{Address: "2.2.2.2"},
+		staticHost{Address: "3.3.3.3"},
+		staticHost{Address: "4.4.4.4"},
+		staticHost{Address: "5.5.5.5"})
+
+	// A host that can't be reached isn't left out, as it may be in use.
+	fake.unreachable["4.4.4.4"] = true
+	_, err := clst.List()
+	assert.EqualError(t, err, "static host 4.4.4.4 is unreachable: unreachable")
+
+	delete(fake.unreachable, "4.4.4.4")
+	delete(fake.states, "4.4.4.4")
+	machines, err := clst.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []Machine{
+		{
+			ID:        "1.1.1.1",
+			PublicIP:  "1.1.1.1",
+			PrivateIP: "10.0.0.1",
+			Size:      staticSize,
+			Provider:  db.Static,
+		},
+		{
+			ID:        "2.2.2.2",
+			PublicIP:  "2.2.2.2",
+			PrivateIP: "2.2.2.2",
+			Size:      staticSize,
+			Provider:  db.Static,
+			Host:      "2.2.2.2",
+		},
+	}, machines)
+}
+
+func TestStaticBoot(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStaticSSH()
+	fake.states["1.1.1.1"] = staticState{Namespace: "other"}
+	clst := newTestStaticCluster(fake,
+		staticHost{Address: "1.1.1.1"},
+		staticHost{Address: "2.2.2.2"},
+		staticHost{Address: "3.3.3.3"})
+
+	// Machines without a Host take any free host, and those with one take that
+	// host, even if it's not in the inventory.
+	errs := clst.Boot([]Machine{
+		{Provider: db.Static, Host: "3.3.3.3"},
+		{Provider: db.Static},
+		{Provider: db.Static, Host: "ubuntu@4.4.4.4"},
+	})
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+	assert.Equal(t, map[string]staticState{
+		"1.1.1.1": {Namespace: "other"},
+		"2.2.2.2": {Namespace: testNamespace},
+		"3.3.3.3": {Namespace: testNamespace, Host: "3.3.3.3"},
+		"4.4.4.4": {Namespace: testNamespace, Host: "ubuntu@4.4.4.4"},
+	}, fake.states)
+	assert.Equal(t, "ubuntu", clst.hosts["4.4.4.4"].User)
+	assert.Contains(t, fake.cmds["2.2.2.2"], "bash -s")
+
+	// There are no free hosts left.
+	errs = clst.Boot([]Machine{{Provider: db.Static}})
+	assert.EqualError(t, errs[0], "no free static hosts")
+
+	// A host named in the spec is adopted without being bootstrapped again, as
+	// it would be after the daemon restarts.
+	fake.cmds = map[string][]string{}
+	clst = newTestStaticCluster(fake)
+	errs = clst.Boot([]Machine{{Provider: db.Static, Host: "3.3.3.3"}})
+	assert.Equal(t, []error{nil}, errs)
+	assert.NotContains(t, fake.cmds["3.3.3.3"], "bash -s")
+
+	// But it can't be claimed by a different machine, or by one with a different
+	// boot script.
+	errs = clst.Boot([]Machine{{Provider: db.Static, Host: "root@3.3.3.3"}})
+	assert.EqualError(t, errs[0],
+		"static host root@3.3.3.3 is unreachable or in use")
+
+	errs = clst.Boot([]Machine{{Provider: db.Static, Host: "3.3.3.3",
+		BootScriptExtra: "echo hi"}})
+	assert.EqualError(t, errs[0], "static host 3.3.3.3 is unreachable or in use")
+
+	// The image and boot script a host was booted with are listed.
+	fake.states["3.3.3.3"] = clst.state(Machine{Host: "3.3.3.3",
+		Image: "image", BootScriptExtra: "echo hi"})
+	machines, err := clst.List()
+	assert.Nil(t, err)
+	assert.Len(t, machines, 1)
+	assert.Equal(t, "image", machines[0].Image)
+	assert.Equal(t, BootScriptHash("echo hi"), machines[0].BootScriptHash)
+}
+
+func TestStaticClaim(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStaticSSH()
+	clst := newTestStaticCluster(fake, staticHost{Address: "1.1.1.1"},
+		staticHost{Address: "2.2.2.2"})
+
+	// Another cluster claims the host after it was found to be free, so it's
+	// not bootstrapped.
+	fake.states["1.1.1.1"] = staticState{Namespace: "other"}
+	err := clst.bootHost(staticHost{Address: "1.1.1.1"}, Machine{})
+	assert.EqualError(t, err, "static host 1.1.1.1 is unreachable or in use: "+
+		"cannot overwrite existing file")
+	assert.NotContains(t, fake.cmds["1.1.1.1"], "bash -s")
+	assert.Equal(t, staticState{Namespace: "other"}, fake.states["1.1.1.1"])
+
+	// A host that fails to bootstrap is released.
+	fake.broken["2.2.2.2"] = true
+	err = clst.bootHost(staticHost{Address: "2.2.2.2"}, Machine{})
+	assert.EqualError(t, err,
+		"failed to bootstrap static host 2.2.2.2: bootstrap failed")
+	assert.NotContains(t, fake.states, "2.2.2.2")
+}
+
+func TestKnownHostKeys(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() ssh.PublicKey {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := ssh.NewPublicKey(&priv.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	line := func(hosts string, key ssh.PublicKey) string {
+		return hosts + " " + string(ssh.MarshalAuthorizedKey(key))
+	}
+
+	salt := []byte("salt")
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte("3.3.3.3"))
+	hashed := "|1|" + base64.StdEncoding.EncodeToString(salt) + "|" +
+		base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	a, b, c, d := newKey(), newKey(), newKey(), newKey()
+	knownHosts := "# comment\n" +
+		line("1.1.1.1,example.com", a) +
+		line("[2.2.2.2]:22", b) +
+		line("[1.1.1.1]:2222", d) +
+		line(hashed, c) +
+		line("@revoked 3.3.3.3", d)
+
+	for addr, exp := range map[string][]ssh.PublicKey{
+		"1.1.1.1": {a},
+		"2.2.2.2": {b},
+		"3.3.3.3": {c},
+		"4.4.4.4": nil,
+	} {
+		keys, err := knownHostKeys([]byte(knownHosts), addr)
+		assert.Nil(t, err)
+		assert.Equal(t, exp, keys, addr)
+	}
+
+	host := staticHost{Address: "1.1.1.1"}
+	assert.Nil(t, checkHostKey(host, []ssh.PublicKey{b, a}, a))
+	assert.EqualError(t, checkHostKey(host, []ssh.PublicKey{b}, a),
+		"the host key of static host 1.1.1.1 doesn't match the known key")
+	assert.EqualError(t, checkHostKey(host, nil, a), "unknown static host "+
+		"1.1.1.1: add its key to ~/.ssh/known_hosts, or as its HostKey in "+
+		"the inventory")
+
+	keys, err := staticHostKeys(staticHost{Address: "1.1.1.1",
+		HostKey: strings.TrimSpace(string(ssh.MarshalAuthorizedKey(d)))})
+	assert.Nil(t, err)
+	assert.Equal(t, []ssh.PublicKey{d}, keys)
+
+	_, err = staticHostKeys(staticHost{Address: "1.1.1.1", HostKey: "bogus"})
+	assert.NotNil(t, err)
+}
+
+func TestStaticStop(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStaticSSH()
+	fake.states["1.1.1.1"] = staticState{Namespace: testNamespace}
+	clst := newTestStaticCluster(fake, staticHost{Address: "1.1.1.1"})
+
+	// A host that fails doesn't stop the others.
+	errs := clst.Stop([]Machine{{ID: "2.2.2.2"}, {ID: "1.1.1.1"}})
+	assert.EqualError(t, errs[0], "unknown static host 2.2.2.2")
+	assert.Nil(t, errs[1])
+	assert.Empty(t, fake.states)
+}
+
+func TestStaticSetACLs(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStaticSSH()
+	fake.states["1.1.1.1"] = staticState{Namespace: testNamespace}
+	fake.states["2.2.2.2"] = staticState{Namespace: "other"}
+	clst := newTestStaticCluster(fake,
+		staticHost{Address: "1.1.1.1", PrivateIP: "10.0.0.1"},
+		staticHost{Address: "2.2.2.2"})
+
+	acls := []ACL{{CidrIP: "8.8.8.8/32", MinPort: 80, MaxPort: 81}}
+	assert.Nil(t, clst.SetACLs(acls))
+	assert.Equal(t, []string{"cat " + staticStateFile + " 2> /dev/null || true",
+		"bash -s"}, fake.cmds["1.1.1.1"])
+	assert.NotContains(t, fake.cmds["2.2.2.2"], "bash -s")
+
+	// The rules aren't applied again if they haven't changed.
+	fake.cmds = map[string][]string{}
+	assert.Nil(t, clst.SetACLs(acls))
+	assert.NotContains(t, fake.cmds["1.1.1.1"], "bash -s")
+
+	exp := "daemon=${SSH_CLIENT%% *}\n" +
+		"iptables -N quilt 2> /dev/null\n" +
+		"iptables -C INPUT -j quilt 2> /dev/null || " +
+		"iptables -I INPUT -j quilt\n" +
+		"iptables -F quilt\n" +
+		"iptables -A quilt -i lo -j ACCEPT\n" +
+		"iptables -A quilt -m state --state ESTABLISHED,RELATED -j ACCEPT\n" +
+		"iptables -A quilt -p icmp -j ACCEPT\n" +
+		"iptables -A quilt ${daemon:+-s $daemon} -p tcp --dport 22 -j ACCEPT\n" +
+		"iptables -A quilt -s 1.1.1.1 -j ACCEPT\n" +
+		"iptables -A quilt -s 10.0.0.1 -j ACCEPT\n" +
+		"iptables -A quilt -s 8.8.8.8/32 -p tcp --dport 80:81 -j ACCEPT\n" +
+		"iptables -A quilt -s 8.8.8.8/32 -p udp --dport 80:81 -j ACCEPT\n" +
+		"iptables -A quilt -j DROP\n"
+	assert.Equal(t, exp, clst.rules["1.1.1.1"])
+
+	// Without ACLs, the rules are removed.
+	assert.Nil(t, clst.SetACLs(nil))
+	assert.Equal(t, staticACLTeardown, clst.rules["1.1.1.1"])
+	assert.Contains(t, fake.cmds["1.1.1.1"], "bash -s")
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/NetSys/quilt/db"
@@ -9,6 +10,9 @@ import (
 	"github.com/satori/go.uuid"
 )
 
+// The box machines boot from, unless they name their own image.
+const defaultBox = "boxcutter/ubuntu1604"
+
 type vagrantCluster struct {
 	namespace string
 	vagrant   vagrantAPI
@@ -16,7 +20,7 @@ type vagrantCluster struct {
 
 func (clst *vagrantCluster) Connect(namespace string) error {
 	vagrant := newVagrantAPI()
-	err := vagrant.AddBox("boxcutter/ubuntu1604", "virtualbox")
+	err := vagrant.AddBox(defaultBox, "virtualbox")
 	if err != nil {
 		return err
 	}
@@ -25,40 +29,32 @@ func (clst *vagrantCluster) Connect(namespace string) error {
 	return nil
 }
 
-func (clst vagrantCluster) Boot(bootSet []Machine) error {
-
-	// If any of the bootMachine() calls fail, errChan will contain exactly one error
-	// for this function to return.
-	errChan := make(chan error, 1)
+func (clst vagrantCluster) Boot(bootSet []Machine) []error {
+	errs := make([]error, len(bootSet))
 
 	var wg sync.WaitGroup
-	for _, m := range bootSet {
+	for i, m := range bootSet {
 		wg.Add(1)
-		go func(m Machine) {
+		go func(i int, m Machine) {
 			defer wg.Done()
-			if err := bootMachine(clst.vagrant, m); err != nil {
-				select {
-				case errChan <- err:
-				default:
-				}
-			}
-		}(m)
+			errs[i] = bootMachine(clst.vagrant, clst.namespace, m)
+		}(i, m)
 	}
 	wg.Wait()
 
-	var err error
-	select {
-	case err = <-errChan:
-	default:
-	}
-
-	return err
+	return errs
 }
 
-func bootMachine(vagrant vagrantAPI, m Machine) error {
-	id := uuid.NewV4().String()
+func bootMachine(vagrant vagrantAPI, namespace string, m Machine) error {
+	id := namespace + "-" + uuid.NewV4().String()
+
+	box := m.Image
+	if box == "" {
+		box = defaultBox
+	}
 
-	err := vagrant.Init(cloudConfigUbuntu(m.SSHKeys, "xenial"), m.Size, id)
+	err := vagrant.Init(cloudConfigUbuntu(m, "xenial"), m.Size, box, id,
+		bootRecord(m))
 	if err == nil {
 		err = vagrant.Up(id)
 	}
@@ -82,6 +78,10 @@ func (clst vagrantCluster) List() ([]Machine, error) {
 	}
 
 	for _, instanceID := range instanceIDs {
+		if !clst.inNamespace(instanceID) {
+			continue
+		}
+
 		ip, err := vagrant.PublicIP(instanceID)
 		if err != nil {
 			log.WithError(err).Infof(
@@ -95,30 +95,39 @@ func (clst vagrantCluster) List() ([]Machine, error) {
 			Provider:  db.Vagrant,
 			Size:      vagrant.Size(instanceID),
 		}
+		readBootRecord(&instance, vagrant.BootRecord(instanceID))
 		machines = append(machines, instance)
 	}
 	return machines, nil
 }
 
-func (clst vagrantCluster) Stop(machines []Machine) error {
-	vagrant := clst.vagrant
-	if machines == nil {
-		return nil
+// inNamespace returns whether the machine `instanceID` was booted in this cluster's
+// namespace.  Every namespace shares the same Vagrant directory, so machine IDs are
+// prefixed with their namespace.
+func (clst vagrantCluster) inNamespace(instanceID string) bool {
+	if !strings.HasPrefix(instanceID, clst.namespace+"-") {
+		return false
 	}
-	for _, m := range machines {
-		err := vagrant.Destroy(m.ID)
-		if err != nil {
-			return err
-		}
+
+	// The namespace "a" must not claim the machines of namespace "a-b".
+	_, err := uuid.FromString(strings.TrimPrefix(instanceID, clst.namespace+"-"))
+	return err == nil
+}
+
+func (clst vagrantCluster) Stop(machines []Machine) []error {
+	errs := make([]error, len(machines))
+	for i, m := range machines {
+		errs[i] = clst.vagrant.Destroy(m.ID)
 	}
-	return nil
+	return errs
 }
 
 func (clst vagrantCluster) SetACLs(acls []ACL) error {
 	return nil
 }
 
-func (clst vagrantCluster) ChooseSize(ram stitch.Range, cpu stitch.Range,
-	maxPrice float64) string {
-	return clst.vagrant.CreateSize(ram.Min, cpu.Min)
+func (clst vagrantCluster) ChooseSize(m stitch.Machine) (string, string) {
+	size := clst.vagrant.CreateSize(m.RAM.Min, m.CPU.Min)
+	return size, ignoring(size+" has the least RAM and CPUs the machine "+
+		"accepts, as Vagrant machines may have any amount", m)
 }
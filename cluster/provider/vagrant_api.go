@@ -2,6 +2,7 @@ package provider
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -24,7 +25,9 @@ func newVagrantAPI() vagrantAPI {
 	return vagrant
 }
 
-func (api vagrantAPI) Init(cloudConfig string, size string, id string) error {
+func (api vagrantAPI) Init(cloudConfig, size, box, id string,
+	record map[string]string) error {
+
 	vdir, err := api.VagrantDir()
 	if err != nil {
 		return err
@@ -48,7 +51,7 @@ func (api vagrantAPI) Init(cloudConfig string, size string, id string) error {
 		return err
 	}
 
-	vagrant := vagrantFile()
+	vagrant := vagrantFile(box)
 	err = util.WriteFile(path+"/vagrantFile", []byte(vagrant), 0644)
 	if err != nil {
 		api.Destroy(id)
@@ -61,6 +64,15 @@ func (api vagrantAPI) Init(cloudConfig string, size string, id string) error {
 		return err
 	}
 
+	recordJSON, err := json.Marshal(record)
+	if err == nil {
+		err = util.WriteFile(path+"/boot-record", recordJSON, 0644)
+	}
+	if err != nil {
+		api.Destroy(id)
+		return err
+	}
+
 	return nil
 }
 
@@ -197,14 +209,25 @@ func (api vagrantAPI) Size(id string) string {
 	return string(size)
 }
 
-func vagrantFile() string {
+// BootRecord returns the record of how the machine `id` was booted, as written by
+// Init.
+func (api vagrantAPI) BootRecord(id string) map[string]string {
+	record := map[string]string{}
+	recordJSON, _, err := api.Shell(id, "cat boot-record")
+	if err == nil {
+		json.Unmarshal(recordJSON, &record)
+	}
+	return record
+}
+
+func vagrantFile(box string) string {
 	vagrantfile := `CLOUD_CONFIG_PATH = File.join(File.dirname(__FILE__), "user-data")
 SIZE_PATH = File.join(File.dirname(__FILE__), "size")
 Vagrant.require_version ">= 1.6.0"
 
 size = File.open(SIZE_PATH).read.strip.split(",")
 Vagrant.configure(2) do |config|
-  config.vm.box = "boxcutter/ubuntu1604"
+  config.vm.box = "` + box + `"
 
   config.vm.network "private_network", type: "dhcp"
 
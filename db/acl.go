@@ -6,18 +6,22 @@ import (
 	"log"
 )
 
-// ACL defines access control for Quilt-managed machines.
+// ACL defines access control for the Quilt-managed machines in a namespace.
 type ACL struct {
 	ID int
 
+	Namespace string
+
 	Admin            []string
 	ApplicationPorts []PortRange
 }
 
-// PortRange represents a range of ports for which to allow traffic.
+// PortRange represents a range of ports for which to allow traffic.  If CidrIP is
+// set, traffic is only allowed from hosts within that block.
 type PortRange struct {
 	MinPort int
 	MaxPort int
+	CidrIP  string
 }
 
 func (pr PortRange) String() string {
@@ -25,6 +29,9 @@ func (pr PortRange) String() string {
 	if pr.MaxPort != pr.MinPort {
 		port += fmt.Sprintf("-%d", pr.MaxPort)
 	}
+	if pr.CidrIP != "" {
+		port = pr.CidrIP + ":" + port
+	}
 	return port
 }
 
@@ -46,15 +53,18 @@ func (db Database) SelectFromACL(check func(ACL) bool) []ACL {
 	return result
 }
 
-// GetACL gets the ACL row from the database. There should only ever be a single
-// ACL row.
-func (db Database) GetACL() (ACL, error) {
-	aclRows := db.SelectFromACL(nil)
+// GetACL gets the ACL row for `namespace` from the database. There should only ever
+// be a single ACL row per namespace.
+func (db Database) GetACL(namespace string) (ACL, error) {
+	aclRows := db.SelectFromACL(func(acl ACL) bool {
+		return acl.Namespace == namespace
+	})
 	numACLs := len(aclRows)
 	if numACLs == 1 {
 		return aclRows[0], nil
 	} else if numACLs > 1 {
-		log.Panicf("Found %d ACL rows, there should be 1", numACLs)
+		log.Panicf("Found %d ACL rows in namespace %s, there should be 1",
+			numACLs, namespace)
 	}
 	return ACL{}, errors.New("no ACL rows found")
 }
@@ -5,7 +5,8 @@ import (
 	"log"
 )
 
-// A Cluster is a group of Machines which can operate containers.
+// A Cluster is a group of Machines which can operate containers.  The daemon
+// manages one Cluster per namespace.
 type Cluster struct {
 	ID int
 
@@ -31,15 +32,18 @@ func (db Database) SelectFromCluster(check func(Cluster) bool) []Cluster {
 	return result
 }
 
-// GetCluster gets the cluster from the database. There should only ever be a single
-// cluster.
-func (db Database) GetCluster() (Cluster, error) {
-	clusters := db.SelectFromCluster(nil)
+// GetCluster gets the cluster for `namespace` from the database. There should only
+// ever be a single cluster per namespace.
+func (db Database) GetCluster(namespace string) (Cluster, error) {
+	clusters := db.SelectFromCluster(func(c Cluster) bool {
+		return c.Namespace == namespace
+	})
 	numClusters := len(clusters)
 	if numClusters == 1 {
 		return clusters[0], nil
 	} else if numClusters > 1 {
-		log.Panicf("Found %d clusters, there should be 1", numClusters)
+		log.Panicf("Found %d clusters in namespace %s, there should be 1",
+			numClusters, namespace)
 	}
 	return Cluster{}, errors.New("no clusters found")
 }
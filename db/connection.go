@@ -5,7 +5,8 @@ import (
 )
 
 // A Connection allows the members of two labels to speak to each other on the port
-// range [MinPort, MaxPort] inclusive.
+// range [MinPort, MaxPort] inclusive.  Connections with the public internet may be
+// restricted to the hosts in CidrIP.
 type Connection struct {
 	ID int
 
@@ -13,6 +14,7 @@ type Connection struct {
 	To      string
 	MinPort int
 	MaxPort int
+	CidrIP  string
 }
 
 // InsertConnection creates a new connection row and inserts it into the database.
@@ -55,6 +57,10 @@ func (c Connection) String() string {
 		port += fmt.Sprintf("-%d", c.MaxPort)
 	}
 
+	if c.CidrIP != "" {
+		port += fmt.Sprintf(" from %s", c.CidrIP)
+	}
+
 	return fmt.Sprintf("Connection-%d{%s->%s:%s}", c.ID, c.From, c.To, port)
 }
 
@@ -70,6 +76,8 @@ func (c Connection) less(r row) bool {
 		return c.MaxPort < o.MaxPort
 	case c.MinPort != o.MaxPort:
 		return c.MinPort < o.MinPort
+	case c.CidrIP != o.CidrIP:
+		return c.CidrIP < o.CidrIP
 	default:
 		return c.ID < o.ID
 	}
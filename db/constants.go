@@ -61,12 +61,22 @@ const (
 
 	// Vagrant implements local virtual machines.
 	Vagrant = "Vagrant"
+
+	// DigitalOcean implements DigitalOcean droplets.
+	DigitalOcean = "DigitalOcean"
+
+	// Static implements servers that Quilt can't boot, but can reach over SSH.
+	Static = "Static"
+
+	// Local implements containers on the local docker daemon that act as
+	// machines, for development and testing.
+	Local = "Local"
 )
 
 // ParseProvider returns the Provider represented by 'name' or an error.
 func ParseProvider(name string) (Provider, error) {
 	switch name {
-	case "Amazon", "Google", "Vagrant":
+	case "Amazon", "Google", "Vagrant", "DigitalOcean", "Static", "Local":
 		return Provider(name), nil
 	default:
 		return "", errors.New("unknown provider")
@@ -87,6 +97,40 @@ func ParseRole(role string) (Role, error) {
 	}
 }
 
+// A Market is the purchasing option under which a machine is booted.
+type Market string
+
+const (
+	// Spot machines are cheap, but may be reclaimed by the cloud provider when
+	// capacity runs short or prices rise.
+	Spot Market = "spot"
+
+	// OnDemand machines cost more, but run until they are stopped.  Amazon bills
+	// them at the reserved rate if the account holds a matching reservation.
+	OnDemand = "ondemand"
+)
+
+// ParseMarket returns the Market represented by 'market' for machines booted on
+// 'provider', or an error if the provider doesn't offer it.  Amazon machines
+// default to the spot market.  Other providers have no markets.
+func ParseMarket(provider Provider, market string) (Market, error) {
+	if provider != Amazon {
+		if market != "" {
+			return "", errors.New("markets are only offered by Amazon")
+		}
+		return "", nil
+	}
+
+	switch market {
+	case "", "spot":
+		return Spot, nil
+	case "ondemand":
+		return OnDemand, nil
+	default:
+		return "", errors.New("unknown market")
+	}
+}
+
 // ProviderSlice is an alias for []Provider to allow for joins
 type ProviderSlice []Provider
 
@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/NetSys/quilt/util"
 )
@@ -23,6 +24,65 @@ type Container struct {
 	Command  []string
 	Labels   []string
 	Env      map[string]string
+
+	Dependencies []Dependency
+
+	// Job is the zero value unless the container runs to completion, rather than
+	// being restarted whenever it exits.
+	Job Job
+}
+
+// A Dependency holds back a container until a container implementing Label has an
+// IP address and is running, or if UntilHealthy is set, until all of them do.
+type Dependency struct {
+	Label        string
+	UntilHealthy bool
+}
+
+// A Job describes how a container that runs to completion is scheduled, and how its
+// most recent attempt fared.
+type Job struct {
+	Parallelism  int
+	BackoffLimit int
+	Backoff      int
+
+	State    JobState
+	ExitCode int
+	Retries  int
+	Finished time.Time
+}
+
+// JobState is the progress of a Job container's most recent attempt.
+type JobState string
+
+const (
+	// JobPending containers have yet to be started.
+	JobPending JobState = "pending"
+
+	// JobRunning containers have started, but not yet exited.
+	JobRunning JobState = "running"
+
+	// JobSucceeded containers exited with a zero exit code.
+	JobSucceeded JobState = "succeeded"
+
+	// JobFailed containers exited with a non-zero exit code.
+	JobFailed JobState = "failed"
+)
+
+// Ready returns true if the container has an IP address and is running, which is
+// what the containers that depend on it wait for.
+func (c Container) Ready() bool {
+	return c.IP != "" && c.DockerID != ""
+}
+
+// IsJob returns true if the container runs to completion.
+func (c Container) IsJob() bool {
+	return c.Job.Parallelism > 0
+}
+
+// Done returns true if the job's most recent attempt has exited.
+func (job Job) Done() bool {
+	return job.State == JobSucceeded || job.State == JobFailed
 }
 
 // ContainerSlice is an alias for []Container to allow for joins
@@ -98,6 +158,31 @@ func (c Container) String() string {
 		tags = append(tags, fmt.Sprintf("Env: %s", c.Env))
 	}
 
+	if len(c.Dependencies) > 0 {
+		var deps []string
+		for _, dep := range c.Dependencies {
+			deps = append(deps, dep.Label)
+		}
+		tags = append(tags, fmt.Sprintf("DependsOn: %s", deps))
+	}
+
+	if c.IsJob() {
+		state := c.Job.State
+		if state == "" {
+			state = JobPending
+		}
+
+		jobStr := fmt.Sprintf("Job: %s", state)
+		if c.Job.Done() {
+			jobStr += fmt.Sprintf(" (exit %d)", c.Job.ExitCode)
+		}
+		tags = append(tags, jobStr)
+
+		if c.Job.Retries > 0 {
+			tags = append(tags, fmt.Sprintf("Retries: %d", c.Job.Retries))
+		}
+	}
+
 	return fmt.Sprintf("Container-%d{%s}", c.ID, strings.Join(tags, ", "))
 }
 
@@ -114,11 +114,30 @@ func TestMachineString(t *testing.T) {
 		PublicIP:  "1.2.3.4",
 		PrivateIP: "5.6.7.8",
 		DiskSize:  56,
+		Market:    Spot,
+		MaxPrice:  0.08,
+		Bid:       0.08,
+		Price:     0.031,
 		Connected: true,
 	}
 	got = m.String()
 	exp = "Machine-1{Amazon us-west-1 m4.large, CloudID1234, PublicIP=1.2.3.4," +
-		" PrivateIP=5.6.7.8, Disk=56GB, Connected}"
+		" PrivateIP=5.6.7.8, Disk=56GB, Market=spot, MaxPrice=$0.08," +
+		" Bid=$0.08, Price=$0.031, Connected}"
+	if got != exp {
+		t.Errorf("\nGot: %s\nExp: %s", got, exp)
+	}
+
+	m = Machine{
+		ID:           2,
+		Provider:     "Amazon",
+		BootError:    "RequestLimitExceeded",
+		BootFailures: 2,
+		RetryAt:      time.Date(2017, 1, 1, 12, 30, 0, 0, time.UTC),
+	}
+	got = m.String()
+	exp = `Machine-2{Amazon  , BootError="RequestLimitExceeded", BootFailures=2,` +
+		` RetryAt=12:30:00}`
 	if got != exp {
 		t.Errorf("\nGot: %s\nExp: %s", got, exp)
 	}
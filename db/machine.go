@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Machine represents a physical or virtual machine operated by a cloud provider on
@@ -12,18 +13,47 @@ type Machine struct {
 	ID int //Database ID
 
 	/* Populated by the policy engine. */
-	Role     Role
-	Provider Provider
-	Region   string
-	Size     string
-	DiskSize int
-	SSHKeys  []string `rowStringer:"omit"`
+	Namespace string
+	Role      Role
+	Provider  Provider
+	Region    string
+	Size      string
+	DiskSize  int
+	Market    Market
+	MaxPrice  float64  // The most to pay per hour, or zero for no limit.
+	Image     string   // The image to boot from, or empty for the default.
+	SSHKeys   []string `rowStringer:"omit"`
+
+	// A script run at the end of the machine's boot script.
+	BootScriptExtra string `rowStringer:"omit"`
+
+	// The server a Static machine runs on, or empty for any free server.
+	Host string
+
+	// Protected machines may not be terminated by a new policy.
+	Protected bool
 
 	/* Populated by the cloud provider. */
 	CloudID   string //Cloud Provider ID
 	PublicIP  string
 	PrivateIP string
 
+	// The spot bid the machine was booted with, and the current spot price for
+	// its size, per hour.
+	Bid   float64
+	Price float64
+
+	// Draining machines have been given notice that the cloud provider will
+	// reclaim them.  They're replaced, and removed once they're gone.
+	Draining bool
+
+	/* Populated by the cluster. */
+	// The error of the last failed attempt to boot the machine, how many attempts
+	// in a row have failed, and when the next one may be made.
+	BootError    string
+	BootFailures int
+	RetryAt      time.Time
+
 	/* Populated by the foreman. */
 	Connected bool // Whether the minion on this machine has connected back.
 }
@@ -75,10 +105,52 @@ func (m Machine) String() string {
 		tags = append(tags, fmt.Sprintf("Disk=%dGB", m.DiskSize))
 	}
 
+	if m.Market != "" {
+		tags = append(tags, "Market="+string(m.Market))
+	}
+
+	if m.Image != "" {
+		tags = append(tags, "Image="+m.Image)
+	}
+
+	if m.Host != "" {
+		tags = append(tags, "Host="+m.Host)
+	}
+
+	if m.MaxPrice != 0 {
+		tags = append(tags, fmt.Sprintf("MaxPrice=$%g", m.MaxPrice))
+	}
+
+	if m.Bid != 0 {
+		tags = append(tags, fmt.Sprintf("Bid=$%g", m.Bid))
+	}
+
+	if m.Price != 0 {
+		tags = append(tags, fmt.Sprintf("Price=$%g", m.Price))
+	}
+
 	if m.Connected {
 		tags = append(tags, "Connected")
 	}
 
+	if m.Draining {
+		tags = append(tags, "Draining")
+	}
+
+	if m.Protected {
+		tags = append(tags, "Protected")
+	}
+
+	if m.BootError != "" {
+		tags = append(tags, fmt.Sprintf("BootError=%q", m.BootError),
+			fmt.Sprintf("BootFailures=%d", m.BootFailures),
+			"RetryAt="+m.RetryAt.Format("15:04:05"))
+	}
+
+	if m.Namespace != "" {
+		tags = append(tags, "Namespace="+m.Namespace)
+	}
+
 	return fmt.Sprintf("Machine-%d{%s}", m.ID, strings.Join(tags, ", "))
 }
 
@@ -17,6 +17,10 @@ type Minion struct {
 	Provider  string
 	Size      string
 	Region    string
+
+	// Draining minions are about to be reclaimed by their cloud provider, so
+	// containers are moved off of them.
+	Draining bool `json:",omitempty"`
 }
 
 // InsertMinion creates a new Minion and inserts it into 'db'.
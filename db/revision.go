@@ -0,0 +1,77 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// A Revision is a spec the daemon has applied.  Revisions are numbered in the order
+// they were applied, starting at 1, so that an earlier spec can be found and rolled
+// back to.
+type Revision struct {
+	ID int
+
+	Number    int
+	Namespace string
+	Time      time.Time
+	User      string
+
+	// The compiled spec, including its `importSources`.
+	Spec string `rowStringer:"omit"`
+}
+
+// InsertRevision creates a new revision row and inserts it into the database.
+func (db Database) InsertRevision() Revision {
+	result := Revision{ID: db.nextID()}
+	db.insert(result)
+	return result
+}
+
+// SelectFromRevision gets all revisions in the database that satisfy 'check'.
+func (db Database) SelectFromRevision(check func(Revision) bool) []Revision {
+	var result []Revision
+	for _, row := range db.tables[RevisionTable].rows {
+		if check == nil || check(row.(Revision)) {
+			result = append(result, row.(Revision))
+		}
+	}
+
+	return result
+}
+
+// LatestRevision gets the most recently applied revision in any namespace, if
+// there is one.
+func (db Database) LatestRevision() (Revision, bool) {
+	return db.latestRevision(nil)
+}
+
+// LatestRevisionIn gets the most recently applied revision in `namespace`, if there
+// is one.
+func (db Database) LatestRevisionIn(namespace string) (Revision, bool) {
+	return db.latestRevision(func(rev Revision) bool {
+		return rev.Namespace == namespace
+	})
+}
+
+func (db Database) latestRevision(check func(Revision) bool) (Revision, bool) {
+	var latest Revision
+	for _, rev := range db.SelectFromRevision(check) {
+		if rev.Number > latest.Number {
+			latest = rev
+		}
+	}
+	return latest, latest.Number > 0
+}
+
+func (r Revision) getID() int {
+	return r.ID
+}
+
+func (r Revision) String() string {
+	return fmt.Sprintf("Revision-%d{%d, %s, %s, User=%s}", r.ID, r.Number,
+		r.Namespace, r.Time.Format(time.RFC3339), r.User)
+}
+
+func (r Revision) less(o row) bool {
+	return r.Number < o.(Revision).Number
+}
@@ -34,8 +34,15 @@ var PlacementTable = TableType(reflect.TypeOf(Placement{}).String())
 // ACLTable is the type of the ACL table.
 var ACLTable = TableType(reflect.TypeOf(ACL{}).String())
 
+// ViolationTable is the type of the violation table.
+var ViolationTable = TableType(reflect.TypeOf(Violation{}).String())
+
+// RevisionTable is the type of the revision table.
+var RevisionTable = TableType(reflect.TypeOf(Revision{}).String())
+
 var allTables = []TableType{ClusterTable, MachineTable, ContainerTable, MinionTable,
-	ConnectionTable, LabelTable, EtcdTable, PlacementTable, ACLTable}
+	ConnectionTable, LabelTable, EtcdTable, PlacementTable, ACLTable,
+	ViolationTable, RevisionTable}
 
 type table struct {
 	rows map[int]row
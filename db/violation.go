@@ -0,0 +1,77 @@
+package db
+
+import (
+	"fmt"
+)
+
+// A Violation is an invariant from the spec that doesn't hold for the running
+// cluster, e.g. because an ACL is missing.  The leader re-evaluates the invariants
+// periodically and keeps one row per failing invariant.
+type Violation struct {
+	ID int
+
+	Invariant      string
+	Counterexample string
+}
+
+// ViolationSlice is an alias for []Violation to allow for joins
+type ViolationSlice []Violation
+
+// InsertViolation creates a new violation row and inserts it into the database.
+func (db Database) InsertViolation() Violation {
+	result := Violation{ID: db.nextID()}
+	db.insert(result)
+	return result
+}
+
+// SelectFromViolation gets all violations in the database that satisfy 'check'.
+func (db Database) SelectFromViolation(check func(Violation) bool) []Violation {
+	var result []Violation
+	for _, row := range db.tables[ViolationTable].rows {
+		if check == nil || check(row.(Violation)) {
+			result = append(result, row.(Violation))
+		}
+	}
+
+	return result
+}
+
+// SelectFromViolation gets all violations in the database connection that satisfy
+// 'check'.
+func (conn Conn) SelectFromViolation(check func(Violation) bool) []Violation {
+	var violations []Violation
+	conn.Transact(func(view Database) error {
+		violations = view.SelectFromViolation(check)
+		return nil
+	})
+	return violations
+}
+
+func (v Violation) getID() int {
+	return v.ID
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("Violation-%d{%s: %s}", v.ID, v.Invariant, v.Counterexample)
+}
+
+func (v Violation) less(r row) bool {
+	o := r.(Violation)
+
+	switch {
+	case v.Invariant != o.Invariant:
+		return v.Invariant < o.Invariant
+	default:
+		return v.ID < o.ID
+	}
+}
+
+// Get returns the value contained at the given index
+func (vs ViolationSlice) Get(ii int) interface{} {
+	return vs[ii]
+}
+
+// Len returns the number of items in the slice.
+func (vs ViolationSlice) Len() int {
+	return len(vs)
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/NetSys/quilt/cluster/provider"
 	"github.com/NetSys/quilt/db"
@@ -15,11 +16,24 @@ import (
 var myIP = util.MyIP
 var defaultDiskSize = 32
 
+// DefaultNamespace is the namespace of specs that don't specify one.
+const DefaultNamespace = "default-namespace"
+
+// Namespace returns the namespace `spec` is deployed in.  The daemon manages each
+// namespace independently, so a spec only affects the machines in its namespace.
+func Namespace(spec stitch.Stitch) string {
+	if namespace := spec.QueryNamespace(); namespace != "" {
+		return namespace
+	}
+	return DefaultNamespace
+}
+
 // UpdatePolicy executes transactions on 'conn' to make it reflect a new policy,
-// 'stitch'.
-func UpdatePolicy(conn db.Conn, stitch stitch.Stitch) error {
+// 'stitch'.  If the policy changes the spec, it's recorded in the revision history
+// as applied by `user`.
+func UpdatePolicy(conn db.Conn, stitch stitch.Stitch, user string) error {
 	txn := func(db db.Database) error {
-		return updateTxn(db, stitch)
+		return updateTxn(db, stitch, user)
 	}
 
 	if err := conn.Transact(txn); err != nil {
@@ -29,11 +43,131 @@ func UpdatePolicy(conn db.Conn, stitch stitch.Stitch) error {
 	return nil
 }
 
-func updateTxn(view db.Database, stitch stitch.Stitch) error {
+// Protection limits the changes a new policy may make to a running deployment.
+type Protection struct {
+	// The largest fraction of the running machines a policy may terminate.
+	MaxTerminateFraction float64
+
+	// Force permits a policy to terminate more than MaxTerminateFraction of the
+	// machines.  Protected machines and labels may never be removed.
+	Force bool
+}
+
+// UpdatePolicyProtected is like UpdatePolicy, except that it leaves the policy
+// unchanged if applying `stitch` would violate `prot`.  It returns the reason the
+// policy was refused, if it was.
+func UpdatePolicyProtected(conn db.Conn, stitch stitch.Stitch, prot Protection,
+	user string) (string, error) {
+
+	// Compiling the running spec may fetch its imports, so it's done outside of
+	// the transaction, which then checks that the spec hasn't changed since.
+	namespace := Namespace(stitch)
+	for {
+		running, protected := protectedLabels(conn, namespace)
+
+		var refusal string
+		var changed bool
+		err := conn.Transact(func(view db.Database) error {
+			cluster, err := view.GetCluster(namespace)
+			if changed = err == nil && cluster.Spec != running; changed {
+				return nil
+			}
+
+			refusal = checkProtection(view, stitch, prot, protected)
+			if refusal != "" {
+				return nil
+			}
+			return updateTxn(view, stitch, user)
+		})
+
+		if !changed {
+			return refusal, err
+		}
+	}
+}
+
+// protectedLabels returns the spec running in `namespace`, and the names of its
+// protected labels.
+func protectedLabels(conn db.Conn, namespace string) (spec string,
+	protected []string) {
+
+	conn.Transact(func(view db.Database) error {
+		if cluster, err := view.GetCluster(namespace); err == nil {
+			spec = cluster.Spec
+		}
+		return nil
+	})
+
+	if spec == "" {
+		return spec, nil
+	}
+
+	// The running spec was accepted when it was applied, so failing to parse it
+	// now means nothing was protected.
+	curr, err := stitch.New(spec, stitch.DefaultImportGetter)
+	if err != nil {
+		return spec, nil
+	}
+
+	for _, label := range curr.QueryLabels() {
+		if label.Protected {
+			protected = append(protected, label.Name)
+		}
+	}
+	return spec, protected
+}
+
+// checkProtection returns why applying `spec` would violate `prot`, or the empty
+// string if it wouldn't.  `protected` are the labels of the running spec that may
+// not be removed.
+func checkProtection(view db.Database, spec stitch.Stitch, prot Protection,
+	protected []string) string {
+
+	namespace := Namespace(spec)
+	dbMachines := view.SelectFromMachine(func(m db.Machine) bool {
+		return m.Namespace == namespace
+	})
+	_, terminate := PlanMachines(spec, dbMachines)
+	for _, m := range terminate {
+		if m.Protected {
+			return fmt.Sprintf("the spec would terminate protected "+
+				"machine %s", m)
+		}
+	}
+
+	labels := map[string]struct{}{}
+	for _, label := range spec.QueryLabels() {
+		labels[label.Name] = struct{}{}
+	}
+
+	for _, name := range protected {
+		if _, ok := labels[name]; !ok {
+			return fmt.Sprintf("the spec would remove protected service %q",
+				name)
+		}
+	}
+
+	if prot.Force || len(dbMachines) == 0 {
+		return ""
+	}
+
+	fraction := float64(len(terminate)) / float64(len(dbMachines))
+	if fraction > prot.MaxTerminateFraction {
+		return fmt.Sprintf("the spec would terminate %d of the %d machines, "+
+			"more than the maximum of %.0f%%; run with -force to apply it "+
+			"anyway", len(terminate), len(dbMachines),
+			prot.MaxTerminateFraction*100)
+	}
+	return ""
+}
+
+func updateTxn(view db.Database, stitch stitch.Stitch, user string) error {
 	if err := clusterTxn(view, stitch); err != nil {
 		return err
 	}
 
+	revisionTxn(view, stitch, user)
+
 	if err := machineTxn(view, stitch); err != nil {
 		return err
 	}
@@ -46,14 +180,13 @@ func updateTxn(view db.Database, stitch stitch.Stitch) error {
 }
 
 func clusterTxn(view db.Database, stitch stitch.Stitch) error {
-	namespace := stitch.QueryNamespace()
-	if namespace == "" {
-		namespace = "default-namespace"
+	namespace := Namespace(stitch)
+	if stitch.QueryNamespace() == "" {
 		msg := "policy did not specify 'Namespace', defaulting to '%s'"
 		log.Warn(fmt.Sprintf(msg, namespace))
 	}
 
-	cluster, err := view.GetCluster()
+	cluster, err := view.GetCluster(namespace)
 	if err != nil {
 		cluster = view.InsertCluster()
 	}
@@ -64,37 +197,73 @@ func clusterTxn(view db.Database, stitch stitch.Stitch) error {
 	return nil
 }
 
+// revisionTxn records `stitch` in the revision history, unless it's the same as the
+// latest revision in its namespace.
+func revisionTxn(view db.Database, stitch stitch.Stitch, user string) {
+	namespace := Namespace(stitch)
+	current, ok := view.LatestRevisionIn(namespace)
+	if ok && current.Spec == stitch.String() {
+		return
+	}
+
+	latest, _ := view.LatestRevision()
+	rev := view.InsertRevision()
+	rev.Number = latest.Number + 1
+	rev.Namespace = namespace
+	rev.Time = time.Now()
+	rev.User = user
+	rev.Spec = stitch.String()
+	view.Commit(rev)
+}
+
 func aclTxn(view db.Database, specHandle stitch.Stitch) error {
-	aclRow, err := view.GetACL()
+	namespace := Namespace(specHandle)
+	aclRow, err := view.GetACL(namespace)
 	if err != nil {
 		aclRow = view.InsertACL()
+		aclRow.Namespace = namespace
 	}
 
-	aclRow.Admin = resolveACLs(specHandle.QueryAdminACL())
+	acl := PlanACL(specHandle)
+	aclRow.Admin = acl.Admin
+	aclRow.ApplicationPorts = acl.ApplicationPorts
 
+	view.Commit(aclRow)
+	return nil
+}
+
+// PlanACL returns the ACL that `specHandle` would install.
+func PlanACL(specHandle stitch.Stitch) db.ACL {
 	var applicationPorts []db.PortRange
 	for _, conn := range specHandle.QueryConnections() {
 		if conn.From == stitch.PublicInternetLabel {
 			applicationPorts = append(applicationPorts, db.PortRange{
 				MinPort: conn.MinPort,
 				MaxPort: conn.MaxPort,
+				CidrIP:  conn.CidrIP,
 			})
 		}
 	}
-	aclRow.ApplicationPorts = applicationPorts
 
-	view.Commit(aclRow)
-	return nil
+	return db.ACL{
+		Admin:            resolveACLs(specHandle.QueryAdminACL()),
+		ApplicationPorts: applicationPorts,
+	}
 }
 
 // toDBMachine converts machines specified in the Stitch into db.Machines that can
 // be compared against what's already in the db.
 // Specifically, it sets the role of the db.Machine, the size (which may depend
-// on RAM and CPU constraints), and the provider.
-// Additionally, it skips machines with invalid roles, sizes or providers.
-func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
+// on RAM, CPU and GPU constraints, and on prices in the machine's region), and the
+// provider.
+// Additionally, it skips machines with invalid roles, sizes or providers.  The
+// machines whose sizes were chosen, rather than given by the spec, are mapped from
+// their index in the result to the stitch machine they were converted from.
+func toDBMachine(machines []stitch.Machine, maxPrice float64) (
+	dbMachines []db.Machine, chosen map[int]stitch.Machine) {
+
 	var hasMaster, hasWorker bool
-	var dbMachines []db.Machine
+	chosen = map[int]stitch.Machine{}
 	for _, stitchm := range machines {
 		var m db.Machine
 
@@ -114,16 +283,34 @@ func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
 			continue
 		}
 		m.Provider = p
+
+		market, err := db.ParseMarket(p, stitchm.Market)
+		if err != nil {
+			log.WithError(err).Error("Error parsing market.")
+			continue
+		}
+		m.Market = market
 		m.Size = stitchm.Size
 
+		m.MaxPrice = stitchm.MaxPrice
+		if m.MaxPrice == 0 {
+			m.MaxPrice = maxPrice
+		}
+
+		// Prices vary by region, so the region must be known before the
+		// size is chosen.
+		m.Region = stitchm.Region
+		m = provider.DefaultRegion(m)
+
 		if m.Size == "" {
-			providerInst := provider.New(p)
-			m.Size = providerInst.ChooseSize(
-				stitchm.RAM, stitchm.CPU, maxPrice)
+			var reason string
+			m.Size, reason = chooseSize(stitchm, m)
 			if m.Size == "" {
-				log.Errorf("No valid size for %v, skipping.", m)
+				log.Errorf("No valid size for %v, as %s, skipping.", m,
+					reason)
 				continue
 			}
+			chosen[len(dbMachines)] = stitchm
 		}
 
 		m.DiskSize = stitchm.DiskSize
@@ -132,30 +319,161 @@ func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
 		}
 
 		m.SSHKeys = stitchm.SSHKeys
-		m.Region = stitchm.Region
-		dbMachines = append(dbMachines, provider.DefaultRegion(m))
+		m.Image = stitchm.Image
+		m.BootScriptExtra = stitchm.BootScriptExtra
+		m.Host = stitchm.Host
+		m.Protected = stitchm.Protected
+		dbMachines = append(dbMachines, m)
 	}
 
 	if hasMaster && !hasWorker {
 		log.Warning("A Master was specified but no workers.")
-		return nil
+		return nil, nil
 	} else if hasWorker && !hasMaster {
 		log.Warning("A Worker was specified but no masters.")
-		return nil
+		return nil, nil
 	}
 
-	return dbMachines
+	return dbMachines, chosen
+}
+
+// chooseSize returns the size `m` should boot as, given the preferences of the
+// `stitchm` it was converted from, and why.
+func chooseSize(stitchm stitch.Machine, m db.Machine) (string, string) {
+	stitchm.Region = m.Region
+	stitchm.MaxPrice = m.MaxPrice
+	return provider.New(m.Provider).ChooseSize(stitchm)
+}
+
+// ExplainSizes describes the size that would be chosen for each machine in `spec`
+// that doesn't give one, and why.
+func ExplainSizes(spec stitch.Stitch) []string {
+	var explanations []string
+	for i, stitchm := range spec.QueryMachines() {
+		p, err := db.ParseProvider(stitchm.Provider)
+		if err != nil || stitchm.Size != "" {
+			continue
+		}
+
+		m := db.Machine{Provider: p, Region: stitchm.Region,
+			MaxPrice: stitchm.MaxPrice}
+		if m.MaxPrice == 0 {
+			m.MaxPrice = spec.QueryMaxPrice()
+		}
+		m = provider.DefaultRegion(m)
+
+		_, reason := chooseSize(stitchm, m)
+		explanations = append(explanations, fmt.Sprintf("machine %d (%s %s): %s",
+			i+1, p, stitchm.Role, reason))
+	}
+	return explanations
 }
 
 func machineTxn(view db.Database, stitch stitch.Stitch) error {
+	pairs, bootList, terminateList := joinMachines(stitch,
+		view.SelectFromMachine(nil))
+
+	for _, toTerminate := range terminateList {
+		toTerminate := toTerminate.(db.Machine)
+		view.Remove(toTerminate)
+	}
+
+	for _, bootSet := range bootList {
+		bootSet := bootSet.(db.Machine)
+
+		pairs = append(pairs, join.Pair{L: bootSet, R: view.InsertMachine()})
+	}
+
+	for _, pair := range pairs {
+		stitchMachine := pair.L.(db.Machine)
+		dbMachine := pair.R.(db.Machine)
+
+		dbMachine.Namespace = stitchMachine.Namespace
+		dbMachine.Role = stitchMachine.Role
+		dbMachine.Size = stitchMachine.Size
+		dbMachine.DiskSize = stitchMachine.DiskSize
+		dbMachine.Market = stitchMachine.Market
+		dbMachine.MaxPrice = stitchMachine.MaxPrice
+		dbMachine.Image = stitchMachine.Image
+		dbMachine.BootScriptExtra = stitchMachine.BootScriptExtra
+		dbMachine.Host = stitchMachine.Host
+		dbMachine.Provider = stitchMachine.Provider
+		dbMachine.Region = stitchMachine.Region
+		dbMachine.SSHKeys = stitchMachine.SSHKeys
+		dbMachine.Protected = stitchMachine.Protected
+		view.Commit(dbMachine)
+	}
+
+	return nil
+}
+
+// PlanMachines returns the machines that applying `stitch` would boot, and the
+// machines in `dbMachines` that it would terminate.  Machines in other namespaces
+// are ignored.
+func PlanMachines(stitch stitch.Stitch, dbMachines []db.Machine) (boot,
+	terminate []db.Machine) {
+
+	_, bootList, terminateList := joinMachines(stitch, dbMachines)
+	for _, m := range bootList {
+		boot = append(boot, m.(db.Machine))
+	}
+	for _, m := range terminateList {
+		terminate = append(terminate, m.(db.Machine))
+	}
+	return boot, terminate
+}
+
+// joinMachines pairs the machines requested by `stitch` with those in `allMachines`
+// in the same namespace.  The unpaired stitch machines must be booted, and the
+// unpaired database machines terminated.  Draining machines are left out, as the
+// cluster has already booted their replacements, and removes them once they're
+// gone.
+func joinMachines(stitch stitch.Stitch, allMachines []db.Machine) (pairs []join.Pair,
+	bootList, terminateList []interface{}) {
+
+	namespace := Namespace(stitch)
+	var dbMachines []db.Machine
+	for _, m := range allMachines {
+		if m.Namespace == namespace && !m.Draining {
+			dbMachines = append(dbMachines, m)
+		}
+	}
+
 	// XXX: How best to deal with machines that don't specify enough information?
 	maxPrice := stitch.QueryMaxPrice()
-	stitchMachines := toDBMachine(stitch.QueryMachines(), maxPrice)
+	stitchMachines, chosen := toDBMachine(stitch.QueryMachines(), maxPrice)
+	for i := range stitchMachines {
+		stitchMachines[i].Namespace = namespace
+	}
+
+	// A price change can make a different size the cheapest, so a machine whose
+	// size was chosen keeps the size it's running as while that still fits.  Only
+	// the machines that must be booted get the newly chosen size.
+	type sizeFit struct {
+		index int
+		size  string
+	}
+	fits := map[sizeFit]bool{}
+	keepsSize := func(i int, dbMachine db.Machine) bool {
+		stitchm, ok := chosen[i]
+		if !ok {
+			return false
+		}
 
-	dbMachines := view.SelectFromMachine(nil)
+		key := sizeFit{i, dbMachine.Size}
+		if fit, ok := fits[key]; ok {
+			return fit
+		}
+
+		stitchm.Size = dbMachine.Size
+		size, _ := chooseSize(stitchm, stitchMachines[i])
+		fits[key] = size == dbMachine.Size
+		return fits[key]
+	}
 
 	scoreFun := func(left, right interface{}) int {
-		stitchMachine := left.(db.Machine)
+		i := left.(int)
+		stitchMachine := stitchMachines[i]
 		dbMachine := right.(db.Machine)
 
 		switch {
@@ -163,12 +481,26 @@ func machineTxn(view db.Database, stitch stitch.Stitch) error {
 			return -1
 		case dbMachine.Region != stitchMachine.Region:
 			return -1
-		case dbMachine.Size != "" && stitchMachine.Size != dbMachine.Size:
+		case dbMachine.Size != "" && stitchMachine.Size != dbMachine.Size &&
+			!keepsSize(i, dbMachine):
 			return -1
 		case dbMachine.Role != db.None && dbMachine.Role != stitchMachine.Role:
 			return -1
 		case dbMachine.DiskSize != stitchMachine.DiskSize:
 			return -1
+		case dbMachine.Market != stitchMachine.Market:
+			return -1
+		case dbMachine.Market == db.Spot &&
+			dbMachine.MaxPrice != stitchMachine.MaxPrice:
+			// Only spot machines are bid for, so other machines just
+			// take the new price.
+			return -1
+		case dbMachine.Image != stitchMachine.Image:
+			return -1
+		case dbMachine.BootScriptExtra != stitchMachine.BootScriptExtra:
+			return -1
+		case dbMachine.Host != stitchMachine.Host:
+			return -1
 		case dbMachine.PrivateIP == "":
 			return 2
 		case dbMachine.PublicIP == "":
@@ -178,33 +510,24 @@ func machineTxn(view db.Database, stitch stitch.Stitch) error {
 		}
 	}
 
-	pairs, bootList, terminateList := join.Join(stitchMachines, dbMachines, scoreFun)
-
-	for _, toTerminate := range terminateList {
-		toTerminate := toTerminate.(db.Machine)
-		view.Remove(toTerminate)
+	indices := make([]int, len(stitchMachines))
+	for i := range indices {
+		indices[i] = i
 	}
 
-	for _, bootSet := range bootList {
-		bootSet := bootSet.(db.Machine)
-
-		pairs = append(pairs, join.Pair{L: bootSet, R: view.InsertMachine()})
+	pairs, lonelyIndices, terminateList := join.Join(indices, dbMachines, scoreFun)
+	for i, pair := range pairs {
+		stitchMachine := stitchMachines[pair.L.(int)]
+		if dbMachine := pair.R.(db.Machine); dbMachine.Size != "" {
+			stitchMachine.Size = dbMachine.Size
+		}
+		pairs[i].L = stitchMachine
 	}
 
-	for _, pair := range pairs {
-		stitchMachine := pair.L.(db.Machine)
-		dbMachine := pair.R.(db.Machine)
-
-		dbMachine.Role = stitchMachine.Role
-		dbMachine.Size = stitchMachine.Size
-		dbMachine.DiskSize = stitchMachine.DiskSize
-		dbMachine.Provider = stitchMachine.Provider
-		dbMachine.Region = stitchMachine.Region
-		dbMachine.SSHKeys = stitchMachine.SSHKeys
-		view.Commit(dbMachine)
+	for _, i := range lonelyIndices {
+		bootList = append(bootList, stitchMachines[i.(int)])
 	}
-
-	return nil
+	return pairs, bootList, terminateList
 }
 
 func resolveACLs(acls []string) []string {
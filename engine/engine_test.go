@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/NetSys/quilt/catalog"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/join"
 	"github.com/NetSys/quilt/stitch"
@@ -26,9 +28,9 @@ func TestEngine(t *testing.T) {
 	code := pre + `deployment.deploy(baseMachine.asMaster().replicate(2));
 		deployment.deploy(baseMachine.asWorker().replicate(3));`
 
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err := conn.Transact(func(view db.Database) error {
-		acl, err := view.GetACL()
+		acl, err := view.GetACL("namespace")
 		masters := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
 		})
@@ -59,7 +61,7 @@ func TestEngine(t *testing.T) {
 	code = pre + `deployment.deploy(baseMachine.asMaster().replicate(4));
 		deployment.deploy(baseMachine.asWorker().replicate(5));`
 
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err = conn.Transact(func(view db.Database) error {
 		masters := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
@@ -110,7 +112,7 @@ func TestEngine(t *testing.T) {
 	/* Also verify that masters and workers decrease properly. */
 	code = pre + `deployment.deploy(baseMachine.asMaster());
 		deployment.deploy(baseMachine.asWorker());`
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err = conn.Transact(func(view db.Database) error {
 		masters := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
@@ -134,17 +136,18 @@ func TestEngine(t *testing.T) {
 		t.Error(err.Error())
 	}
 
-	/* Empty Namespace does nothing. */
+	/* An empty namespace deploys to the default namespace, leaving the machines
+	 * in "namespace" alone. */
 	code = pre + `deployment.namespace = "";
 		deployment.deploy(baseMachine.asMaster());
 		deployment.deploy(baseMachine.asWorker());`
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err = conn.Transact(func(view db.Database) error {
 		masters := view.SelectFromMachine(func(m db.Machine) bool {
-			return m.Role == db.Master
+			return m.Role == db.Master && m.Namespace == "namespace"
 		})
 		workers := view.SelectFromMachine(func(m db.Machine) bool {
-			return m.Role == db.Worker
+			return m.Role == db.Worker && m.Namespace == "namespace"
 		})
 
 		if len(masters) != 1 || masters[0].CloudID != "1" ||
@@ -156,7 +159,20 @@ func TestEngine(t *testing.T) {
 			workers[0].PublicIP != "2" || workers[0].PrivateIP != "3" {
 			return fmt.Errorf("bad workers: %s", spew.Sdump(workers))
 		}
-		return nil
+
+		defaults := view.SelectFromMachine(func(m db.Machine) bool {
+			return m.Namespace == DefaultNamespace && m.CloudID == ""
+		})
+		if len(defaults) != 2 {
+			return fmt.Errorf("bad default namespace machines: %s",
+				spew.Sdump(defaults))
+		}
+
+		if _, err := view.GetCluster(DefaultNamespace); err != nil {
+			return err
+		}
+		_, err := view.GetCluster("namespace")
+		return err
 	})
 	if err != nil {
 		t.Error(err.Error())
@@ -164,13 +180,13 @@ func TestEngine(t *testing.T) {
 
 	/* Verify things go to zero. */
 	code = pre + `deployment.deploy(baseMachine.asWorker())`
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err = conn.Transact(func(view db.Database) error {
 		masters := view.SelectFromMachine(func(m db.Machine) bool {
-			return m.Role == db.Master
+			return m.Role == db.Master && m.Namespace == "namespace"
 		})
 		workers := view.SelectFromMachine(func(m db.Machine) bool {
-			return m.Role == db.Worker
+			return m.Role == db.Worker && m.Namespace == "namespace"
 		})
 
 		if len(masters) != 0 {
@@ -205,7 +221,7 @@ func TestEngine(t *testing.T) {
 		new Machine({provider: "Vagrant", size: "v.large", role: "Master"}),
 		new Machine({provider: "Amazon", size: "m4.large", role: "Worker"}),
 		new Machine({provider: "Google", size: "g.large", role: "Worker"})]);`
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err = conn.Transact(func(view db.Database) error {
 		masters := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
@@ -231,7 +247,7 @@ func TestEngine(t *testing.T) {
 	code = `deployment.deploy([
 		new Machine({provider: "Amazon", size: "m4.large", role: "Master"}),
 		new Machine({provider: "Amazon", size: "m4.large", role: "Worker"})]);`
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err = conn.Transact(func(view db.Database) error {
 		masters := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
@@ -253,7 +269,7 @@ func TestContainer(t *testing.T) {
 	conn := db.New()
 
 	check := func(code string, red, blue, yellow int) error {
-		UpdatePolicy(conn, prog(t, code))
+		UpdatePolicy(conn, prog(t, code), "")
 		return conn.Transact(func(view db.Database) error {
 			var redCount, blueCount, yellowCount int
 
@@ -338,7 +354,7 @@ func TestSort(t *testing.T) {
 
 	UpdatePolicy(conn, prog(t, pre+`
 	deployment.deploy(baseMachine.asMaster().replicate(3));
-	deployment.deploy(baseMachine.asWorker().replicate(1));`))
+	deployment.deploy(baseMachine.asWorker().replicate(1));`), "")
 	err := conn.Transact(func(view db.Database) error {
 		machines := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
@@ -363,7 +379,7 @@ func TestSort(t *testing.T) {
 
 	UpdatePolicy(conn, prog(t, pre+`
 	deployment.deploy(baseMachine.asMaster().replicate(2));
-	deployment.deploy(baseMachine.asWorker().replicate(1));`))
+	deployment.deploy(baseMachine.asWorker().replicate(1));`), "")
 	err = conn.Transact(func(view db.Database) error {
 		machines := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
@@ -388,7 +404,7 @@ func TestSort(t *testing.T) {
 
 	UpdatePolicy(conn, prog(t, pre+`
 	deployment.deploy(baseMachine.asMaster().replicate(1));
-	deployment.deploy(baseMachine.asWorker().replicate(1));`))
+	deployment.deploy(baseMachine.asWorker().replicate(1));`), "")
 	err = conn.Transact(func(view db.Database) error {
 		machines := view.SelectFromMachine(func(m db.Machine) bool {
 			return m.Role == db.Master
@@ -427,9 +443,9 @@ func TestACLs(t *testing.T) {
 	myIP = func() (string, error) {
 		return "5.6.7.8", nil
 	}
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err := conn.Transact(func(view db.Database) error {
-		acl, err := view.GetACL()
+		acl, err := view.GetACL("default-namespace")
 
 		if err != nil {
 			return err
@@ -450,9 +466,9 @@ func TestACLs(t *testing.T) {
 	myIP = func() (string, error) {
 		return "", errors.New("")
 	}
-	UpdatePolicy(conn, prog(t, code))
+	UpdatePolicy(conn, prog(t, code), "")
 	err = conn.Transact(func(view db.Database) error {
-		acl, err := view.GetACL()
+		acl, err := view.GetACL("default-namespace")
 
 		if err != nil {
 			return err
@@ -470,6 +486,354 @@ func TestACLs(t *testing.T) {
 	}
 }
 
+func TestProtection(t *testing.T) {
+	conn := db.New()
+	prot := Protection{MaxTerminateFraction: 0.5}
+
+	pre := `var deployment = createDeployment({namespace: "namespace"});
+		var baseMachine = new Machine({provider: "Amazon", size: "m4.large"});
+		deployment.deploy(baseMachine.asMaster());`
+	checkRefusal := func(code, exp string) {
+		refusal, err := UpdatePolicyProtected(conn, prog(t, code), prot, "")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		} else if refusal != exp {
+			t.Errorf("expected refusal %q, got %q", exp, refusal)
+		}
+	}
+	countWorkers := func() int {
+		var workers []db.Machine
+		conn.Transact(func(view db.Database) error {
+			workers = view.SelectFromMachine(func(m db.Machine) bool {
+				return m.Role == db.Worker
+			})
+			return nil
+		})
+		return len(workers)
+	}
+
+	web := `deployment.deploy(new Service("web", [new Container("nginx")])
+		.protect());`
+	code := pre + web + `deployment.deploy(baseMachine.asWorker().replicate(3));`
+	checkRefusal(code, "")
+	if workers := countWorkers(); workers != 3 {
+		t.Errorf("expected 3 workers, got %d", workers)
+	}
+
+	// Without workers, the master is terminated too, which is too many.
+	checkRefusal(pre+web, "the spec would terminate 4 of the 4 machines, more "+
+		"than the maximum of 50%; run with -force to apply it anyway")
+	if workers := countWorkers(); workers != 3 {
+		t.Errorf("refused policy changed the workers: %d", workers)
+	}
+
+	// Terminating 1 of the 4 machines is fine, but the protected service may not
+	// be removed.
+	code = pre + `deployment.deploy(baseMachine.asWorker().replicate(2));`
+	checkRefusal(code, `the spec would remove protected service "web"`)
+
+	code = pre + `deployment.deploy(baseMachine.asWorker().replicate(2));
+		deployment.deploy(new Service("web", [new Container("nginx")]));`
+	checkRefusal(code, "")
+	if workers := countWorkers(); workers != 2 {
+		t.Errorf("expected 2 workers, got %d", workers)
+	}
+
+	// Protected machines may not be terminated, even by a forced policy.
+	code = pre + `deployment.deploy(baseMachine.asWorker().replicate(2));
+		deployment.deploy(new Machine({provider: "Amazon", size: "m4.xlarge",
+			role: "Worker", protected: true}));`
+	checkRefusal(code, "")
+
+	prot.Force = true
+	code = pre + `deployment.deploy(baseMachine.asWorker().replicate(2));`
+	refusal, _ := UpdatePolicyProtected(conn, prog(t, code), prot, "")
+	if !strings.HasPrefix(refusal, "the spec would terminate protected machine") {
+		t.Errorf("expected a protected machine refusal, got %q", refusal)
+	}
+
+	// Forced policies may terminate any unprotected machine.
+	code = pre + `deployment.deploy(baseMachine.asWorker().replicate(2));
+		deployment.deploy(new Machine({provider: "Amazon", size: "m4.xlarge",
+			role: "Worker"}));`
+	checkRefusal(code, "")
+	checkRefusal(pre, "")
+	if workers := countWorkers(); workers != 0 {
+		t.Errorf("expected no workers, got %d", workers)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	conn := db.New()
+
+	first := `createDeployment({namespace: "ns"});`
+	second := `createDeployment({namespace: "ns", adminACL: ["1.2.3.4/32"]});`
+	other := `createDeployment({namespace: "other"});`
+	UpdatePolicy(conn, prog(t, first), "alice")
+	UpdatePolicy(conn, prog(t, first), "alice")
+	UpdatePolicy(conn, prog(t, second), "bob")
+	UpdatePolicy(conn, prog(t, other), "dave")
+	UpdatePolicy(conn, prog(t, first), "carol")
+
+	// Revisions are numbered across namespaces.
+	UpdatePolicy(conn, prog(t, other), "dave")
+
+	var revisions []db.Revision
+	conn.Transact(func(view db.Database) error {
+		revisions = view.SelectFromRevision(nil)
+		return nil
+	})
+	// Re-applying the current spec doesn't create a revision.
+	exp := map[int]struct{ namespace, user, spec string }{
+		1: {"ns", "alice", first},
+		2: {"ns", "bob", second},
+		3: {"other", "dave", other},
+		4: {"ns", "carol", first},
+	}
+	if len(revisions) != len(exp) {
+		t.Fatalf("expected %d revisions, got %v", len(exp), revisions)
+	}
+	for _, rev := range revisions {
+		e, ok := exp[rev.Number]
+		if !ok || rev.Namespace != e.namespace || rev.User != e.user ||
+			rev.Spec != e.spec || rev.Time.IsZero() {
+			t.Errorf("bad revision: %v (%s)", rev, rev.Spec)
+		}
+	}
+}
+
+func TestMarket(t *testing.T) {
+	conn := db.New()
+	markets := func() map[db.Role]db.Market {
+		result := map[db.Role]db.Market{}
+		conn.Transact(func(view db.Database) error {
+			for _, m := range view.SelectFromMachine(nil) {
+				result[m.Role] = m.Market
+			}
+			return nil
+		})
+		return result
+	}
+
+	code := `var baseMachine = new Machine({provider: "Amazon", size: "m4.large"});
+		deployment.deploy(baseMachine.asWorker());`
+	master := `deployment.deploy(baseMachine.asMaster());`
+	UpdatePolicy(conn, prog(t, code+master), "")
+	exp := map[db.Role]db.Market{db.Master: db.Spot, db.Worker: db.Spot}
+	if got := markets(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected markets %v, got %v", exp, got)
+	}
+
+	// Moving a machine to another market replaces it.
+	master = `deployment.deploy(new Machine({provider: "Amazon", size: "m4.large",
+		role: "Master", market: "ondemand"}));`
+	UpdatePolicy(conn, prog(t, code+master), "")
+	exp = map[db.Role]db.Market{db.Master: db.OnDemand, db.Worker: db.Spot}
+	if got := markets(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected markets %v, got %v", exp, got)
+	}
+
+	// Providers other than Amazon have no markets.
+	master = `deployment.deploy(new Machine({provider: "Vagrant", role: "Master",
+		market: "ondemand"}));`
+	UpdatePolicy(conn, prog(t, code+master), "")
+	exp = map[db.Role]db.Market{db.Worker: db.Spot}
+	if got := markets(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected markets %v, got %v", exp, got)
+	}
+}
+
+func TestMaxPrice(t *testing.T) {
+	conn := db.New()
+	code := `var deployment = createDeployment({maxPrice: 0.5});
+		deployment.deploy(new Machine({provider: "Amazon", size: "m4.large",
+			role: "Master", maxPrice: 0.08}));
+		deployment.deploy(new Machine({provider: "Amazon", size: "m4.large",
+			role: "Worker"}));`
+	UpdatePolicy(conn, prog(t, code), "")
+
+	// Machines without a MaxPrice get the deployment's.
+	prices := map[db.Role]float64{}
+	conn.Transact(func(view db.Database) error {
+		for _, m := range view.SelectFromMachine(nil) {
+			prices[m.Role] = m.MaxPrice
+		}
+		return nil
+	})
+	exp := map[db.Role]float64{db.Master: 0.08, db.Worker: 0.5}
+	if !reflect.DeepEqual(prices, exp) {
+		t.Errorf("expected max prices %v, got %v", exp, prices)
+	}
+
+	// Changing the price replaces spot machines, whose bid it is, but not
+	// others.
+	var machines []db.Machine
+	conn.Transact(func(view db.Database) error {
+		machines = view.SelectFromMachine(nil)
+		return nil
+	})
+	code = `var deployment = createDeployment({maxPrice: 0.6});
+		deployment.deploy(new Machine({provider: "Amazon", size: "m4.large",
+			role: "Master", market: "ondemand"}));
+		deployment.deploy(new Machine({provider: "Amazon", size: "m4.large",
+			role: "Worker"}));`
+	for i := range machines {
+		if machines[i].Role == db.Master {
+			machines[i].Market = db.OnDemand
+		}
+	}
+	boot, terminate := PlanMachines(prog(t, code), machines)
+	if len(boot) != 1 || boot[0].Role != db.Worker || boot[0].MaxPrice != 0.6 ||
+		len(terminate) != 1 || terminate[0].Role != db.Worker {
+		t.Errorf("expected only the spot worker to be replaced, got boot %v "+
+			"and terminate %v", boot, terminate)
+	}
+}
+
+func TestKeepSize(t *testing.T) {
+	conn := db.New()
+	code := `var baseMachine = new Machine({provider: "Amazon",
+			ram: new Range(4, 16)});
+		deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(baseMachine.asWorker());`
+	UpdatePolicy(conn, prog(t, code), "")
+
+	var machines []db.Machine
+	conn.Transact(func(view db.Database) error {
+		machines = view.SelectFromMachine(nil)
+		return nil
+	})
+	if len(machines) != 2 || machines[0].Size == "" {
+		t.Fatalf("expected two sized machines, got %v", machines)
+	}
+	cheapest := machines[0].Size
+
+	// A machine running as a size that still fits isn't replaced, even if it's
+	// no longer the cheapest.
+	var fits, tooSmall string
+	for _, d := range catalog.InRegion(catalog.Get(db.Amazon),
+		machines[0].Region) {
+		switch {
+		case d.RAM >= 4 && d.RAM <= 16 && d.Size != cheapest:
+			fits = d.Size
+		case d.RAM < 4:
+			tooSmall = d.Size
+		}
+	}
+	if fits == "" || tooSmall == "" {
+		t.Fatal("expected the catalog to have sizes that do and don't fit")
+	}
+
+	machines[0].Size = fits
+	machines[1].Size = tooSmall
+	conn.Transact(func(view db.Database) error {
+		for _, m := range machines {
+			view.Commit(m)
+		}
+		return nil
+	})
+
+	boot, terminate := PlanMachines(prog(t, code), machines)
+	if len(boot) != 1 || boot[0].Size != cheapest || len(terminate) != 1 ||
+		terminate[0].Size != tooSmall {
+		t.Errorf("expected only the %s machine to be replaced, got boot %v "+
+			"and terminate %v", tooSmall, boot, terminate)
+	}
+
+	UpdatePolicy(conn, prog(t, code), "")
+	conn.Transact(func(view db.Database) error {
+		machines = view.SelectFromMachine(func(m db.Machine) bool {
+			return m.Size == fits
+		})
+		return nil
+	})
+	if len(machines) != 1 {
+		t.Errorf("expected the %s machine to keep its size", fits)
+	}
+}
+
+func TestExplainSizes(t *testing.T) {
+	code := `deployment.deploy([
+		new Machine({provider: "Amazon", role: "Master", size: "m4.large"}),
+		new Machine({provider: "Vagrant", role: "Worker", ram: new Range(2),
+			cpu: new Range(1), localSSD: true}),
+		new Machine({provider: "Azure", role: "Worker"})]);`
+
+	exp := []string{"machine 2 (Vagrant Worker): 2,1 has the least RAM and " +
+		"CPUs the machine accepts, as Vagrant machines may have any amount " +
+		"(ignoring localSSD)"}
+	if res := ExplainSizes(prog(t, code)); !reflect.DeepEqual(res, exp) {
+		t.Errorf("\nGot: %v\nExp: %v", res, exp)
+	}
+}
+
+func TestImage(t *testing.T) {
+	code := `var baseMachine = new Machine({provider: "Amazon",
+			size: "m4.large", image: "ami-a"});
+		deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(baseMachine.asWorker());`
+	conn := db.New()
+	UpdatePolicy(conn, prog(t, code), "")
+
+	var machines []db.Machine
+	conn.Transact(func(view db.Database) error {
+		machines = view.SelectFromMachine(nil)
+		return nil
+	})
+	if len(machines) != 2 || machines[0].Image != "ami-a" ||
+		machines[1].Image != "ami-a" {
+		t.Fatalf("expected machines with image ami-a, got %v", machines)
+	}
+
+	// Changing the image replaces the machine.
+	code = `var baseMachine = new Machine({provider: "Amazon",
+			size: "m4.large", image: "ami-b"});
+		deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(baseMachine.asWorker());`
+	boot, terminate := PlanMachines(prog(t, code), machines)
+	if len(boot) != 2 || boot[0].Image != "ami-b" || len(terminate) != 2 {
+		t.Errorf("expected ami-a to be replaced by ami-b, got boot %v and "+
+			"terminate %v", boot, terminate)
+	}
+}
+
+func TestDraining(t *testing.T) {
+	code := `var baseMachine = new Machine({provider: "Amazon", size: "m4.large"});
+		deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(baseMachine.asWorker());`
+	conn := db.New()
+	UpdatePolicy(conn, prog(t, code), "")
+
+	// The cluster marks the worker as draining, and inserts its replacement.
+	conn.Transact(func(view db.Database) error {
+		for _, m := range view.SelectFromMachine(nil) {
+			if m.Role == db.Worker {
+				replacement := view.InsertMachine()
+				id := replacement.ID
+				replacement = m
+				replacement.ID = id
+				view.Commit(replacement)
+
+				m.Draining = true
+				view.Commit(m)
+			}
+		}
+		return nil
+	})
+
+	// Draining machines are neither paired with the spec, nor terminated.
+	var machines []db.Machine
+	conn.Transact(func(view db.Database) error {
+		machines = view.SelectFromMachine(nil)
+		return nil
+	})
+	boot, terminate := PlanMachines(prog(t, code), machines)
+	if len(boot) != 0 || len(terminate) != 0 {
+		t.Errorf("expected no changes, got boot %v and terminate %v", boot,
+			terminate)
+	}
+}
+
 func prog(t *testing.T, code string) stitch.Stitch {
 	result, err := stitch.New(code, stitch.DefaultImportGetter)
 	if err != nil {
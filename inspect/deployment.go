@@ -0,0 +1,236 @@
+package inspect
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NetSys/quilt/stitch"
+)
+
+// A deployment summarizes a spec for rendering.  It's also the schema of the json
+// output format.
+type deployment struct {
+	Services    []service         `json:"services"`
+	Connections []connection      `json:"connections"`
+	Machines    []machine         `json:"machines"`
+	Placements  []string          `json:"placements"`
+	Invariants  []invariantResult `json:"invariants"`
+}
+
+// A service is the set of containers implementing a label.
+type service struct {
+	Name        string   `json:"name"`
+	Containers  []string `json:"containers"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// A connection permits the containers of one service to connect to those of
+// another.  Either end may be the public internet.
+type connection struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Ports []string `json:"ports"`
+}
+
+type machine struct {
+	Provider string `json:"provider"`
+	Role     string `json:"role"`
+	Size     string `json:"size,omitempty"`
+	Region   string `json:"region,omitempty"`
+}
+
+type invariantResult struct {
+	Invariant      string `json:"invariant"`
+	Holds          bool   `json:"holds"`
+	Counterexample string `json:"counterexample,omitempty"`
+}
+
+func makeDeployment(spec stitch.Stitch, graph stitch.Graph) deployment {
+	d := deployment{
+		Services:    []service{},
+		Connections: []connection{},
+		Machines:    []machine{},
+		Placements:  []string{},
+		Invariants:  []invariantResult{},
+	}
+
+	labelServices := map[string]*service{}
+	var labels []string
+	for _, node := range graph.Nodes {
+		if node.Name == stitch.PublicInternetLabel {
+			continue
+		}
+
+		svc, ok := labelServices[node.Label]
+		if !ok {
+			svc = &service{Name: node.Label}
+			for annotation := range node.Annotations {
+				svc.Annotations = append(svc.Annotations, annotation)
+			}
+			sort.Strings(svc.Annotations)
+
+			labelServices[node.Label] = svc
+			labels = append(labels, node.Label)
+		}
+		svc.Containers = append(svc.Containers, node.Name)
+	}
+
+	sort.Strings(labels)
+	for _, label := range labels {
+		svc := labelServices[label]
+		sort.Sort(idSlice(svc.Containers))
+		d.Services = append(d.Services, *svc)
+	}
+
+	d.Connections = labelConnections(graph)
+
+	for _, m := range graph.Machines {
+		d.Machines = append(d.Machines, machine{
+			Provider: m.Provider,
+			Role:     m.Role,
+			Size:     m.Size,
+			Region:   m.Region,
+		})
+	}
+
+	for _, pl := range graph.Constraints {
+		d.Placements = append(d.Placements, placementStr(pl))
+	}
+
+	for _, result := range spec.EvaluateInvariants(graph) {
+		d.Invariants = append(d.Invariants, invariantResult{
+			Invariant:      result.Invariant,
+			Holds:          result.Holds,
+			Counterexample: result.Counterexample,
+		})
+	}
+
+	return d
+}
+
+// labelConnections merges the edges between containers into connections between
+// the labels they implement.
+func labelConnections(graph stitch.Graph) []connection {
+	type labelPair struct{ from, to string }
+	pairPorts := map[labelPair]map[stitch.PortRange]struct{}{}
+	for _, node := range graph.Nodes {
+		for to, toNode := range node.Connections {
+			pair := labelPair{node.Label, toNode.Label}
+			if _, ok := pairPorts[pair]; !ok {
+				pairPorts[pair] = map[stitch.PortRange]struct{}{}
+			}
+
+			for _, ports := range node.Ports[to] {
+				pairPorts[pair][ports] = struct{}{}
+			}
+		}
+	}
+
+	conns := []connection{}
+	for pair, portSet := range pairPorts {
+		var ranges []stitch.PortRange
+		for ports := range portSet {
+			ranges = append(ranges, ports)
+		}
+		sort.Sort(portRangeSlice(ranges))
+
+		conn := connection{From: pair.from, To: pair.to, Ports: []string{}}
+		for _, ports := range ranges {
+			conn.Ports = append(conn.Ports, portRangeStr(ports))
+		}
+		conns = append(conns, conn)
+	}
+
+	sort.Sort(connectionSlice(conns))
+	return conns
+}
+
+func portRangeStr(ports stitch.PortRange) string {
+	if ports.Min == ports.Max {
+		return strconv.Itoa(ports.Min)
+	}
+	return fmt.Sprintf("%d-%d", ports.Min, ports.Max)
+}
+
+// placementStr describes a placement rule, e.g. `"web" must not share a machine
+// with "db"`.
+func placementStr(pl stitch.Placement) string {
+	must := "must"
+	if pl.Exclusive {
+		must = "must not"
+	}
+
+	if pl.OtherLabel != "" {
+		return fmt.Sprintf("%q %s share a machine with %q", pl.TargetLabel,
+			must, pl.OtherLabel)
+	}
+
+	var attrs []string
+	for _, attr := range []struct{ name, val string }{
+		{"provider", pl.Provider},
+		{"size", pl.Size},
+		{"region", pl.Region},
+	} {
+		if attr.val != "" {
+			attrs = append(attrs, attr.name+" "+attr.val)
+		}
+	}
+	return fmt.Sprintf("%q %s be on a machine with %s", pl.TargetLabel, must,
+		strings.Join(attrs, " and "))
+}
+
+// idSlice sorts container IDs numerically.
+type idSlice []string
+
+func (ids idSlice) Len() int {
+	return len(ids)
+}
+
+func (ids idSlice) Less(i, j int) bool {
+	l, lErr := strconv.Atoi(ids[i])
+	r, rErr := strconv.Atoi(ids[j])
+	if lErr != nil || rErr != nil {
+		return ids[i] < ids[j]
+	}
+	return l < r
+}
+
+func (ids idSlice) Swap(i, j int) {
+	ids[i], ids[j] = ids[j], ids[i]
+}
+
+type portRangeSlice []stitch.PortRange
+
+func (ranges portRangeSlice) Len() int {
+	return len(ranges)
+}
+
+func (ranges portRangeSlice) Less(i, j int) bool {
+	if ranges[i].Min != ranges[j].Min {
+		return ranges[i].Min < ranges[j].Min
+	}
+	return ranges[i].Max < ranges[j].Max
+}
+
+func (ranges portRangeSlice) Swap(i, j int) {
+	ranges[i], ranges[j] = ranges[j], ranges[i]
+}
+
+type connectionSlice []connection
+
+func (conns connectionSlice) Len() int {
+	return len(conns)
+}
+
+func (conns connectionSlice) Less(i, j int) bool {
+	if conns[i].From != conns[j].From {
+		return conns[i].From < conns[j].From
+	}
+	return conns[i].To < conns[j].To
+}
+
+func (conns connectionSlice) Swap(i, j int) {
+	conns[i], conns[j] = conns[j], conns[i]
+}
@@ -0,0 +1,90 @@
+package inspect
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// The page embeds the drawing, and lists the details beneath it.  Hovering over a
+// service fades out the connections that don't involve it.
+var htmlTemplate = template.Must(template.New("inspect").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font: 14px sans-serif; margin: 2em; }
+  .holds { color: #2e7d32; }
+  .fails { color: #c62828; }
+  .counterexample { margin-left: 2em; }
+  .service, .public { cursor: pointer; }
+  .connection { transition: opacity 0.2s; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{.SVG}}
+<h2>Machines</h2>
+<ul>
+{{range .Machines}}<li>{{.}}</li>
+{{else}}<li>None</li>
+{{end}}</ul>
+<h2>Placement rules</h2>
+<ul>
+{{range .Placements}}<li>{{.}}</li>
+{{else}}<li>None</li>
+{{end}}</ul>
+<h2>Invariants</h2>
+<ul>
+{{range .Invariants}}{{if .Holds}}<li class="holds">holds: {{.Invariant}}</li>
+{{else}}<li class="fails">fails: {{.Invariant}}{{if .Counterexample}}
+<div class="counterexample">{{.Counterexample}}</div>{{end}}</li>
+{{end}}{{else}}<li>None</li>
+{{end}}</ul>
+<script>
+var nodes = document.querySelectorAll(".service, .public");
+var connections = document.querySelectorAll(".connection");
+Array.prototype.forEach.call(nodes, function(node) {
+  var name = node.getAttribute("data-name");
+  node.addEventListener("mouseenter", function() {
+    Array.prototype.forEach.call(connections, function(conn) {
+      if (conn.getAttribute("data-from") !== name &&
+          conn.getAttribute("data-to") !== name) {
+        conn.classList.add("faded");
+      }
+    });
+  });
+  node.addEventListener("mouseleave", function() {
+    Array.prototype.forEach.call(connections, function(conn) {
+      conn.classList.remove("faded");
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// renderHTML renders the deployment as a standalone web page titled `title`.
+func renderHTML(d deployment, title string) (string, error) {
+	var machines []string
+	for _, m := range d.Machines {
+		machines = append(machines, machineStr(m))
+	}
+
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		Title      string
+		SVG        template.HTML
+		Machines   []string
+		Placements []string
+		Invariants []invariantResult
+	}{
+		Title:      title,
+		SVG:        template.HTML(renderSVG(d, false)),
+		Machines:   machines,
+		Placements: d.Placements,
+		Invariants: d.Invariants,
+	})
+	return buf.String(), err
+}
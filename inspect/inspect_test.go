@@ -1,10 +1,15 @@
 package inspect
 
 import (
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/spf13/afero"
+
 	"github.com/NetSys/quilt/stitch"
+	"github.com/NetSys/quilt/util"
 )
 
 func TestSlug(t *testing.T) {
@@ -67,3 +72,161 @@ func TestViz(t *testing.T) {
 		t.Error(gv + "\n" + expect)
 	}
 }
+
+func TestDeployment(t *testing.T) {
+	stc := `var web = new Service("web", new Container("nginx").replicate(2));
+	var db = new Service("db", [new Container("mysql")]);
+	db.annotate("ACL");
+	publicInternet.connect(80, web);
+	web.connect(3306, db);
+	web.connect(new PortRange(8000, 8080), db);
+	web.place(new MachineRule(false, {provider: "Amazon"}));
+	db.place(new LabelRule(true, web));
+	deployment.deploy(new Machine({provider: "Amazon", role: "Worker",
+		size: "m4.large"}));
+	deployment.deploy([web, db]);
+	deployment.assert(publicInternet.canReach(db), true);
+	deployment.assert(enough, true);`
+
+	// The spec is loaded without checking its invariants, as one of them fails.
+	util.AppFs = afero.NewMemMapFs()
+	util.WriteFile("spec.js", []byte(stc), 0644)
+	spec, err := stitch.FromFileUnchecked("spec.js",
+		stitch.ImportGetter{Path: "../specs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graph, err := stitch.InitializeGraph(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := deployment{
+		Services: []service{
+			{
+				Name:        "db",
+				Containers:  []string{"4"},
+				Annotations: []string{"ACL"},
+			},
+			{Name: "web", Containers: []string{"2", "3"}},
+		},
+		Connections: []connection{
+			{From: "public", To: "web", Ports: []string{"80"}},
+			{From: "web", To: "db", Ports: []string{"3306", "8000-8080"}},
+		},
+		Machines: []machine{
+			{Provider: "Amazon", Role: "Worker", Size: "m4.large"},
+		},
+		Placements: []string{
+			`"web" must be on a machine with provider Amazon`,
+			`"db" must not share a machine with "web"`,
+			// Containers listening on the same public port can't share
+			// a machine.
+			`"web" must not share a machine with "web"`,
+		},
+		Invariants: []invariantResult{
+			{Invariant: `reach true "public" "db"`, Holds: true},
+			{
+				Invariant: "enough true",
+				Holds:     false,
+				Counterexample: "container 3 (web) can't be " +
+					"placed: every Worker machine already " +
+					"hosts a container it must be kept apart " +
+					"from; container 4 (db) can't be placed: " +
+					"every Worker machine already hosts a " +
+					"container it must be kept apart from",
+			},
+		},
+	}
+
+	d := makeDeployment(spec, graph)
+	if !reflect.DeepEqual(d, exp) {
+		t.Errorf("Bad deployment:\nGot: %+v\nExp: %+v", d, exp)
+	}
+
+	js, err := render("json", "spec.js", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded deployment
+	if err := json.Unmarshal([]byte(js), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, exp) {
+		t.Errorf("Bad json deployment:\nGot: %+v\nExp: %+v", decoded, exp)
+	}
+
+	svg, err := render("svg", "spec.js", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{
+		`<g class="service" data-name="web">`,
+		`<g class="public" data-name="public">`,
+		`<g class="connection" data-from="web" data-to="db">`,
+		`>3306, 8000-8080</text>`,
+		`>Worker Amazon m4.large</text>`,
+		`>holds: reach true &#34;public&#34; &#34;db&#34;</text>`,
+		`<text class="fails" x="60" y=`,
+	} {
+		if !strings.Contains(svg, str) {
+			t.Errorf("SVG missing %q:\n%s", str, svg)
+		}
+	}
+
+	page, err := render("html", "spec.js", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{
+		"<title>spec.js</title>",
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		"<li>Worker Amazon m4.large</li>",
+		`<li class="fails">fails: enough true`,
+	} {
+		if !strings.Contains(page, str) {
+			t.Errorf("HTML missing %q:\n%s", str, page)
+		}
+	}
+
+	if _, err := render("png", "spec.js", d); err == nil {
+		t.Error("Expected an error rendering an unknown format")
+	}
+}
+
+func TestLayout(t *testing.T) {
+	t.Parallel()
+
+	d := deployment{
+		Services: []service{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+		Connections: []connection{
+			{From: "public", To: "a"},
+			{From: "a", To: "b"},
+			{From: "a", To: "c"},
+			{From: "b", To: "a"},
+		},
+	}
+
+	positions, width, height := layoutServices(d)
+	column := boxWidth + columnGap
+	row := boxHeight + rowGap
+	exp := map[string]point{
+		"public": {margin, margin},
+		"a":      {margin + float64(column), margin},
+		"d":      {margin + float64(column), margin + float64(row)},
+		"b":      {margin + 2*float64(column), margin},
+		"c":      {margin + 2*float64(column), margin + float64(row)},
+	}
+	if !reflect.DeepEqual(positions, exp) {
+		t.Errorf("Positions = %v, expected %v", positions, exp)
+	}
+
+	if expWidth := 2*margin + 3*column - columnGap; width != expWidth {
+		t.Errorf("Width = %d, expected %d", width, expWidth)
+	}
+	if expHeight := 2*margin + 2*row - rowGap; height != expHeight {
+		t.Errorf("Height = %d, expected %d", height, expHeight)
+	}
+}
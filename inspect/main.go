@@ -1,8 +1,10 @@
 package inspect
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/NetSys/quilt/stitch"
 )
@@ -12,16 +14,24 @@ func Usage() {
 	fmt.Fprintln(
 		os.Stderr,
 		`quilt inspect is a tool that helps visualize Stitch specifications.
-Usage: quilt inspect <path to spec file> <pdf|ascii>
-Dependencies
+Usage: quilt inspect [--format=<html|svg|json>] <path to spec file> [pdf|ascii]
+The html, svg and json formats are written to standard output.  They show each
+service's containers, the ports they connect on, the machines, the placement
+rules, and whether each invariant holds.
+Dependencies of the pdf and ascii formats
  - easy-graph (install Graph::Easy from cpan)
  - graphviz (install from your favorite package manager)`,
 	)
 }
 
-// Main is the main function for inspect tool. Helps visualize stitches.
-func Main(opts []string) int {
-	if arglen := len(opts); arglen < 2 {
+// Main is the main function for inspect tool. Helps visualize stitches.  If
+// `format` is empty, it's taken from the arguments instead.
+func Main(format string, opts []string) int {
+	if format == "" && len(opts) >= 2 {
+		format = opts[1]
+	}
+
+	if arglen := len(opts); arglen < 1 || format == "" {
 		fmt.Println("not enough arguments: ", arglen)
 		Usage()
 		return 1
@@ -29,7 +39,8 @@ func Main(opts []string) int {
 
 	configPath := opts[0]
 
-	spec, err := stitch.FromFile(configPath, stitch.DefaultImportGetter)
+	// Failing invariants are part of what's shown, so they aren't an error.
+	spec, err := stitch.FromFileUnchecked(configPath, stitch.DefaultImportGetter)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
@@ -41,11 +52,19 @@ func Main(opts []string) int {
 		return 1
 	}
 
-	switch opts[1] {
+	switch format {
 	case "pdf":
 		fallthrough
 	case "ascii":
-		viz(configPath, spec, graph, opts[1])
+		viz(configPath, spec, graph, format)
+	case "html", "svg", "json":
+		out, err := render(format, filepath.Base(configPath),
+			makeDeployment(spec, graph))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Print(out)
 	default:
 		Usage()
 		return 1
@@ -53,3 +72,17 @@ func Main(opts []string) int {
 
 	return 0
 }
+
+func render(format, title string, d deployment) (string, error) {
+	switch format {
+	case "html":
+		return renderHTML(d, title)
+	case "svg":
+		return renderSVG(d, true), nil
+	case "json":
+		js, err := json.MarshalIndent(d, "", "    ")
+		return string(js) + "\n", err
+	default:
+		return "", fmt.Errorf("unknown format: %s", format)
+	}
+}
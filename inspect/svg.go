@@ -0,0 +1,313 @@
+package inspect
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/NetSys/quilt/stitch"
+)
+
+// Dimensions of the drawing, in pixels.
+const (
+	boxWidth   = 160
+	boxHeight  = 50
+	columnGap  = 110
+	rowGap     = 40
+	margin     = 40
+	lineHeight = 20
+
+	// How far each level of the text beneath the drawing is indented.
+	indentWidth = 20
+
+	// How far connections bow away from a straight line, so that connections
+	// in opposite directions don't overlap.
+	curveOffset = 20
+)
+
+const svgStyle = `
+  .service rect { fill: #e8f0fe; stroke: #4a6fa5; stroke-width: 1.5; }
+  .public ellipse { fill: #fff3e0; stroke: #e08a00; stroke-width: 1.5; }
+  .service text, .public text { font: 13px sans-serif; text-anchor: middle; }
+  .service .name, .public .name { font-weight: bold; }
+  .connection path { fill: none; stroke: #555; stroke-width: 1.2;
+    marker-end: url(#arrow); }
+  .connection text { font: 11px sans-serif; fill: #333; text-anchor: middle; }
+  .details text { font: 13px sans-serif; }
+  .details .heading { font-weight: bold; }
+  .holds { fill: #2e7d32; }
+  .fails { fill: #c62828; }
+  .faded { opacity: 0.15; }
+`
+
+type point struct {
+	x, y float64
+}
+
+// layoutServices arranges the services, and the public internet if anything
+// connects to it, in columns.  Each service is placed one column to the right of
+// the first service found to connect to it, starting from the public internet, so
+// that traffic generally flows from left to right.  It returns the top left corner
+// of each box, and the size of the drawing.
+func layoutServices(d deployment) (map[string]point, int, int) {
+	adjacent := map[string][]string{}
+	hasPublic := false
+	for _, conn := range d.Connections {
+		if conn.From != conn.To {
+			adjacent[conn.From] = append(adjacent[conn.From], conn.To)
+		}
+		if conn.From == stitch.PublicInternetLabel ||
+			conn.To == stitch.PublicInternetLabel {
+			hasPublic = true
+		}
+	}
+
+	ranks := map[string]int{}
+	visit := func(root string, rank int) {
+		ranks[root] = rank
+		queue := []string{root}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			for _, next := range adjacent[name] {
+				if _, ok := ranks[next]; !ok {
+					ranks[next] = ranks[name] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	var names []string
+	firstRank := 0
+	if hasPublic {
+		names = append(names, stitch.PublicInternetLabel)
+		visit(stitch.PublicInternetLabel, 0)
+		firstRank = 1
+	}
+
+	for _, svc := range d.Services {
+		names = append(names, svc.Name)
+		if _, ok := ranks[svc.Name]; !ok {
+			visit(svc.Name, firstRank)
+		}
+	}
+
+	positions := map[string]point{}
+	rows := map[int]int{}
+	maxRank, maxRows := 0, 0
+	for _, name := range names {
+		rank := ranks[name]
+		positions[name] = point{
+			x: float64(margin + rank*(boxWidth+columnGap)),
+			y: float64(margin + rows[rank]*(boxHeight+rowGap)),
+		}
+		rows[rank]++
+
+		if rank > maxRank {
+			maxRank = rank
+		}
+		if rows[rank] > maxRows {
+			maxRows = rows[rank]
+		}
+	}
+
+	width := 2*margin + (maxRank+1)*(boxWidth+columnGap) - columnGap
+	height := 2*margin + maxRows*(boxHeight+rowGap) - rowGap
+	return positions, width, height
+}
+
+// renderSVG draws the deployment's services and the connections between them.  If
+// `withDetails` is set, the machines, placement rules, and invariants are listed
+// beneath the drawing.
+func renderSVG(d deployment, withDetails bool) string {
+	positions, width, height := layoutServices(d)
+
+	var details []detailLine
+	if withDetails {
+		details = detailLines(d)
+		height += len(details) * lineHeight
+		for _, line := range details {
+			// Roughly 7 pixels per character of 13px sans-serif text.
+			w := 2*margin + indentWidth*line.indent + 7*len(line.text)
+			if w > width {
+				width = w
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" `+
+		`height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&buf, "<style>%s</style>\n", svgStyle)
+	buf.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" ` +
+		`refY="5" markerWidth="8" markerHeight="8" orient="auto">` +
+		`<path d="M 0 0 L 10 5 L 0 10 z" fill="#555"/></marker></defs>` + "\n")
+
+	for _, conn := range d.Connections {
+		writeConnection(&buf, conn, positions)
+	}
+
+	if pos, ok := positions[stitch.PublicInternetLabel]; ok {
+		center := boxCenter(pos)
+		fmt.Fprintf(&buf, `<g class="public" data-name="%s">`, html.EscapeString(
+			stitch.PublicInternetLabel))
+		fmt.Fprintf(&buf, `<ellipse cx="%.1f" cy="%.1f" rx="%d" ry="%d"/>`,
+			center.x, center.y, boxWidth/2, boxHeight/2)
+		fmt.Fprintf(&buf, `<text class="name" x="%.1f" y="%.1f">public internet`+
+			"</text></g>\n", center.x, center.y+5)
+	}
+
+	for _, svc := range d.Services {
+		writeService(&buf, svc, positions[svc.Name])
+	}
+
+	if withDetails {
+		buf.WriteString(`<g class="details">` + "\n")
+		y := height - len(details)*lineHeight
+		for _, line := range details {
+			fmt.Fprintf(&buf, `<text class="%s" x="%d" y="%d">%s</text>`+"\n",
+				line.class, margin+indentWidth*line.indent, y,
+				html.EscapeString(line.text))
+			y += lineHeight
+		}
+		buf.WriteString("</g>\n")
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.String()
+}
+
+func writeService(buf *bytes.Buffer, svc service, pos point) {
+	fmt.Fprintf(buf, `<g class="service" data-name="%s">`,
+		html.EscapeString(svc.Name))
+	fmt.Fprintf(buf, "<title>containers: %s</title>",
+		html.EscapeString(strings.Join(svc.Containers, ", ")))
+	fmt.Fprintf(buf, `<rect x="%.1f" y="%.1f" width="%d" height="%d" rx="6"/>`,
+		pos.x, pos.y, boxWidth, boxHeight)
+
+	center := boxCenter(pos)
+	fmt.Fprintf(buf, `<text class="name" x="%.1f" y="%.1f">%s</text>`,
+		center.x, center.y-4, html.EscapeString(svc.Name))
+
+	summary := fmt.Sprintf("%d containers", len(svc.Containers))
+	if len(svc.Containers) == 1 {
+		summary = "1 container"
+	}
+	if len(svc.Annotations) > 0 {
+		summary += " [" + strings.Join(svc.Annotations, ", ") + "]"
+	}
+	fmt.Fprintf(buf, `<text x="%.1f" y="%.1f">%s</text></g>`+"\n",
+		center.x, center.y+14, html.EscapeString(summary))
+}
+
+func writeConnection(buf *bytes.Buffer, conn connection, positions map[string]point) {
+	from, to := positions[conn.From], positions[conn.To]
+
+	var path string
+	var label point
+	if conn.From == conn.To {
+		// Loop over the top of the box.
+		left, right := from.x+boxWidth*0.35, from.x+boxWidth*0.65
+		path = fmt.Sprintf("M %.1f %.1f C %.1f %.1f %.1f %.1f %.1f %.1f",
+			left, from.y, left, from.y-35, right, from.y-35, right, from.y)
+		label = point{from.x + boxWidth/2, from.y - 30}
+	} else {
+		start := boxEdge(boxCenter(from), boxCenter(to))
+		end := boxEdge(boxCenter(to), boxCenter(from))
+
+		// Bow the line to one side of its direction of travel.
+		dx, dy := end.x-start.x, end.y-start.y
+		length := math.Hypot(dx, dy)
+		control := point{
+			x: (start.x+end.x)/2 + curveOffset*dy/length,
+			y: (start.y+end.y)/2 - curveOffset*dx/length,
+		}
+
+		path = fmt.Sprintf("M %.1f %.1f Q %.1f %.1f %.1f %.1f",
+			start.x, start.y, control.x, control.y, end.x, end.y)
+		label = point{
+			x: (start.x + 2*control.x + end.x) / 4,
+			y: (start.y+2*control.y+end.y)/4 - 4,
+		}
+	}
+
+	fmt.Fprintf(buf, `<g class="connection" data-from="%s" data-to="%s">`,
+		html.EscapeString(conn.From), html.EscapeString(conn.To))
+	fmt.Fprintf(buf, `<path d="%s"/>`, path)
+	fmt.Fprintf(buf, `<text x="%.1f" y="%.1f">%s</text></g>`+"\n", label.x,
+		label.y, html.EscapeString(strings.Join(conn.Ports, ", ")))
+}
+
+func boxCenter(pos point) point {
+	return point{pos.x + boxWidth/2, pos.y + boxHeight/2}
+}
+
+// boxEdge finds where the line from `center` toward `target` leaves the box
+// centered at `center`.
+func boxEdge(center, target point) point {
+	dx, dy := target.x-center.x, target.y-center.y
+	scale := math.Inf(1)
+	if dx != 0 {
+		scale = math.Min(scale, boxWidth/2/math.Abs(dx))
+	}
+	if dy != 0 {
+		scale = math.Min(scale, boxHeight/2/math.Abs(dy))
+	}
+	if math.IsInf(scale, 1) {
+		return center
+	}
+	return point{center.x + scale*dx, center.y + scale*dy}
+}
+
+// A detailLine is a line of text listed beneath the drawing.
+type detailLine struct {
+	class  string
+	indent int
+	text   string
+}
+
+// detailLines lists the machines, placement rules and invariants.
+func detailLines(d deployment) []detailLine {
+	var lines []detailLine
+	add := func(class string, indent int, text string) {
+		lines = append(lines, detailLine{class, indent, text})
+	}
+
+	add("heading", 0, "Machines")
+	for _, m := range d.Machines {
+		add("", 1, machineStr(m))
+	}
+
+	add("heading", 0, "Placement rules")
+	for _, pl := range d.Placements {
+		add("", 1, pl)
+	}
+
+	add("heading", 0, "Invariants")
+	for _, inv := range d.Invariants {
+		if inv.Holds {
+			add("holds", 1, "holds: "+inv.Invariant)
+			continue
+		}
+
+		add("fails", 1, "fails: "+inv.Invariant)
+		if inv.Counterexample != "" {
+			add("fails", 2, inv.Counterexample)
+		}
+	}
+	return lines
+}
+
+// machineStr describes a machine, e.g. "Worker Amazon m4.large us-west-1".
+func machineStr(m machine) string {
+	var fields []string
+	for _, field := range []string{m.Role, m.Provider, m.Size, m.Region} {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -33,6 +33,11 @@ type Container struct {
 	Pid    int
 	Env    map[string]string
 	Labels map[string]string
+
+	// Running is false once the container has exited, in which case ExitCode is
+	// the code it exited with.
+	Running  bool
+	ExitCode int
 }
 
 // ContainerSlice is an alias for []Container to allow for joins
@@ -256,6 +261,23 @@ func (dk Client) List(filters map[string][]string) ([]Container, error) {
 	return dk.list(filters, false)
 }
 
+// ListExited returns a slice of all containers that have exited, and match the
+// supplied filters.
+func (dk Client) ListExited(filters map[string][]string) ([]Container, error) {
+	containers, err := dk.list(filters, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var exited []Container
+	for _, c := range containers {
+		if !c.Running {
+			exited = append(exited, c)
+		}
+	}
+	return exited, nil
+}
+
 func (dk Client) list(filters map[string][]string, all bool) ([]Container, error) {
 	opts := dkc.ListContainersOptions{All: all, Filters: filters}
 	apics, err := dk.ListContainers(opts)
@@ -303,6 +325,9 @@ func (dk Client) Get(id string) (Container, error) {
 		Pid:    c.State.Pid,
 		Env:    env,
 		Labels: c.Config.Labels,
+
+		Running:  c.State.Running,
+		ExitCode: c.State.ExitCode,
 	}, nil
 }
 
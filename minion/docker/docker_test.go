@@ -214,6 +214,17 @@ func TestRun(t *testing.T) {
 		t.Errorf(spew.Sprintf("Unexpected containers: %v", containers))
 	}
 
+	md.ExitContainer(id2, 3)
+	containers, err = dk.ListExited(nil)
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	if len(containers) != 1 || containers[0].ID != id2 ||
+		containers[0].Running || containers[0].ExitCode != 3 {
+		t.Error(spew.Sprintf("Unexpected containers: %v", containers))
+	}
+
 	md.InspectError = true
 	containers, err = dk.List(nil)
 	if err != nil {
@@ -59,6 +59,7 @@ func (dk MockClient) StartContainer(id string, hostConfig *dkc.HostConfig) error
 
 	container := dk.Containers[id]
 	container.Running = true
+	container.State.Running = true
 	container.HostConfig = hostConfig
 	dk.Containers[id] = container
 	return nil
@@ -66,10 +67,20 @@ func (dk MockClient) StartContainer(id string, hostConfig *dkc.HostConfig) error
 
 // StopContainer stops the given docker container.
 func (dk MockClient) StopContainer(id string) {
+	dk.ExitContainer(id, 0)
+}
+
+// ExitContainer stops the given docker container as if it had exited with `code`.
+func (dk MockClient) ExitContainer(id string, code int) {
 	dk.Lock()
 	defer dk.Unlock()
-	container := dk.Containers[id]
+	container, ok := dk.Containers[id]
+	if !ok {
+		return
+	}
 	container.Running = false
+	container.State.Running = false
+	container.State.ExitCode = code
 	dk.Containers[id] = container
 }
 
@@ -2,6 +2,7 @@ package minion
 
 import (
 	"sort"
+	"time"
 
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/join"
@@ -11,8 +12,38 @@ import (
 	log "github.com/Sirupsen/logrus"
 )
 
-func updatePolicy(view db.Database, role db.Role, spec string) {
-	compiled, err := stitch.New(spec, stitch.DefaultImportGetter)
+// A policy applies the minion's spec to the database.  The update loop reuses one
+// policy, so that the spec is only compiled when it changes, and so that rolling
+// updates remember when their last batch began.
+type policy struct {
+	spec     string
+	compiled *stitch.Stitch
+
+	// The time at which the most recent batch of each label's rolling update
+	// began.
+	lastBatch map[string]time.Time
+}
+
+func newPolicy() *policy {
+	return &policy{lastBatch: map[string]time.Time{}}
+}
+
+// compile returns the compiled `spec`, compiling it only if it differs from the
+// last spec compiled successfully.  Failures aren't cached, as they may be caused by
+// an import that couldn't be fetched.
+func (p *policy) compile(spec string) (stitch.Stitch, error) {
+	if p.compiled == nil || spec != p.spec {
+		compiled, err := stitch.New(spec, stitch.DefaultImportGetter)
+		if err != nil {
+			return stitch.Stitch{}, err
+		}
+		p.spec, p.compiled = spec, &compiled
+	}
+	return *p.compiled, nil
+}
+
+func (p *policy) update(view db.Database, role db.Role, spec string) {
+	compiled, err := p.compile(spec)
 	if err != nil {
 		log.WithError(err).Warn("Invalid spec.")
 		return
@@ -29,11 +60,44 @@ func updatePolicy(view db.Database, role db.Role, spec string) {
 		// should exist.  In the workers, however, the container table is just
 		// what's running locally.  That's why we only sync the database
 		// containers on the master.
-		updateContainers(view, compiled)
+		p.updateContainers(view, compiled)
 	}
 }
 
 func updatePlacements(view db.Database, spec stitch.Stitch) {
+	addSet, removeSet := joinPlacements(spec, view.SelectFromPlacement(nil))
+
+	for _, toAddIntf := range addSet {
+		toAdd := toAddIntf.(db.Placement)
+
+		id := view.InsertPlacement().ID
+		toAdd.ID = id
+		view.Commit(toAdd)
+	}
+
+	for _, toRemove := range removeSet {
+		view.Remove(toRemove.(db.Placement))
+	}
+}
+
+// PlanPlacements returns the placements that applying `spec` would add, and the
+// placements in `dbPlacements` that it would remove.
+func PlanPlacements(spec stitch.Stitch, dbPlacements []db.Placement) (add,
+	remove []db.Placement) {
+
+	addSet, removeSet := joinPlacements(spec, dbPlacements)
+	for _, p := range addSet {
+		add = append(add, p.(db.Placement))
+	}
+	for _, p := range removeSet {
+		remove = append(remove, p.(db.Placement))
+	}
+	return add, remove
+}
+
+func joinPlacements(spec stitch.Stitch, dbPlacements []db.Placement) (addSet,
+	removeSet []interface{}) {
+
 	var placements db.PlacementSlice
 	for _, sp := range spec.QueryPlacements() {
 		placements = append(placements, db.Placement{
@@ -52,37 +116,13 @@ func updatePlacements(view db.Database, spec stitch.Stitch) {
 		return p
 	}
 
-	dbPlacements := db.PlacementSlice(view.SelectFromPlacement(nil))
-	_, addSet, removeSet := join.HashJoin(placements, dbPlacements, key, key)
-
-	for _, toAddIntf := range addSet {
-		toAdd := toAddIntf.(db.Placement)
-
-		id := view.InsertPlacement().ID
-		toAdd.ID = id
-		view.Commit(toAdd)
-	}
-
-	for _, toRemove := range removeSet {
-		view.Remove(toRemove.(db.Placement))
-	}
+	_, addSet, removeSet = join.HashJoin(placements,
+		db.PlacementSlice(dbPlacements), key, key)
+	return addSet, removeSet
 }
 
 func updateConnections(view db.Database, spec stitch.Stitch) {
-	scs, vcs := stitch.ConnectionSlice(spec.QueryConnections()),
-		view.SelectFromConnection(nil)
-
-	dbcKey := func(val interface{}) interface{} {
-		c := val.(db.Connection)
-		return stitch.Connection{
-			From:    c.From,
-			To:      c.To,
-			MinPort: c.MinPort,
-			MaxPort: c.MaxPort,
-		}
-	}
-
-	pairs, stitches, dbcs := join.HashJoin(scs, db.ConnectionSlice(vcs), nil, dbcKey)
+	pairs, stitches, dbcs := joinConnections(spec, view.SelectFromConnection(nil))
 
 	for _, dbc := range dbcs {
 		view.Remove(dbc.(db.Connection))
@@ -100,10 +140,52 @@ func updateConnections(view db.Database, spec stitch.Stitch) {
 		dbc.To = stitchc.To
 		dbc.MinPort = stitchc.MinPort
 		dbc.MaxPort = stitchc.MaxPort
+		dbc.CidrIP = stitchc.CidrIP
 		view.Commit(dbc)
 	}
 }
 
+// PlanConnections returns the connections that applying `spec` would add, and the
+// connections in `vcs` that it would remove.
+func PlanConnections(spec stitch.Stitch, vcs []db.Connection) (add,
+	remove []db.Connection) {
+
+	_, stitches, dbcs := joinConnections(spec, vcs)
+	for _, intf := range stitches {
+		stitchc := intf.(stitch.Connection)
+		add = append(add, db.Connection{
+			From:    stitchc.From,
+			To:      stitchc.To,
+			MinPort: stitchc.MinPort,
+			MaxPort: stitchc.MaxPort,
+			CidrIP:  stitchc.CidrIP,
+		})
+	}
+	for _, dbc := range dbcs {
+		remove = append(remove, dbc.(db.Connection))
+	}
+	return add, remove
+}
+
+func joinConnections(spec stitch.Stitch, vcs []db.Connection) (pairs []join.Pair,
+	stitches, dbcs []interface{}) {
+
+	scs := stitch.ConnectionSlice(spec.QueryConnections())
+
+	dbcKey := func(val interface{}) interface{} {
+		c := val.(db.Connection)
+		return stitch.Connection{
+			From:    c.From,
+			To:      c.To,
+			MinPort: c.MinPort,
+			MaxPort: c.MaxPort,
+			CidrIP:  c.CidrIP,
+		}
+	}
+
+	return join.HashJoin(scs, db.ConnectionSlice(vcs), nil, dbcKey)
+}
+
 func queryContainers(spec stitch.Stitch) []db.Container {
 	containers := map[int]*db.Container{}
 	for _, c := range spec.QueryContainers() {
@@ -118,6 +200,21 @@ func queryContainers(spec stitch.Stitch) []db.Container {
 	for _, label := range spec.QueryLabels() {
 		for _, id := range label.IDs {
 			containers[id].Labels = append(containers[id].Labels, label.Name)
+			for _, dep := range label.Dependencies {
+				containers[id].Dependencies = append(
+					containers[id].Dependencies, db.Dependency{
+						Label:        dep.Label,
+						UntilHealthy: dep.UntilHealthy,
+					})
+			}
+
+			if label.Job != nil {
+				containers[id].Job = db.Job{
+					Parallelism:  label.Job.Parallelism,
+					BackoffLimit: label.Job.BackoffLimit,
+					Backoff:      label.Job.Backoff,
+				}
+			}
 		}
 	}
 
@@ -129,14 +226,76 @@ func queryContainers(spec stitch.Stitch) []db.Container {
 	return ret
 }
 
-func updateContainers(view db.Database, spec stitch.Stitch) {
+func (p *policy) updateContainers(view db.Database, spec stitch.Stitch) {
+	workers := len(view.SelectFromMinion(func(m db.Minion) bool {
+		return m.Role == db.Worker && m.PrivateIP != "" && !m.Draining
+	}))
+
+	pairs, news, dbcs := joinContainers(spec, view.SelectFromContainer(nil))
+	news, dbcs = p.throttleUpdates(spec, workers, pairs, news, dbcs)
+
+	for _, dbc := range dbcs {
+		view.Remove(dbc.(db.Container))
+	}
+
+	for _, new := range news {
+		pairs = append(pairs, join.Pair{L: new, R: view.InsertContainer()})
+	}
+
+	for _, pair := range pairs {
+		newc := pair.L.(db.Container)
+		dbc := pair.R.(db.Container)
+
+		// By sorting the labels we prevent the database from getting confused
+		// when their order is non deterministic.
+		dbc.Labels = newc.Labels
+		sort.Sort(sort.StringSlice(dbc.Labels))
+
+		dbc.Command = newc.Command
+		dbc.Image = newc.Image
+		dbc.Env = newc.Env
+		dbc.StitchID = newc.StitchID
+		dbc.Dependencies = newc.Dependencies
+
+		// Keep the progress of job containers that are already running.
+		dbc.Job.Parallelism = newc.Job.Parallelism
+		dbc.Job.BackoffLimit = newc.Job.BackoffLimit
+		dbc.Job.Backoff = newc.Job.Backoff
+		if dbc.IsJob() && dbc.Job.State == "" {
+			dbc.Job.State = db.JobPending
+		}
+		view.Commit(dbc)
+	}
+}
+
+// PlanContainers returns the containers that applying `spec` would boot, and the
+// containers in `dbcs` that it would remove.  These are the changes once the update
+// is complete: labels with an update strategy spread them out over several
+// batches, which aren't planned.
+func PlanContainers(spec stitch.Stitch, dbcs []db.Container) (boot,
+	remove []db.Container) {
+
+	_, news, olds := joinContainers(spec, dbcs)
+	for _, newc := range news {
+		boot = append(boot, newc.(db.Container))
+	}
+	for _, dbc := range olds {
+		remove = append(remove, dbc.(db.Container))
+	}
+	return boot, remove
+}
+
+func joinContainers(spec stitch.Stitch, dbcs []db.Container) (pairs []join.Pair,
+	news, olds []interface{}) {
+
 	score := func(l, r interface{}) int {
 		left := l.(db.Container)
 		right := r.(db.Container)
 
 		if left.Image != right.Image ||
 			!util.StrSliceEqual(left.Command, right.Command) ||
-			!util.StrStrMapEqual(left.Env, right.Env) {
+			!util.StrStrMapEqual(left.Env, right.Env) ||
+			left.IsJob() != right.IsJob() {
 			return -1
 		}
 
@@ -147,30 +306,174 @@ func updateContainers(view db.Database, spec stitch.Stitch) {
 		return score
 	}
 
-	pairs, news, dbcs := join.Join(queryContainers(spec),
-		view.SelectFromContainer(nil), score)
+	return join.Join(queryContainers(spec), dbcs, score)
+}
+
+var timeNow = time.Now
 
-	for _, dbc := range dbcs {
-		view.Remove(dbc.(db.Container))
+// throttleUpdates limits the containers that are booted and removed in a single
+// pass according to the update strategy of their labels.  Containers whose labels
+// don't specify a strategy are replaced all at once.
+func (p *policy) throttleUpdates(spec stitch.Stitch, workers int, pairs []join.Pair,
+	news, dbcs []interface{}) (allowedNews, allowedDBCs []interface{}) {
+
+	strategies := map[string]stitch.Label{}
+	for _, label := range spec.QueryLabels() {
+		if label.UpdateStrategy != nil {
+			strategies[label.Name] = label
+		}
 	}
 
-	for _, new := range news {
-		pairs = append(pairs, join.Pair{L: new, R: view.InsertContainer()})
+	if len(strategies) == 0 {
+		return news, dbcs
+	}
+
+	// A container that implements multiple labels with a strategy is governed
+	// by the alphabetically first.
+	strategyLabel := func(labels []string) string {
+		sorted := append([]string{}, labels...)
+		sort.Strings(sorted)
+		for _, l := range sorted {
+			if _, ok := strategies[l]; ok {
+				return l
+			}
+		}
+		return ""
+	}
+
+	type rollout struct {
+		current, ready int
+		pending, old   []db.Container
+	}
+
+	rollouts := map[string]*rollout{}
+	getRollout := func(label string) *rollout {
+		if _, ok := rollouts[label]; !ok {
+			rollouts[label] = &rollout{}
+		}
+		return rollouts[label]
 	}
 
 	for _, pair := range pairs {
-		newc := pair.L.(db.Container)
 		dbc := pair.R.(db.Container)
+		if l := strategyLabel(pair.L.(db.Container).Labels); l != "" {
+			r := getRollout(l)
+			r.current++
+			if dbc.Ready() {
+				r.ready++
+			}
+		}
+	}
 
-		// By sorting the labels we prevent the database from getting confused
-		// when their order is non deterministic.
-		dbc.Labels = newc.Labels
-		sort.Sort(sort.StringSlice(dbc.Labels))
+	for _, intf := range news {
+		newc := intf.(db.Container)
+		if l := strategyLabel(newc.Labels); l != "" {
+			getRollout(l).pending = append(getRollout(l).pending, newc)
+		} else {
+			allowedNews = append(allowedNews, newc)
+		}
+	}
 
-		dbc.Command = newc.Command
-		dbc.Image = newc.Image
-		dbc.Env = newc.Env
-		dbc.StitchID = newc.StitchID
-		view.Commit(dbc)
+	for _, intf := range dbcs {
+		dbc := intf.(db.Container)
+		if l := strategyLabel(dbc.Labels); l != "" {
+			getRollout(l).old = append(getRollout(l).old, dbc)
+		} else {
+			allowedDBCs = append(allowedDBCs, dbc)
+		}
+	}
+
+	for label, r := range rollouts {
+		// Without old containers to replace, this isn't an update.
+		if len(r.old) == 0 {
+			delete(p.lastBatch, label)
+			for _, newc := range r.pending {
+				allowedNews = append(allowedNews, newc)
+			}
+			continue
+		}
+
+		strategy := *strategies[label].UpdateStrategy
+		pause := time.Duration(strategy.Pause) * time.Second
+		if timeNow().Before(p.lastBatch[label].Add(pause)) {
+			continue
+		}
+
+		// Without unavailable containers, the update only proceeds as the
+		// surge becomes ready, which can't happen without a worker to run it.
+		if strategy.MaxUnavailable == 0 && workers == 0 {
+			log.WithField("label", label).Warn("A rolling update " +
+				"can't surge without workers, so it's waiting for " +
+				"one to join.")
+		}
+
+		// Old containers that aren't ready don't contribute to the label's
+		// availability, so they're removed first.
+		sort.Sort(rolloutOrder(r.old))
+		sort.Sort(rolloutOrder(r.pending))
+
+		size := len(strategies[label].IDs)
+		available := r.ready
+		for _, dbc := range r.old {
+			if dbc.Ready() {
+				available++
+			}
+		}
+
+		total := r.current + len(r.old)
+		removed, added := 0, 0
+		for _, dbc := range r.old {
+			if dbc.Ready() {
+				if available <= size-strategy.MaxUnavailable {
+					break
+				}
+				available--
+			}
+			allowedDBCs = append(allowedDBCs, dbc)
+			removed++
+		}
+		total -= removed
+
+		for _, newc := range r.pending {
+			if total >= size+strategy.MaxSurge {
+				break
+			}
+			allowedNews = append(allowedNews, newc)
+			total++
+			added++
+		}
+
+		if removed > 0 || added > 0 {
+			log.WithFields(log.Fields{
+				"label":   label,
+				"removed": removed,
+				"booted":  added,
+			}).Info("Rolling update batch.")
+			p.lastBatch[label] = timeNow()
+		}
 	}
+
+	return allowedNews, allowedDBCs
+}
+
+// rolloutOrder sorts the containers that aren't ready before those that are, and
+// otherwise by StitchID.
+type rolloutOrder []db.Container
+
+func (cs rolloutOrder) Len() int {
+	return len(cs)
+}
+
+func (cs rolloutOrder) Less(i, j int) bool {
+	if cs[i].Ready() != cs[j].Ready() {
+		return !cs[i].Ready()
+	}
+	if cs[i].StitchID != cs[j].StitchID {
+		return cs[i].StitchID < cs[j].StitchID
+	}
+	return cs[i].ID < cs[j].ID
+}
+
+func (cs rolloutOrder) Swap(i, j int) {
+	cs[i], cs[j] = cs[j], cs[i]
 }
@@ -126,10 +126,170 @@ func TestContainerTxn(t *testing.T) {
 	}
 }
 
+func TestPolicyCompile(t *testing.T) {
+	p := newPolicy()
+	spec := `deployment.deploy(new Service("a", [new Container("alpine")]));`
+	if _, err := p.compile(spec); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same spec isn't compiled again.
+	compiled := p.compiled
+	p.compile(spec)
+	if p.compiled != compiled {
+		t.Error("Expected the cached spec")
+	}
+
+	if _, err := p.compile("syntax error("); err == nil {
+		t.Error("Expected an error compiling a broken spec")
+	}
+	if p.compiled != compiled {
+		t.Error("Expected a broken spec not to replace the cached one")
+	}
+
+	p.compile(`deployment.deploy(new Service("b", [new Container("alpine")]));`)
+	if p.compiled == compiled {
+		t.Error("Expected a changed spec to be compiled")
+	}
+}
+
+func TestRollingUpdate(t *testing.T) {
+	clock := time.Now()
+	timeNow = func() time.Time { return clock }
+	defer func() { timeNow = time.Now }()
+
+	specTemplate := `var c = new Container("%s");
+	var svc = new Service("svc", c.replicate(3));
+	svc.setUpdateStrategy(%s);
+	deployment.deploy(svc);`
+
+	conn := db.New()
+	conn.Transact(func(view db.Database) error {
+		for _, ip := range []string{"10.0.0.2", "10.0.0.3"} {
+			m := view.InsertMinion()
+			m.Role = db.Worker
+			m.PrivateIP = ip
+			view.Commit(m)
+		}
+		return nil
+	})
+
+	policy := newPolicy()
+	update := func(image, strategy string) {
+		conn.Transact(func(view db.Database) error {
+			policy.update(view, db.Master,
+				fmt.Sprintf(specTemplate, image, strategy))
+			return nil
+		})
+	}
+
+	startAll := func() {
+		conn.Transact(func(view db.Database) error {
+			for _, dbc := range view.SelectFromContainer(nil) {
+				dbc.DockerID = fmt.Sprintf("docker-%d", dbc.ID)
+				dbc.IP = fmt.Sprintf("10.1.0.%d", dbc.ID)
+				view.Commit(dbc)
+			}
+			return nil
+		})
+	}
+
+	// Containers are running before they get their IP addresses.
+	runAll := func() {
+		conn.Transact(func(view db.Database) error {
+			for _, dbc := range view.SelectFromContainer(nil) {
+				dbc.DockerID = fmt.Sprintf("docker-%d", dbc.ID)
+				view.Commit(dbc)
+			}
+			return nil
+		})
+	}
+
+	checkImages := func(exp map[string]int) {
+		actual := map[string]int{}
+		for _, dbc := range conn.SelectFromContainer(nil) {
+			actual[dbc.Image]++
+		}
+		if !reflect.DeepEqual(actual, exp) {
+			t.Errorf("Expected images %v, got %v", exp, actual)
+		}
+	}
+
+	strategy := "{maxUnavailable: 1, pause: 60}"
+	update("alpine", strategy)
+	checkImages(map[string]int{"alpine": 3})
+	startAll()
+
+	// Only one container may be unavailable at a time.
+	update("ubuntu", strategy)
+	checkImages(map[string]int{"alpine": 2, "ubuntu": 1})
+
+	// The new container hasn't started, and the pause hasn't elapsed.
+	update("ubuntu", strategy)
+	checkImages(map[string]int{"alpine": 2, "ubuntu": 1})
+
+	clock = clock.Add(time.Minute)
+	update("ubuntu", strategy)
+	checkImages(map[string]int{"alpine": 2, "ubuntu": 1})
+
+	startAll()
+	update("ubuntu", strategy)
+	checkImages(map[string]int{"alpine": 1, "ubuntu": 2})
+
+	clock = clock.Add(time.Minute)
+	startAll()
+	update("ubuntu", strategy)
+	checkImages(map[string]int{"ubuntu": 3})
+
+	// With a surge, new containers start before old ones are removed.
+	strategy = "{maxSurge: 1}"
+	startAll()
+	update("alpine", strategy)
+	checkImages(map[string]int{"alpine": 1, "ubuntu": 3})
+
+	update("alpine", strategy)
+	checkImages(map[string]int{"alpine": 1, "ubuntu": 3})
+
+	// A running container isn't ready until it has an IP address.
+	runAll()
+	update("alpine", strategy)
+	checkImages(map[string]int{"alpine": 1, "ubuntu": 3})
+
+	startAll()
+	update("alpine", strategy)
+	checkImages(map[string]int{"alpine": 2, "ubuntu": 2})
+
+	// The strategy is kept with a single worker, which the surge shares with
+	// the containers it replaces.
+	conn.Transact(func(view db.Database) error {
+		view.Remove(view.SelectFromMinion(func(m db.Minion) bool {
+			return m.PrivateIP == "10.0.0.3"
+		})[0])
+		return nil
+	})
+
+	update("ubuntu", strategy)
+	checkImages(map[string]int{"alpine": 1, "ubuntu": 3})
+
+	startAll()
+	update("ubuntu", strategy)
+	checkImages(map[string]int{"ubuntu": 3})
+
+	update("debian", strategy)
+	checkImages(map[string]int{"ubuntu": 3, "debian": 1})
+
+	update("debian", strategy)
+	checkImages(map[string]int{"ubuntu": 3, "debian": 1})
+
+	startAll()
+	update("debian", strategy)
+	checkImages(map[string]int{"ubuntu": 2, "debian": 2})
+}
+
 func testContainerTxn(conn db.Conn, spec string) string {
 	var containers []db.Container
 	conn.Transact(func(view db.Database) error {
-		updatePolicy(view, db.Master, spec)
+		newPolicy().update(view, db.Master, spec)
 		containers = view.SelectFromContainer(nil)
 		return nil
 	})
@@ -244,7 +404,7 @@ func TestConnectionTxn(t *testing.T) {
 func testConnectionTxn(conn db.Conn, spec string) string {
 	var connections []db.Connection
 	conn.Transact(func(view db.Database) error {
-		updatePolicy(view, db.Master, spec)
+		newPolicy().update(view, db.Master, spec)
 		connections = view.SelectFromConnection(nil)
 		return nil
 	})
@@ -294,7 +454,7 @@ func TestPlacementTxn(t *testing.T) {
 	checkPlacement := func(spec string, exp ...db.Placement) {
 		placements := map[db.Placement]struct{}{}
 		conn.Transact(func(view db.Database) error {
-			updatePolicy(view, db.Master, spec)
+			newPolicy().update(view, db.Master, spec)
 			res := view.SelectFromPlacement(nil)
 
 			// Set the ID to 0 so that we can use reflect.DeepEqual.
@@ -1,11 +1,14 @@
 package etcd
 
 import (
+	"crypto/sha1"
 	"encoding/json"
+	"fmt"
 	"net"
 	"path"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NetSys/quilt/db"
@@ -23,6 +26,13 @@ const (
 	containerStore = minionDir + "/container"
 	nodeStore      = minionDir + "/nodes"
 	minionIPStore  = "ips"
+
+	// Workers publish the Docker IDs of the containers they're running so that
+	// the leader knows when they've started.
+	minionDockerIDStore = "dockerIDs"
+
+	// Workers publish the progress of the job containers they've been assigned.
+	minionJobStore = "jobs"
 )
 
 // Keeping all the store data types in a struct makes it much less verbose to pass them
@@ -41,6 +51,12 @@ type storeContainer struct {
 	Env     map[string]string
 
 	Labels []string
+
+	Dependencies []db.Dependency
+
+	// Only the job's parameters and retry count are stored.  Its progress is
+	// published by the worker running it.
+	Job db.Job
 }
 
 type storeContainerSlice []storeContainer
@@ -104,6 +120,19 @@ func runNetwork(conn db.Conn, store Store) {
 				return nil
 			}
 
+			dockerIDMap, err := loadMinionMaps(store, minionDockerIDStore)
+			if err != nil {
+				log.WithError(err).Error(
+					"Etcd read minion Docker IDs failed")
+				return nil
+			}
+
+			jobMap, err := loadMinionMaps(store, minionJobStore)
+			if err != nil {
+				log.WithError(err).Error("Etcd read minion jobs failed")
+				return nil
+			}
+
 			// It would likely be more efficient to perform the etcd write
 			// outside of the DB transact. But, if we perform the writes
 			// after the transact, there is no way to ensure that the writes
@@ -117,7 +146,8 @@ func runNetwork(conn db.Conn, store Store) {
 					return nil
 				}
 
-				updateLeaderDBC(view, containers, etcdData, ipMap)
+				updateLeaderDBC(view, containers, etcdData, ipMap,
+					dockerIDMap, jobMap)
 			}
 
 			updateDBLabels(view, etcdData, ipMap)
@@ -171,11 +201,43 @@ func readEtcd(store Store) (storeData, error) {
 	return storeData{etcdContainerSlice, multiHostMap}, err
 }
 
-func loadMinionIPs(store Store) (map[string]string, error) {
-	ipMap := map[string]string{}
+// minionMaps maps the PrivateIP of each worker to a map, published by the worker,
+// that is keyed by the containerKeys of its containers.
+type minionMaps map[string]map[string]string
+
+func (mm minionMaps) lookup(minion, key string) string {
+	return mm[minion][key]
+}
+
+// containerKey returns the key of `c` in the maps workers publish.  While a
+// container is being updated, its old and new versions share a StitchID, and may
+// run on the same worker, so they're told apart by their definitions.
+func containerKey(c storeContainer) string {
+	def, err := json.Marshal([]interface{}{c.Image, c.Command, c.Env})
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%d-%x", c.StitchID, sha1.Sum(def))
+}
+
+func dbcKey(dbc db.Container) string {
+	return containerKey(storeContainer{
+		StitchID: dbc.StitchID,
+		Image:    dbc.Image,
+		Command:  dbc.Command,
+		Env:      dbc.Env,
+	})
+}
+
+func loadMinionIPs(store Store) (minionMaps, error) {
+	return loadMinionMaps(store, minionIPStore)
+}
+
+func loadMinionMaps(store Store, key string) (minionMaps, error) {
+	result := minionMaps{}
 	allMinions, err := store.GetTree(nodeStore)
 	if err != nil {
-		return ipMap, err
+		return result, err
 	}
 
 	for _, t := range allMinions.Children {
@@ -189,32 +251,30 @@ func loadMinionIPs(store Store) (map[string]string, error) {
 		err := json.Unmarshal([]byte(minionData.Value), &minion)
 		if err != nil {
 			log.Errorf("Failed to unmarshal minion %s self", t.Key)
-			return ipMap, err
+			return result, err
 		}
 
 		if minion.Role != db.Worker {
 			continue
 		}
 
-		minionIPData, ok := t.Children[minionIPStore]
+		mapData, ok := t.Children[key]
 		if !ok {
-			log.Debugf("Minion %s has no IP store node", t.Key)
+			log.Debugf("Minion %s has no %s node", t.Key, key)
 			continue
 		}
 
-		minionIPMap := map[string]string{}
-		err = json.Unmarshal([]byte(minionIPData.Value), &minionIPMap)
+		minionMap := map[string]string{}
+		err = json.Unmarshal([]byte(mapData.Value), &minionMap)
 		if err != nil {
-			log.Errorf("Failed to unmarshal minion %s IP data", t.Key)
-			return ipMap, err
+			log.Errorf("Failed to unmarshal minion %s %s data", t.Key, key)
+			return result, err
 		}
 
-		for stitchID, ipAddr := range minionIPMap {
-			ipMap[stitchID] = ipAddr
-		}
+		result[minion.PrivateIP] = minionMap
 	}
 
-	return ipMap, nil
+	return result, nil
 }
 
 func updateEtcd(s Store, etcdData storeData,
@@ -241,6 +301,14 @@ func dbSliceToStoreSlice(dbcs []db.Container) []storeContainer {
 			Command:  c.Command,
 			Labels:   c.Labels,
 			Env:      c.Env,
+
+			Dependencies: c.Dependencies,
+			Job: db.Job{
+				Parallelism:  c.Job.Parallelism,
+				BackoffLimit: c.Job.BackoffLimit,
+				Backoff:      c.Job.Backoff,
+				Retries:      c.Job.Retries,
+			},
 		}
 		dbContainerSlice = append(dbContainerSlice, sc)
 	}
@@ -320,19 +388,66 @@ func updateEtcdLabel(s Store, etcdData storeData, containers []db.Container) (st
 }
 
 func updateLeaderDBC(view db.Database, dbcs []db.Container,
-	etcdData storeData, ipMap map[string]string) {
+	etcdData storeData, ipMap, dockerIDMap, jobMap minionMaps) {
 
 	for _, dbc := range dbcs {
-		ipVal := ipMap[strconv.Itoa(dbc.StitchID)]
+		key := dbcKey(dbc)
+		ipVal := ipMap.lookup(dbc.Minion, key)
 		mac := ip.ToMac(ipVal)
-		if dbc.IP != ipVal || dbc.Mac != mac {
+		dockerID := dockerIDMap.lookup(dbc.Minion, key)
+		changed := updateJob(&dbc, jobMap.lookup(dbc.Minion, key))
+		if changed || dbc.IP != ipVal || dbc.Mac != mac ||
+			dbc.DockerID != dockerID {
 			dbc.IP = ipVal
 			dbc.Mac = mac
+			dbc.DockerID = dockerID
 			view.Commit(dbc)
 		}
 	}
 }
 
+// updateJob records the progress of `dbc` published by its worker, and returns true
+// if it changed.  Progress reported for an earlier attempt is ignored.
+func updateJob(dbc *db.Container, status string) bool {
+	state, exitCode, retries, ok := parseJobStatus(status)
+	if !dbc.IsJob() || !ok || retries != dbc.Job.Retries ||
+		state == dbc.Job.State && exitCode == dbc.Job.ExitCode {
+		return false
+	}
+
+	dbc.Job.State = state
+	dbc.Job.ExitCode = exitCode
+	if dbc.Job.Done() {
+		dbc.Job.Finished = time.Now()
+	}
+	return true
+}
+
+func jobStatus(job db.Job) string {
+	return fmt.Sprintf("%s:%d:%d", job.State, job.ExitCode, job.Retries)
+}
+
+func parseJobStatus(status string) (state db.JobState, exitCode, retries int,
+	ok bool) {
+
+	fields := strings.Split(status, ":")
+	if len(fields) != 3 {
+		return "", 0, 0, false
+	}
+
+	exitCode, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	retries, err = strconv.Atoi(fields[2])
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	return db.JobState(fields[0]), exitCode, retries, true
+}
+
 func updateWorker(view db.Database, self db.Minion, store Store,
 	etcdData storeData) {
 
@@ -353,6 +468,7 @@ func updateWorker(view db.Database, self db.Minion, store Store,
 				Command:  dbc.Command,
 				Env:      dbc.Env,
 				Labels:   dbc.Labels,
+				Job:      db.Job{Retries: dbc.Job.Retries},
 			}
 			return containerJoinScore(l, right.(storeContainer))
 		})
@@ -379,6 +495,11 @@ func updateWorker(view db.Database, self db.Minion, store Store,
 		dbc.Command = etcdc.Command
 		dbc.Env = etcdc.Env
 		dbc.Labels = etcdc.Labels
+		dbc.Dependencies = etcdc.Dependencies
+		dbc.Job.Parallelism = etcdc.Job.Parallelism
+		dbc.Job.BackoffLimit = etcdc.Job.BackoffLimit
+		dbc.Job.Backoff = etcdc.Job.Backoff
+		dbc.Job.Retries = etcdc.Job.Retries
 
 		view.Commit(dbc)
 	}
@@ -395,13 +516,68 @@ func updateWorker(view db.Database, self db.Minion, store Store,
 	}
 
 	for _, c := range oldContainers {
-		ipAddr, ok := newIPMap[strconv.Itoa(c.StitchID)]
+		ipAddr, ok := newIPMap[dbcKey(c)]
 		if ok && ipAddr != c.IP {
 			c.IP = ipAddr
 			c.Mac = ip.ToMac(ipAddr)
 			view.Commit(c)
 		}
 	}
+
+	updateContainerDockerIDs(view.SelectFromContainer(nil), self, store)
+	updateContainerJobs(view.SelectFromContainer(nil), self, store)
+}
+
+// updateContainerDockerIDs publishes the Docker IDs of the containers running on
+// this minion.
+func updateContainerDockerIDs(containers []db.Container, self db.Minion,
+	store Store) {
+
+	dockerIDs := map[string]string{}
+	for _, c := range containers {
+		if c.DockerID != "" {
+			dockerIDs[dbcKey(c)] = c.DockerID
+		}
+	}
+
+	publishMinionMap(store, self, minionDockerIDStore, dockerIDs)
+}
+
+// updateContainerJobs publishes the progress of the job containers assigned to this
+// minion.
+func updateContainerJobs(containers []db.Container, self db.Minion, store Store) {
+	jobs := map[string]string{}
+	for _, c := range containers {
+		if c.IsJob() && c.Job.State != "" {
+			jobs[dbcKey(c)] = jobStatus(c.Job)
+		}
+	}
+
+	publishMinionMap(store, self, minionJobStore, jobs)
+}
+
+func publishMinionMap(store Store, self db.Minion, name string,
+	data map[string]string) {
+
+	key := path.Join(nodeStore, self.PrivateIP, name)
+	oldData := map[string]string{}
+	if etcdData, err := store.Get(key); err == nil {
+		json.Unmarshal([]byte(etcdData), &oldData)
+	}
+
+	if util.StrStrMapEqual(oldData, data) {
+		return
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		log.WithError(err).Errorf("Failed to marshal minion %s map", name)
+		return
+	}
+
+	if err := store.Set(key, string(jsonData), 0); err != nil {
+		log.WithError(err).Errorf("Failed to update minion %s map", name)
+	}
 }
 
 func updateContainerIP(etcdData storeData, containers []db.Container,
@@ -429,13 +605,12 @@ func updateContainerIP(etcdData storeData, containers []db.Container,
 
 	newIPMap := map[string]string{}
 	for _, c := range dbContainers {
-		newIPMap[strconv.Itoa(c.StitchID)] = ""
+		newIPMap[containerKey(c)] = ""
 	}
 
 	for _, pair := range pairs {
-		sdc := pair.R.(storeContainer)
-		sid := strconv.Itoa(sdc.StitchID)
-		newIPMap[sid] = oldIPMap[sid]
+		key := containerKey(pair.R.(storeContainer))
+		newIPMap[key] = oldIPMap[key]
 	}
 
 	ip.Sync(newIPMap, subnet, ip.SubMask)
@@ -459,7 +634,7 @@ func updateContainerIP(etcdData storeData, containers []db.Container,
 	return newIPMap, nil
 }
 
-func updateDBLabels(view db.Database, etcdData storeData, ipMap map[string]string) {
+func updateDBLabels(view db.Database, etcdData storeData, ipMap minionMaps) {
 	// Gather all of the label keys and IPs for single host labels, and IPs of
 	// the containers in a given label.
 	containerIPs := map[string][]string{}
@@ -468,7 +643,7 @@ func updateDBLabels(view db.Database, etcdData storeData, ipMap map[string]strin
 	for _, c := range etcdData.containers {
 		for _, l := range c.Labels {
 			labelKeys[l] = struct{}{}
-			cIP := ipMap[strconv.Itoa(c.StitchID)]
+			cIP := ipMap.lookup(c.Minion, containerKey(c))
 			if _, ok := etcdData.multiHost[l]; !ok {
 				labelIPs[l] = cIP
 			}
@@ -520,7 +695,8 @@ func containerJoinScore(left, right storeContainer) int {
 	if left.Minion != right.Minion ||
 		left.Image != right.Image ||
 		!util.StrSliceEqual(left.Command, right.Command) ||
-		!util.StrStrMapEqual(left.Env, right.Env) {
+		!util.StrStrMapEqual(left.Env, right.Env) ||
+		left.Job.Retries != right.Job.Retries {
 		return -1
 	}
 
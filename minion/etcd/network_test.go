@@ -252,15 +252,18 @@ func TestUpdateLeaderDBC(t *testing.T) {
 	conn.Transact(func(view db.Database) error {
 		dbc := view.InsertContainer()
 		dbc.StitchID = 1
+		dbc.Minion = "1.2.3.4"
 		view.Commit(dbc)
 
+		key := dbcKey(dbc)
 		updateLeaderDBC(view, view.SelectFromContainer(nil), storeData{
 			containers: []storeContainer{{StitchID: 1}},
-		}, map[string]string{"1": "foo"})
+		}, minionMaps{"1.2.3.4": {key: "foo"}, "1.2.3.5": {key: "bar"}},
+			minionMaps{"1.2.3.4": {key: "abc"}}, minionMaps{})
 
 		dbcs := view.SelectFromContainer(nil)
 		if len(dbcs) != 1 || dbcs[0].StitchID != 1 || dbcs[0].IP != "foo" ||
-			dbcs[0].Mac != "" {
+			dbcs[0].Mac != "" || dbcs[0].DockerID != "abc" {
 			t.Error(spew.Sprintf("Unexpected dbc: %v", dbc))
 		}
 
@@ -268,6 +271,40 @@ func TestUpdateLeaderDBC(t *testing.T) {
 	})
 }
 
+func TestUpdateLeaderJobs(t *testing.T) {
+	conn := db.New()
+	conn.Transact(func(view db.Database) error {
+		dbc := view.InsertContainer()
+		dbc.StitchID = 1
+		dbc.Minion = "1.2.3.4"
+		dbc.Job = db.Job{Parallelism: 1, State: db.JobRunning, Retries: 1}
+		view.Commit(dbc)
+
+		// Progress from an earlier attempt is ignored.
+		key := dbcKey(dbc)
+		jobMap := minionMaps{"1.2.3.4": {key: "failed:2:0"}}
+		updateLeaderDBC(view, view.SelectFromContainer(nil), storeData{},
+			minionMaps{}, minionMaps{}, jobMap)
+
+		dbcs := view.SelectFromContainer(nil)
+		if len(dbcs) != 1 || dbcs[0].Job.State != db.JobRunning {
+			t.Error(spew.Sprintf("Unexpected dbc: %v", dbcs))
+		}
+
+		jobMap = minionMaps{"1.2.3.4": {key: "failed:2:1"}}
+		updateLeaderDBC(view, view.SelectFromContainer(nil), storeData{},
+			minionMaps{}, minionMaps{}, jobMap)
+
+		dbcs = view.SelectFromContainer(nil)
+		if len(dbcs) != 1 || dbcs[0].Job.State != db.JobFailed ||
+			dbcs[0].Job.ExitCode != 2 || dbcs[0].Job.Finished.IsZero() {
+			t.Error(spew.Sprintf("Unexpected dbc: %v", dbcs))
+		}
+
+		return nil
+	})
+}
+
 func TestUpdateWorkerDBC(t *testing.T) {
 	conn := db.New()
 	conn.Transact(func(view db.Database) error {
@@ -345,11 +382,13 @@ func testUpdateWorkerDBC(t *testing.T, view db.Database) {
 		Subnet: "10.1.0.0"}, store, storeData{containers: cs})
 
 	ipMap := map[int]string{}
+	keyIPMap := map[string]string{}
 	labelMap := map[int][]string{}
 	commandMap := map[string][]string{}
 	envMap := map[string]map[string]string{}
 	for _, c := range view.SelectFromContainer(nil) {
 		ipMap[c.StitchID] = c.IP
+		keyIPMap[dbcKey(c)] = c.IP
 		labelMap[c.StitchID] = c.Labels
 		commandMap[c.DockerID] = c.Command
 		envMap[c.DockerID] = c.Env
@@ -373,9 +412,8 @@ func testUpdateWorkerDBC(t *testing.T, view db.Database) {
 	storeIPs, _ := store.Get(path.Join(minionDirKey, minionIPStore))
 	json.Unmarshal([]byte(storeIPs), &resultMap)
 
-	for id, ip := range resultMap {
-		sid, _ := strconv.Atoi(id)
-		if otherIP, ok := ipMap[sid]; !ok || ip != otherIP {
+	for key, ip := range resultMap {
+		if otherIP, ok := keyIPMap[key]; !ok || ip != otherIP {
 			t.Fatalf("IPs did not match: %s vs %s", ip, otherIP)
 		}
 	}
@@ -390,6 +428,70 @@ func testUpdateWorkerDBC(t *testing.T, view db.Database) {
 	}
 }
 
+func TestUpdateContainerDockerIDs(t *testing.T) {
+	store := newTestMock()
+	self := db.Minion{PrivateIP: "1.2.3.4"}
+	key := path.Join(nodeStore, self.PrivateIP, minionDockerIDStore)
+
+	// The old and new versions of a container being updated share a StitchID.
+	containers := []db.Container{
+		{StitchID: 1, DockerID: "abc"},
+		{StitchID: 1, Image: "new", DockerID: "def"},
+		{StitchID: 2},
+	}
+	updateContainerDockerIDs(containers, self, store)
+
+	stored, _ := store.Get(key)
+	dockerIDs := map[string]string{}
+	json.Unmarshal([]byte(stored), &dockerIDs)
+	exp := map[string]string{
+		dbcKey(containers[0]): "abc",
+		dbcKey(containers[1]): "def",
+	}
+	if !eq(dockerIDs, exp) {
+		t.Error(spew.Sprintf("\nGot: %v\nExp: %v\n", dockerIDs, exp))
+	}
+
+	// Nothing changed, so there should be no writes.
+	*store.writes = 0
+	updateContainerDockerIDs(containers, self, store)
+	if *store.writes != 0 {
+		t.Errorf("Unexpected writes (%d) to etcd store.", *store.writes)
+	}
+}
+
+func TestUpdateContainerJobs(t *testing.T) {
+	store := newTestMock()
+	self := db.Minion{PrivateIP: "1.2.3.4"}
+	key := path.Join(nodeStore, self.PrivateIP, minionJobStore)
+
+	containers := []db.Container{
+		{StitchID: 1, Job: db.Job{Parallelism: 1, State: db.JobFailed,
+			ExitCode: 2, Retries: 3}},
+		{StitchID: 2, Job: db.Job{Parallelism: 1}},
+		{StitchID: 3},
+	}
+	updateContainerJobs(containers, self, store)
+
+	stored, _ := store.Get(key)
+	jobs := map[string]string{}
+	json.Unmarshal([]byte(stored), &jobs)
+	jobKey := dbcKey(containers[0])
+	if exp := map[string]string{jobKey: "failed:2:3"}; !eq(jobs, exp) {
+		t.Error(spew.Sprintf("\nGot: %v\nExp: %v\n", jobs, exp))
+	}
+
+	state, exitCode, retries, ok := parseJobStatus(jobs[jobKey])
+	if !ok || state != db.JobFailed || exitCode != 2 || retries != 3 {
+		t.Errorf("Unexpected job status: %s %d %d %t", state, exitCode,
+			retries, ok)
+	}
+
+	if _, _, _, ok := parseJobStatus("failed:x:1"); ok {
+		t.Error("Expected malformed job status to fail parsing")
+	}
+}
+
 func TestContainerJoinScore(t *testing.T) {
 	t.Parallel()
 
@@ -423,18 +525,23 @@ func TestUpdateDBLabels(t *testing.T) {
 
 func testUpdateDBLabels(t *testing.T, view db.Database) {
 	labelStruct := map[string]string{"a": "10.0.0.2"}
-	ipMap := map[string]string{"1": "10.0.0.3", "2": "10.0.0.4"}
 	containerSlice := []storeContainer{
 		{
 			StitchID: 1,
+			Minion:   "1.2.3.4",
 			Labels:   []string{"a", "b"},
 		},
 		{
 			StitchID: 2,
+			Minion:   "1.2.3.5",
 			Labels:   []string{"a"},
 		},
 	}
 
+	ipMap := minionMaps{
+		"1.2.3.4": {containerKey(containerSlice[0]): "10.0.0.3"},
+		"1.2.3.5": {containerKey(containerSlice[1]): "10.0.0.4"},
+	}
 	updateDBLabels(view, storeData{
 		containers: containerSlice,
 		multiHost:  labelStruct,
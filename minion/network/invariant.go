@@ -0,0 +1,310 @@
+package network
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/join"
+	"github.com/NetSys/quilt/minion/ovsdb"
+	"github.com/NetSys/quilt/stitch"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// How often, in seconds, the leader checks the spec's invariants against the
+// running cluster.
+const invariantInterval = 60
+
+// The match of an ACL generated by `aclConnection.acls` that allows traffic from
+// the source to the destination's ports.
+var aclMatchPattern = regexp.MustCompile(`^ip4\.src==(\S+) && ip4\.dst==(\S+) && ` +
+	`\((\d+) <= udp\.dst <= (\d+) \|\| \d+ <= tcp\.dst <= \d+\)$`)
+
+// runInvariants periodically evaluates the spec's invariants against the network
+// as it's actually configured, so that drift, such as an ACL that failed to
+// install, is caught.
+func runInvariants(conn db.Conn) {
+	var checker invariantChecker
+	for range conn.TriggerTick(invariantInterval, db.EtcdTable).C {
+		checker.check(conn)
+	}
+}
+
+// An invariantChecker holds the last spec it compiled, so that the spec is only
+// compiled again when it changes.
+type invariantChecker struct {
+	spec     string
+	compiled *stitch.Stitch
+}
+
+// compile returns the compiled `spec`.  Failures aren't cached, as they may be
+// caused by an import that couldn't be fetched.
+func (c *invariantChecker) compile(spec string) (stitch.Stitch, error) {
+	if c.compiled == nil || spec != c.spec {
+		compiled, err := stitch.New(spec, stitch.DefaultImportGetter)
+		if err != nil {
+			return stitch.Stitch{}, err
+		}
+		c.spec, c.compiled = spec, &compiled
+	}
+	return *c.compiled, nil
+}
+
+func (c *invariantChecker) check(conn db.Conn) {
+	var leader bool
+	var spec string
+	var containers []db.Container
+	var labels []db.Label
+	var connections []db.Connection
+	var minions []db.Minion
+	conn.Transact(func(view db.Database) error {
+		leader = view.EtcdLeader()
+		if self, err := view.MinionSelf(); err == nil {
+			spec = self.Spec
+		}
+
+		containers = view.SelectFromContainer(nil)
+		labels = view.SelectFromLabel(nil)
+		connections = view.SelectFromConnection(nil)
+		minions = view.SelectFromMinion(nil)
+		return nil
+	})
+
+	// Only the leader knows where every container is, and only it manages the
+	// ACLs, so the other minions have nothing to report.
+	if !leader || spec == "" {
+		updateViolations(conn, nil)
+		return
+	}
+
+	compiled, err := c.compile(spec)
+	if err != nil {
+		log.WithError(err).Warn("Invalid spec.")
+		return
+	}
+
+	ovsdbClient, err := ovsdb.Open()
+	if err != nil {
+		log.WithError(err).Error("Failed to connect to OVSDB.")
+		return
+	}
+	defer ovsdbClient.Close()
+
+	acls, err := ovsdbClient.ListACLs(lSwitch)
+	if err != nil {
+		log.WithError(err).Error("Failed to list ACLS.")
+		return
+	}
+
+	graph := liveGraph(compiled, containers, labels, connections, acls, minions)
+	violations := compiled.CheckInvariants(graph)
+	for _, v := range violations {
+		log.WithField("counterexample", v.Counterexample).Warnf(
+			"Invariant failed: %s", v.Invariant)
+	}
+	updateViolations(conn, violations)
+}
+
+// liveGraph describes the running cluster in terms of the spec.  Containers are
+// connected according to the ACLs installed in OVN, and to the public internet
+// according to the connection table, as those aren't implemented with ACLs.
+// Containers are on the workers the scheduler placed them on, and those that
+// haven't been placed yet are scheduled on the workers according to the spec's
+// placement rules.
+func liveGraph(spec stitch.Stitch, containers []db.Container, labels []db.Label,
+	connections []db.Connection, acls []ovsdb.ACL,
+	minions []db.Minion) stitch.Graph {
+
+	annotations := map[string][]string{}
+	for _, label := range spec.QueryLabels() {
+		annotations[label.Name] = label.Annotations
+	}
+
+	graph := stitch.NewGraph()
+
+	graph.Constraints = spec.QueryPlacements()
+
+	// Containers on draining workers are about to be placed elsewhere, so those
+	// workers don't count.
+	workers := map[string]int{}
+	for _, m := range minions {
+		if m.Role == db.Worker && m.PrivateIP != "" && !m.Draining {
+			workers[m.PrivateIP] = len(graph.Machines)
+			graph.Machines = append(graph.Machines, stitch.Machine{
+				Provider: m.Provider,
+				Role:     string(m.Role),
+				Size:     m.Size,
+				Region:   m.Region,
+			})
+		}
+	}
+
+	ipNodes := map[string][]string{}
+	labelNodes := map[string][]string{}
+	for _, dbc := range containers {
+		name := stitch.NodeName(dbc.StitchID)
+		for _, label := range dbc.Labels {
+			graph.AddContainer(name, label, annotations[label])
+		}
+
+		if i, ok := workers[dbc.Minion]; ok {
+			graph.PlaceContainer(name, i)
+		}
+
+		// Containers without an IP address aren't on the network yet.
+		if dbc.IP == "" {
+			continue
+		}
+
+		for _, label := range dbc.Labels {
+			labelNodes[label] = append(labelNodes[label], name)
+		}
+		ipNodes[dbc.IP] = append(ipNodes[dbc.IP], name)
+	}
+
+	for _, label := range labels {
+		if label.IP != "" {
+			ipNodes[label.IP] = append(ipNodes[label.IP],
+				labelNodes[label.Label]...)
+		}
+	}
+
+	for _, conn := range connections {
+		var from, to []string
+		switch {
+		case conn.From == stitch.PublicInternetLabel:
+			from = []string{stitch.PublicInternetLabel}
+			to = labelNodes[conn.To]
+		case conn.To == stitch.PublicInternetLabel:
+			from = labelNodes[conn.From]
+			to = []string{stitch.PublicInternetLabel}
+		default:
+			continue
+		}
+		addEdges(&graph, from, to, conn.MinPort, conn.MaxPort)
+	}
+
+	for _, edge := range aclEdges(acls) {
+		addEdges(&graph, ipNodes[edge.fromIP], ipNodes[edge.toIP],
+			edge.minPort, edge.maxPort)
+	}
+
+	return graph
+}
+
+func addEdges(graph *stitch.Graph, from, to []string, minPort, maxPort int) {
+	for _, f := range from {
+		for _, t := range to {
+			if err := graph.AddEdge(f, t, minPort, maxPort); err != nil {
+				log.WithError(err).Warn("Failed to add edge to graph.")
+			}
+		}
+	}
+}
+
+type aclEdge struct {
+	fromIP  string
+	toIP    string
+	minPort int
+	maxPort int
+}
+
+// aclEdges parses the connections permitted by `acls`.  OVN evaluates both the
+// "from-lport" and "to-lport" ACLs, so traffic is only allowed if both directions
+// allow it.
+func aclEdges(acls []ovsdb.ACL) []aclEdge {
+	var fromLport, toLport []ovsdb.ACLCore
+	for _, acl := range acls {
+		if acl.Core.Action != "allow" {
+			continue
+		}
+
+		switch acl.Core.Direction {
+		case "from-lport":
+			fromLport = append(fromLport, acl.Core)
+		case "to-lport":
+			toLport = append(toLport, acl.Core)
+		}
+	}
+
+	matchKey := func(val interface{}) interface{} {
+		return val.(ovsdb.ACLCore).Match
+	}
+	pairs, _, _ := join.HashJoin(aclCoreSlice(fromLport), aclCoreSlice(toLport),
+		matchKey, matchKey)
+
+	var edges []aclEdge
+	for _, pair := range pairs {
+		match := aclMatchPattern.FindStringSubmatch(pair.L.(ovsdb.ACLCore).Match)
+		if match == nil {
+			continue
+		}
+
+		minPort, _ := strconv.Atoi(match[3])
+		maxPort, _ := strconv.Atoi(match[4])
+		edges = append(edges, aclEdge{
+			fromIP:  match[1],
+			toIP:    match[2],
+			minPort: minPort,
+			maxPort: maxPort,
+		})
+	}
+	return edges
+}
+
+// updateViolations replaces the violation table with `violations`.
+func updateViolations(conn db.Conn, violations []stitch.Violation) {
+	conn.Transact(func(view db.Database) error {
+		key := func(val interface{}) interface{} {
+			switch v := val.(type) {
+			case db.Violation:
+				return stitch.Violation{
+					Invariant:      v.Invariant,
+					Counterexample: v.Counterexample,
+				}
+			default:
+				return v
+			}
+		}
+
+		// Unchanged violations are left alone so that the table isn't
+		// rewritten on every check.
+		_, dbvs, stitchvs := join.HashJoin(
+			db.ViolationSlice(view.SelectFromViolation(nil)),
+			violationSlice(violations), key, key)
+
+		for _, dbv := range dbvs {
+			view.Remove(dbv.(db.Violation))
+		}
+
+		for _, stitchv := range stitchvs {
+			v := stitchv.(stitch.Violation)
+			dbv := view.InsertViolation()
+			dbv.Invariant = v.Invariant
+			dbv.Counterexample = v.Counterexample
+			view.Commit(dbv)
+		}
+		return nil
+	})
+}
+
+type aclCoreSlice []ovsdb.ACLCore
+
+func (acls aclCoreSlice) Get(i int) interface{} {
+	return acls[i]
+}
+
+func (acls aclCoreSlice) Len() int {
+	return len(acls)
+}
+
+type violationSlice []stitch.Violation
+
+func (vs violationSlice) Get(i int) interface{} {
+	return vs[i]
+}
+
+func (vs violationSlice) Len() int {
+	return len(vs)
+}
@@ -0,0 +1,237 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/minion/ovsdb"
+	"github.com/NetSys/quilt/stitch"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	client := ovsdb.NewFakeOvsdbClient()
+	client.CreateLogicalSwitch(lSwitch)
+	ovsdb.Open = func() (ovsdb.Client, error) {
+		return client, nil
+	}
+
+	conn := db.New()
+	conn.Transact(func(view db.Database) error {
+		etcd := view.InsertEtcd()
+		etcd.Leader = true
+		view.Commit(etcd)
+
+		self := view.InsertMinion()
+		self.Self = true
+		self.Role = db.Master
+		self.Spec = `var a = new Service("a", [new Container("ubuntu")]);
+		var b = new Service("b", [new Container("ubuntu")]);
+		a.connect(80, b);
+		deployment.deploy([a, b]);
+		deployment.assert(a.canReach(b), true);
+		deployment.assert(b.canReach(a), false);`
+		view.Commit(self)
+
+		worker := view.InsertMinion()
+		worker.Role = db.Worker
+		worker.PrivateIP = "192.168.0.2"
+		view.Commit(worker)
+
+		for i, label := range []string{"a", "b"} {
+			dbc := view.InsertContainer()
+			dbc.StitchID = i + 1
+			dbc.Labels = []string{label}
+			dbc.IP = []string{"10.0.0.2", "10.0.0.3"}[i]
+			dbc.Minion = "192.168.0.2"
+			view.Commit(dbc)
+
+			dbl := view.InsertLabel()
+			dbl.Label = label
+			dbl.IP = []string{"10.1.0.2", "10.1.0.3"}[i]
+			dbl.ContainerIPs = []string{dbc.IP}
+			view.Commit(dbl)
+		}
+
+		dbconn := view.InsertConnection()
+		dbconn.From = "a"
+		dbconn.To = "b"
+		dbconn.MinPort = 80
+		dbconn.MaxPort = 80
+		view.Commit(dbconn)
+		return nil
+	})
+
+	var checker invariantChecker
+	updateACLs(conn.SelectFromConnection(nil), conn.SelectFromLabel(nil),
+		conn.SelectFromContainer(nil))
+	checker.check(conn)
+	if violations := conn.SelectFromViolation(nil); len(violations) != 0 {
+		t.Errorf("Unexpected violations: %v", violations)
+	}
+
+	// Simulate an ACL that failed to install.
+	acls, _ := client.ListACLs(lSwitch)
+	for _, acl := range acls {
+		if acl.Core.Direction == "to-lport" && acl.Core.Action == "allow" {
+			client.DeleteACL(lSwitch, acl)
+		}
+	}
+
+	// The spec hasn't changed, so it isn't compiled again.
+	compiled := checker.compiled
+	checker.check(conn)
+	if checker.compiled != compiled {
+		t.Error("Expected the compiled spec to be reused")
+	}
+
+	exp := []db.Violation{{
+		Invariant:      `reach true "a" "b"`,
+		Counterexample: "container 1 (a) can't reach container 2 (b)",
+	}}
+	violations := conn.SelectFromViolation(nil)
+	for i := range violations {
+		violations[i].ID = 0
+	}
+	if !reflect.DeepEqual(violations, exp) {
+		t.Errorf("Violations = %v, expected %v", violations, exp)
+	}
+
+	// Followers don't report violations.
+	conn.Transact(func(view db.Database) error {
+		etcd := view.SelectFromEtcd(nil)[0]
+		etcd.Leader = false
+		view.Commit(etcd)
+		return nil
+	})
+	checker.check(conn)
+	if violations := conn.SelectFromViolation(nil); len(violations) != 0 {
+		t.Errorf("Unexpected violations: %v", violations)
+	}
+}
+
+func TestLiveGraphPlacement(t *testing.T) {
+	spec, err := stitch.New(`var services = ["a", "b", "c", "d"].map(
+		function(name) {
+			return new Service(name, [new Container("ubuntu")]);
+		});
+	var worker = new Machine({role: "Worker"});
+	deployment.deploy(services);
+	deployment.deploy([worker, worker.clone(), worker.clone()]);
+	[0, 1].forEach(function(i) {
+		services[i].place(new LabelRule(true, services[2]));
+		services[i].place(new LabelRule(true, services[3]));
+	});
+	deployment.assert(enough, true);`, stitch.DefaultImportGetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	minions := []db.Minion{
+		{Role: db.Worker, PrivateIP: "192.168.0.2"},
+		{Role: db.Worker, PrivateIP: "192.168.0.3"},
+		{Role: db.Worker, PrivateIP: "192.168.0.4", Draining: true},
+	}
+	containers := []db.Container{
+		{StitchID: 1, Labels: []string{"a"}, Minion: "192.168.0.2"},
+		{StitchID: 2, Labels: []string{"b"}, Minion: "192.168.0.2"},
+		{StitchID: 3, Labels: []string{"c"}, Minion: "192.168.0.3"},
+		{StitchID: 4, Labels: []string{"d"}},
+	}
+
+	// Scheduling every container from scratch would put "a" and "b" on
+	// separate workers, leaving nowhere for "c" and "d".  The containers that
+	// are placed stay where they are, so "d" fits with "c".
+	check := func(exp []string) {
+		graph := liveGraph(spec, containers, nil, nil, nil, minions)
+
+		var counterexamples []string
+		for _, v := range spec.CheckInvariants(graph) {
+			counterexamples = append(counterexamples, v.Counterexample)
+		}
+		if !reflect.DeepEqual(counterexamples, exp) {
+			t.Errorf("Counterexamples = %v, expected %v",
+				counterexamples, exp)
+		}
+	}
+	check(nil)
+
+	// Draining workers don't count.
+	minions[1].Draining = true
+	check([]string{"container 3 (c) can't be placed: every Worker machine " +
+		"already hosts a container it must be kept apart from; " +
+		"container 4 (d) can't be placed: every Worker machine already " +
+		"hosts a container it must be kept apart from"})
+}
+
+func TestLiveGraphNodes(t *testing.T) {
+	spec, err := stitch.New(`deployment.deploy([
+		new Service("a", new Container("ubuntu").replicate(2)),
+		new Service("b", [new Container("ubuntu")])]);`,
+		stitch.DefaultImportGetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	specGraph, err := stitch.InitializeGraph(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var containers []db.Container
+	for _, label := range spec.QueryLabels() {
+		for _, id := range label.IDs {
+			containers = append(containers, db.Container{
+				StitchID: id,
+				Labels:   []string{label.Name},
+			})
+		}
+	}
+
+	// The nodes of the running containers are the nodes of the spec.
+	graph := liveGraph(spec, containers, nil, nil, nil, nil)
+	nodeLabels := func(g stitch.Graph) map[string]string {
+		labels := map[string]string{}
+		for name, node := range g.Nodes {
+			labels[name] = node.Label
+		}
+		return labels
+	}
+	exp := nodeLabels(specGraph)
+	if actual := nodeLabels(graph); !reflect.DeepEqual(actual, exp) {
+		t.Errorf("Nodes = %v, expected %v", actual, exp)
+	}
+}
+
+func TestACLEdges(t *testing.T) {
+	t.Parallel()
+
+	conns := []aclConnection{{
+		fromIPs: []string{"10.0.0.2"},
+		toIPs:   []string{"10.0.0.3"},
+		minPort: 80,
+		maxPort: 90,
+	}}
+
+	var acls []ovsdb.ACL
+	for core := range generateACLs(conns) {
+		acls = append(acls, ovsdb.ACL{Core: core})
+	}
+
+	exp := []aclEdge{{fromIP: "10.0.0.2", toIP: "10.0.0.3", minPort: 80,
+		maxPort: 90}}
+	if edges := aclEdges(acls); !reflect.DeepEqual(edges, exp) {
+		t.Errorf("Edges = %v, expected %v", edges, exp)
+	}
+
+	// Traffic must be allowed in both directions.
+	var fromOnly []ovsdb.ACL
+	for _, acl := range acls {
+		if acl.Core.Direction == "from-lport" {
+			fromOnly = append(fromOnly, acl)
+		}
+	}
+	if edges := aclEdges(fromOnly); len(edges) != 0 {
+		t.Errorf("Unexpected edges: %v", edges)
+	}
+}
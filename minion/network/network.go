@@ -49,6 +49,8 @@ func Run(conn db.Conn, dk docker.Client) {
 		time.Sleep(5 * time.Second)
 	}
 
+	go runInvariants(conn)
+
 	loopLog := util.NewEventTimer("Network")
 	for range conn.TriggerTick(30, db.MinionTable, db.ContainerTable,
 		db.ConnectionTable, db.LabelTable, db.EtcdTable).C {
@@ -486,7 +486,7 @@ func generateTargetNatRules(publicInterface string, containers []db.Container,
 	protocols := []string{"tcp", "udp"}
 	// Map each container IP to all ports on which it can receive packets
 	// from the public internet.
-	portsFromWeb := make(map[string]map[int]struct{})
+	portsFromWeb := make(map[string]map[publicPorts]struct{})
 
 	for _, dbc := range containers {
 		for _, conn := range connections {
@@ -501,24 +501,47 @@ func generateTargetNatRules(publicInterface string, containers []db.Container,
 					continue
 				}
 
-				if _, ok := portsFromWeb[dbc.IP]; !ok {
-					portsFromWeb[dbc.IP] = make(map[int]struct{})
+				p, err := toPublicPorts(conn)
+				if err != nil {
+					log.WithError(err).WithField("connection", conn).
+						Error("Skipping public connection")
+					continue
+				}
+
+				ports, ok := portsFromWeb[dbc.IP]
+				if !ok {
+					ports = map[publicPorts]struct{}{}
+					portsFromWeb[dbc.IP] = ports
 				}
 
-				portsFromWeb[dbc.IP][conn.MinPort] = struct{}{}
+				ports[p] = struct{}{}
 			}
 		}
 	}
 
-	// Map the container's port to the same port of the host.
+	// Map the container's ports to the same ports of the host.
 	for ip, ports := range portsFromWeb {
-		for port := range ports {
+		for p := range ports {
+			var src string
+			if p.cidr != "" {
+				src = fmt.Sprintf("-s %s ", p.cidr)
+			}
+
+			// A DNAT without a port preserves the destination port, which
+			// is what we want for port ranges.
+			dport := fmt.Sprintf("%d", p.min)
+			dest := fmt.Sprintf("%s:%d", ip, p.min)
+			if p.min != p.max {
+				dport = fmt.Sprintf("%d:%d", p.min, p.max)
+				dest = ip
+			}
+
 			for _, protocol := range protocols {
 				strRules = append(strRules, fmt.Sprintf(
-					"-A PREROUTING -i %[1]s "+
-						"-p %[2]s -m %[2]s --dport %[3]d -j "+
-						"DNAT --to-destination %[4]s:%[3]d",
-					publicInterface, protocol, port, ip))
+					"-A PREROUTING %[1]s-i %[2]s "+
+						"-p %[3]s -m %[3]s --dport %[4]s -j "+
+						"DNAT --to-destination %[5]s",
+					src, publicInterface, protocol, dport, dest))
 			}
 		}
 	}
@@ -953,16 +976,29 @@ func generateTargetOpenFlow(dk docker.Client, odb ovsdb.Client,
 
 		protocols := []string{"tcp", "udp"}
 
-		portsToWeb := make(map[int]struct{})
-		portsFromWeb := make(map[int]struct{})
+		portsToWeb := make(map[publicPorts]struct{})
+		portsFromWeb := make(map[publicPorts]struct{})
 		for _, l := range dbc.Labels {
 			for _, conn := range connections {
-				if conn.From == l &&
-					conn.To == stitch.PublicInternetLabel {
-					portsToWeb[conn.MinPort] = struct{}{}
-				} else if conn.From ==
-					stitch.PublicInternetLabel && conn.To == l {
-					portsFromWeb[conn.MinPort] = struct{}{}
+				toWeb := conn.From == l &&
+					conn.To == stitch.PublicInternetLabel
+				fromWeb := conn.From == stitch.PublicInternetLabel &&
+					conn.To == l
+				if !toWeb && !fromWeb {
+					continue
+				}
+
+				p, err := toPublicPorts(conn)
+				if err != nil {
+					log.WithError(err).WithField("connection", conn).
+						Error("Skipping public connection")
+					continue
+				}
+
+				if toWeb {
+					portsToWeb[p] = struct{}{}
+				} else {
+					portsFromWeb[p] = struct{}{}
 				}
 			}
 		}
@@ -974,27 +1010,35 @@ func generateTargetOpenFlow(dk docker.Client, odb ovsdb.Client,
 		ingressRule := fmt.Sprintf("table=0 priority=%d,in_port=LOCAL,", 5000) +
 			"%s,%s," + fmt.Sprintf("dl_dst=%s actions=%d", dbcMac, ofVeth)
 
-		for port := range portsFromWeb {
-			for _, protocol := range protocols {
-				egressPort := fmt.Sprintf("tp_src=%d", port)
-				rules = append(rules, fmt.Sprintf(egressRule, protocol,
-					egressPort))
-
-				ingressPort := fmt.Sprintf("tp_dst=%d", port)
-				rules = append(rules, fmt.Sprintf(ingressRule, protocol,
-					ingressPort))
+		for p := range portsFromWeb {
+			egressHost := p.ofMatch("nw_dst")
+			ingressHost := p.ofMatch("nw_src")
+			for _, port := range portMasks(p.min, p.max) {
+				for _, protocol := range protocols {
+					egressPort := "tp_src=" + port + egressHost
+					rules = append(rules, fmt.Sprintf(egressRule,
+						protocol, egressPort))
+
+					ingressPort := "tp_dst=" + port + ingressHost
+					rules = append(rules, fmt.Sprintf(ingressRule,
+						protocol, ingressPort))
+				}
 			}
 		}
 
-		for port := range portsToWeb {
-			for _, protocol := range protocols {
-				egressPort := fmt.Sprintf("tp_dst=%d", port)
-				rules = append(rules, fmt.Sprintf(egressRule, protocol,
-					egressPort))
-
-				ingressPort := fmt.Sprintf("tp_src=%d", port)
-				rules = append(rules, fmt.Sprintf(ingressRule, protocol,
-					ingressPort))
+		for p := range portsToWeb {
+			egressHost := p.ofMatch("nw_dst")
+			ingressHost := p.ofMatch("nw_src")
+			for _, port := range portMasks(p.min, p.max) {
+				for _, protocol := range protocols {
+					egressPort := "tp_dst=" + port + egressHost
+					rules = append(rules, fmt.Sprintf(egressRule,
+						protocol, egressPort))
+
+					ingressPort := "tp_src=" + port + ingressHost
+					rules = append(rules, fmt.Sprintf(ingressRule,
+						protocol, ingressPort))
+				}
 			}
 		}
 
@@ -1371,6 +1415,72 @@ func namespaceName(namespace string) string {
 	return fmt.Sprintf("%s namespace", namespace)
 }
 
+// publicPorts is a range of ports open to the hosts in cidr on the public
+// internet.  An empty cidr matches all hosts.
+type publicPorts struct {
+	min, max int
+	cidr     string
+}
+
+// toPublicPorts returns the ports `conn` opens to the public internet.  A
+// connection from a CIDR that can't be parsed is an error, rather than being
+// opened to every host.
+func toPublicPorts(conn db.Connection) (publicPorts, error) {
+	p := publicPorts{min: conn.MinPort, max: conn.MaxPort}
+	if conn.CidrIP == "" {
+		return p, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(conn.CidrIP)
+	if err != nil {
+		return publicPorts{}, err
+	}
+
+	// Normalize the CIDR so that the rules we generate match the rules
+	// reported by iptables and OpenFlow.
+	if ones, _ := ipNet.Mask.Size(); ones != 0 {
+		p.cidr = ipNet.String()
+	}
+	return p, nil
+}
+
+// ofMatch returns the OpenFlow match on `field` that restricts traffic to the
+// hosts in p.cidr, or an empty string if there is no restriction.
+func (p publicPorts) ofMatch(field string) string {
+	if p.cidr == "" {
+		return ""
+	}
+
+	ip, ipNet, _ := net.ParseCIDR(p.cidr)
+	if ones, bits := ipNet.Mask.Size(); ones == bits {
+		return fmt.Sprintf(",%s=%s", field, ip)
+	}
+	return fmt.Sprintf(",%s=%s", field, p.cidr)
+}
+
+// portMasks converts the port range [min, max] into the smallest set of
+// masked OpenFlow port matches that cover it.
+func portMasks(min, max int) []string {
+	var masks []string
+	for min <= max {
+		// Find the largest aligned block starting at `min` that fits in the
+		// range.
+		size := 1
+		for min%(size*2) == 0 && min+size*2-1 <= max && size < 1<<16 {
+			size *= 2
+		}
+
+		if size == 1 {
+			masks = append(masks, fmt.Sprintf("%d", min))
+		} else {
+			masks = append(masks, fmt.Sprintf("0x%x/0x%x", min,
+				0xffff&^(size-1)))
+		}
+		min += size
+	}
+	return masks
+}
+
 // makeIPRule takes an ip rule as formatted in the output of `iptables -S`,
 // and returns the corresponding ipRule. The output options will be in the same
 // order as output by `iptables -S`.
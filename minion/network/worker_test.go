@@ -2,6 +2,7 @@ package network
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/NetSys/quilt/db"
@@ -242,6 +243,69 @@ func TestGenerateCurrentNatRules(t *testing.T) {
 	}
 }
 
+func TestGenerateTargetNatRules(t *testing.T) {
+	containers := []db.Container{
+		{IP: "10.0.0.2", Labels: []string{"web"}},
+		{IP: "10.0.0.3", Labels: []string{"admin"}},
+	}
+	connections := []db.Connection{
+		{From: "public", To: "web", MinPort: 80, MaxPort: 80},
+		{From: "public", To: "web", MinPort: 5000, MaxPort: 5010,
+			CidrIP: "0.0.0.0/0"},
+		{From: "public", To: "admin", MinPort: 8080, MaxPort: 8080,
+			CidrIP: "1.2.3.4/24"},
+		{From: "admin", To: "public", MinPort: 22, MaxPort: 22},
+
+		// A CIDR that can't be parsed isn't opened to everyone.
+		{From: "public", To: "admin", MinPort: 9000, MaxPort: 9000,
+			CidrIP: "1.2.3.4/99"},
+	}
+
+	var actual []string
+	for _, rule := range generateTargetNatRules("eth0", containers, connections) {
+		if rule.chain == "PREROUTING" && rule.cmd == "-A" {
+			actual = append(actual, rule.opts)
+		}
+	}
+	sort.Strings(actual)
+
+	exp := []string{
+		"-i eth0 -p tcp -m tcp --dport 5000:5010 -j DNAT " +
+			"--to-destination 10.0.0.2",
+		"-i eth0 -p tcp -m tcp --dport 80 -j DNAT " +
+			"--to-destination 10.0.0.2:80",
+		"-i eth0 -p udp -m udp --dport 5000:5010 -j DNAT " +
+			"--to-destination 10.0.0.2",
+		"-i eth0 -p udp -m udp --dport 80 -j DNAT " +
+			"--to-destination 10.0.0.2:80",
+		"-s 1.2.3.0/24 -i eth0 -p tcp -m tcp --dport 8080 -j DNAT " +
+			"--to-destination 10.0.0.3:8080",
+		"-s 1.2.3.0/24 -i eth0 -p udp -m udp --dport 8080 -j DNAT " +
+			"--to-destination 10.0.0.3:8080",
+	}
+
+	if !reflect.DeepEqual(actual, exp) {
+		t.Errorf("Generated wrong NAT rules.\nExpected:\n%v\n\nGot:\n%v\n",
+			exp, actual)
+	}
+}
+
+func TestPortMasks(t *testing.T) {
+	check := func(min, max int, exp []string) {
+		actual := portMasks(min, max)
+		if !reflect.DeepEqual(actual, exp) {
+			t.Errorf("portMasks(%d, %d): expected %v, got %v",
+				min, max, exp, actual)
+		}
+	}
+
+	check(80, 80, []string{"80"})
+	check(80, 95, []string{"0x50/0xfff0"})
+	check(79, 96, []string{"79", "0x50/0xfff0", "96"})
+	check(1000, 1005, []string{"0x3e8/0xfffc", "0x3ec/0xfffe"})
+	check(0, 65535, []string{"0x0/0x0"})
+}
+
 func TestMakeOFRule(t *testing.T) {
 	flows := []string{
 		"cookie=0x0, duration=997.526s, table=0, n_packets=0, " +
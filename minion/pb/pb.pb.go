@@ -68,6 +68,7 @@ type MinionConfig struct {
 	Provider  string            `protobuf:"bytes,5,opt,name=Provider,json=provider" json:"Provider,omitempty"`
 	Size      string            `protobuf:"bytes,6,opt,name=Size,json=size" json:"Size,omitempty"`
 	Region    string            `protobuf:"bytes,7,opt,name=Region,json=region" json:"Region,omitempty"`
+	Draining  bool              `protobuf:"varint,8,opt,name=Draining,json=draining" json:"Draining,omitempty"`
 }
 
 func (m *MinionConfig) Reset()                    { *m = MinionConfig{} }
@@ -251,27 +252,28 @@ var _Minion_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pb/pb.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 344 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x5c, 0x51, 0x5d, 0x4b, 0xeb, 0x40,
-	0x10, 0x6d, 0xbe, 0x93, 0x69, 0x6f, 0x5b, 0x86, 0xcb, 0x65, 0x29, 0x17, 0x94, 0x3c, 0x48, 0x11,
-	0x89, 0x50, 0x1f, 0x7c, 0x56, 0x1b, 0xa4, 0x48, 0xdb, 0xb0, 0x11, 0x7c, 0x36, 0xe9, 0x5a, 0x16,
-	0x6a, 0x37, 0xee, 0xa6, 0x05, 0xfd, 0x01, 0xfe, 0x58, 0x7f, 0x85, 0x9b, 0xb5, 0x62, 0xeb, 0xdb,
-	0x39, 0x67, 0xce, 0xce, 0x9c, 0x99, 0x85, 0x76, 0x55, 0x9c, 0x57, 0x45, 0x52, 0x49, 0x51, 0x8b,
-	0xf8, 0xc3, 0x82, 0xce, 0x94, 0xaf, 0xb9, 0x58, 0xdf, 0x88, 0xf5, 0x13, 0x5f, 0x62, 0x17, 0xec,
-	0xc9, 0x98, 0x58, 0xc7, 0xd6, 0x30, 0xa2, 0x36, 0x1f, 0xe3, 0x09, 0xb8, 0x52, 0xac, 0x18, 0xb1,
-	0xb5, 0xd2, 0x1d, 0x61, 0xb2, 0x6f, 0x4e, 0xa8, 0xae, 0x50, 0x53, 0xc7, 0xff, 0x10, 0x65, 0x92,
-	0x6f, 0x1f, 0x6b, 0x36, 0xc9, 0x88, 0x63, 0x9e, 0x47, 0xd5, 0xb7, 0x80, 0x08, 0x6e, 0x5e, 0xb1,
-	0x92, 0xb8, 0xa6, 0xe0, 0x2a, 0x8d, 0x71, 0x00, 0x61, 0x26, 0xc5, 0x96, 0x2f, 0x98, 0x24, 0x9e,
-	0xd1, 0xc3, 0x6a, 0xc7, 0x8d, 0x9f, 0xbf, 0x31, 0xe2, 0xef, 0xfc, 0x1a, 0xe3, 0x3f, 0xf0, 0x29,
-	0x5b, 0xea, 0xe1, 0x24, 0x30, 0xaa, 0x2f, 0x0d, 0x8b, 0x87, 0xe0, 0x36, 0x39, 0x30, 0x04, 0x77,
-	0x36, 0x9f, 0xa5, 0xfd, 0x16, 0x02, 0xf8, 0x0f, 0x73, 0x7a, 0x97, 0xd2, 0xbe, 0xd5, 0xe0, 0xe9,
-	0x55, 0x7e, 0xaf, 0xb1, 0x1d, 0x5f, 0x82, 0x47, 0x59, 0xb5, 0x7a, 0x45, 0x02, 0x41, 0xbe, 0x29,
-	0x4b, 0xa6, 0x94, 0xd9, 0x34, 0xa4, 0x81, 0xfa, 0xa2, 0xf8, 0x17, 0xbc, 0x54, 0x4a, 0x21, 0xcd,
-	0xbe, 0x11, 0xf5, 0x58, 0x43, 0xe2, 0x08, 0x02, 0xca, 0x5e, 0x36, 0x4c, 0xd5, 0xf1, 0x11, 0xb4,
-	0xd3, 0xba, 0x5c, 0x4c, 0xd9, 0x73, 0xc1, 0xa4, 0xc2, 0x3e, 0x38, 0x93, 0xac, 0xe9, 0xe2, 0x68,
-	0xb7, 0xc3, 0x33, 0x35, 0x7a, 0xb7, 0xf4, 0x44, 0x73, 0x24, 0x3c, 0x85, 0x5e, 0xce, 0xea, 0x83,
-	0xf3, 0xfe, 0x39, 0x38, 0xe0, 0xc0, 0x4f, 0x4c, 0xa0, 0xb8, 0x85, 0x67, 0xd0, 0xbb, 0xfd, 0xe5,
-	0x0d, 0x93, 0xdd, 0xd0, 0xc1, 0xe1, 0x2b, 0xed, 0x8e, 0x21, 0xbc, 0x16, 0xa2, 0x6e, 0x92, 0x60,
-	0x27, 0xd9, 0x0b, 0xf4, 0xd3, 0xb1, 0xf0, 0xcd, 0x0f, 0x5f, 0x7c, 0x06, 0x00, 0x00, 0xff, 0xff,
-	0x89, 0x60, 0x91, 0x75, 0xf0, 0x01, 0x00, 0x00,
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x5c, 0x51, 0x5d, 0x8b, 0xd3, 0x40,
+	0x14, 0x6d, 0xbe, 0x27, 0x77, 0xd7, 0xdd, 0x70, 0x11, 0x19, 0x8a, 0x60, 0x99, 0x07, 0x09, 0x22,
+	0x11, 0xd6, 0x07, 0x9f, 0xd5, 0x06, 0x29, 0xd2, 0xdd, 0x30, 0x11, 0x7c, 0xde, 0xa4, 0xd7, 0x32,
+	0x50, 0x33, 0xe3, 0x24, 0x5b, 0xb0, 0x3f, 0xa0, 0xbf, 0x5b, 0x32, 0x8d, 0xd8, 0xfa, 0x36, 0xe7,
+	0xdc, 0x73, 0xcf, 0x1c, 0xce, 0x85, 0x2b, 0xd3, 0xbc, 0x33, 0x4d, 0x61, 0xac, 0x1e, 0xb4, 0x38,
+	0xfa, 0x70, 0xbd, 0x56, 0x9d, 0xd2, 0xdd, 0x67, 0xdd, 0xfd, 0x50, 0x5b, 0xbc, 0x01, 0x7f, 0xb5,
+	0xe4, 0xde, 0xc2, 0xcb, 0x53, 0xe9, 0xab, 0x25, 0xbe, 0x86, 0xd0, 0xea, 0x1d, 0x71, 0x7f, 0xe1,
+	0xe5, 0x37, 0x77, 0x58, 0x9c, 0x8b, 0x0b, 0xa9, 0x77, 0x24, 0xdd, 0x1c, 0x5f, 0x42, 0x5a, 0x59,
+	0xb5, 0x7f, 0x1c, 0x68, 0x55, 0xf1, 0xc0, 0xad, 0xa7, 0xe6, 0x2f, 0x81, 0x08, 0x61, 0x6d, 0xa8,
+	0xe5, 0xa1, 0x1b, 0x84, 0xbd, 0xa1, 0x16, 0xe7, 0xc0, 0x2a, 0xab, 0xf7, 0x6a, 0x43, 0x96, 0x47,
+	0x8e, 0x67, 0x66, 0xc2, 0x4e, 0xaf, 0x0e, 0xc4, 0xe3, 0x49, 0xaf, 0x0e, 0x84, 0x2f, 0x20, 0x96,
+	0xb4, 0x55, 0xba, 0xe3, 0x89, 0x63, 0x63, 0xeb, 0xd0, 0xe8, 0xb3, 0xb4, 0x8f, 0xaa, 0x53, 0xdd,
+	0x96, 0xb3, 0x85, 0x97, 0x33, 0xc9, 0x36, 0x13, 0x16, 0x39, 0x84, 0x63, 0x46, 0x64, 0x10, 0xde,
+	0x3f, 0xdc, 0x97, 0xd9, 0x0c, 0x01, 0xe2, 0xef, 0x0f, 0xf2, 0x6b, 0x29, 0x33, 0x6f, 0x7c, 0xaf,
+	0x3f, 0xd6, 0xdf, 0x4a, 0x99, 0xf9, 0xe2, 0x03, 0x44, 0x92, 0xcc, 0xee, 0x37, 0x72, 0x48, 0xea,
+	0xa7, 0xb6, 0xa5, 0xbe, 0x77, 0x2d, 0x30, 0x99, 0xf4, 0x27, 0x88, 0xcf, 0x21, 0x2a, 0xad, 0xd5,
+	0xd6, 0x75, 0x91, 0xca, 0x88, 0x46, 0x20, 0x52, 0x48, 0x24, 0xfd, 0x7a, 0xa2, 0x7e, 0x10, 0xaf,
+	0xe0, 0xaa, 0x1c, 0xda, 0xcd, 0x9a, 0x7e, 0x36, 0x64, 0x7b, 0xcc, 0x20, 0x58, 0x55, 0xa3, 0x4b,
+	0x90, 0xa7, 0x32, 0x50, 0x55, 0x7f, 0x77, 0xf4, 0x20, 0x3e, 0x15, 0x88, 0x6f, 0xe0, 0xb6, 0xa6,
+	0xe1, 0xa2, 0xfa, 0x67, 0x17, 0xe5, 0xce, 0xe3, 0xc2, 0x05, 0x12, 0x33, 0x7c, 0x0b, 0xb7, 0x5f,
+	0xfe, 0xd3, 0xb2, 0x62, 0xfa, 0x74, 0x7e, 0xb9, 0x25, 0x66, 0x28, 0x80, 0x7d, 0xd2, 0x7a, 0x18,
+	0x93, 0xe0, 0x75, 0x71, 0x16, 0xe8, 0x9f, 0x63, 0x13, 0xbb, 0xeb, 0xbf, 0xff, 0x33, 0x00, 0xe1,
+	0x47, 0x54, 0x11, 0x0c, 0x02, 0x00, 0x00,
 }
@@ -18,6 +18,11 @@ import (
 	log "github.com/Sirupsen/logrus"
 )
 
+// Stitches aren't run on the minion, so there are no machines for its API server to
+// protect.  No stitch can terminate more than all of the machines, so this fraction
+// never refuses one.
+const noTerminateLimit = 1.0
+
 // Run blocks executing the minion.
 func Run() {
 	// XXX Uncomment the following line to run the profiler
@@ -33,10 +38,15 @@ func Run() {
 	go network.Run(conn, dk)
 	go etcd.Run(conn)
 
-	go apiServer.Run(conn, fmt.Sprintf("tcp://0.0.0.0:%d", api.DefaultRemotePort))
+	go apiServer.Run(conn, fmt.Sprintf("tcp://0.0.0.0:%d", api.DefaultRemotePort),
+		noTerminateLimit)
 
+	// Rolling updates progress as containers start, and as their pauses
+	// elapse, so the policy is also re-evaluated on container changes and on
+	// a timer.
 	loopLog := util.NewEventTimer("Minion-Update")
-	for range conn.Trigger(db.MinionTable).C {
+	policy := newPolicy()
+	for range conn.TriggerTick(30, db.MinionTable, db.ContainerTable).C {
 		loopLog.LogStart()
 		conn.Transact(func(view db.Database) error {
 			minion, err := view.MinionSelf()
@@ -44,7 +54,7 @@ func Run() {
 				return err
 			}
 
-			updatePolicy(view, minion.Role, minion.Spec)
+			policy.update(view, minion.Role, minion.Spec)
 			return nil
 		})
 		loopLog.LogEnd()
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"container/heap"
+	"time"
 
 	"github.com/NetSys/quilt/db"
 	log "github.com/Sirupsen/logrus"
@@ -17,8 +18,20 @@ type context struct {
 	constraints []db.Placement
 	unassigned  []*db.Container
 	changed     []*db.Container
+
+	// For each label, the number of containers implementing it, and the number
+	// of those that have an IP address and are running, or are jobs that have
+	// succeeded.
+	labelTotal map[string]int
+	labelReady map[string]int
+
+	// For each label, the number of its job containers that are placed and have
+	// yet to exit.
+	labelJobs map[string]int
 }
 
+var timeNow = time.Now
+
 func runMaster(conn db.Conn) {
 	conn.Transact(func(view db.Database) error {
 		if view.EtcdLeader() {
@@ -50,6 +63,10 @@ func cleanupPlacements(ctx *context) {
 				continue
 			}
 			dbc.Minion = ""
+			if dbc.IsJob() {
+				dbc.Job.State = db.JobPending
+				ctx.countJob(dbc, -1)
+			}
 			m.containers = append(m.containers[:i], m.containers[i+1:]...)
 			ctx.unassigned = append(ctx.unassigned, dbc)
 			ctx.changed = append(ctx.changed, dbc)
@@ -63,10 +80,23 @@ func placeUnassigned(ctx *context) {
 
 Outer:
 	for _, dbc := range ctx.unassigned {
+		if !ctx.dependenciesReady(dbc) {
+			log.WithField("container", dbc).Debug(
+				"Waiting on dependencies to place container.")
+			continue
+		}
+
+		if !ctx.jobSlotFree(dbc) {
+			log.WithField("container", dbc).Debug(
+				"Waiting on other job containers to place container.")
+			continue
+		}
+
 		for i, minion := range minions {
 			if validPlacement(ctx.constraints, *minion, dbc) {
 				dbc.Minion = minion.PrivateIP
 				ctx.changed = append(ctx.changed, dbc)
+				ctx.countJob(dbc, 1)
 				minion.containers = append(minion.containers, dbc)
 				heap.Fix(&minions, i)
 				log.WithField("container", dbc).Info("Placed container.")
@@ -78,6 +108,72 @@ Outer:
 	}
 }
 
+// dependenciesReady returns false if a label that `dbc` depends on has no ready
+// containers, or, for dependencies that wait until healthy, any unready ones.  A
+// label without containers has nothing to wait on, so it's satisfied.
+func (ctx context) dependenciesReady(dbc *db.Container) bool {
+	for _, dep := range dbc.Dependencies {
+		ready, total := ctx.labelReady[dep.Label], ctx.labelTotal[dep.Label]
+		if total == 0 {
+			continue
+		}
+
+		if ready == 0 || dep.UntilHealthy && ready < total {
+			return false
+		}
+	}
+	return true
+}
+
+// jobSlotFree returns false if placing `dbc` would run more containers of its job at
+// once than the job's parallelism allows.
+func (ctx context) jobSlotFree(dbc *db.Container) bool {
+	if !dbc.IsJob() {
+		return true
+	}
+
+	for _, label := range dbc.Labels {
+		if ctx.labelJobs[label] >= dbc.Job.Parallelism {
+			return false
+		}
+	}
+	return true
+}
+
+func (ctx *context) countJob(dbc *db.Container, delta int) {
+	if dbc.IsJob() {
+		for _, label := range dbc.Labels {
+			ctx.labelJobs[label] += delta
+		}
+	}
+}
+
+// retryJob resets a failed job container so that it's placed again, once it has
+// waited out its backoff.  It returns false if the container shouldn't be retried
+// yet, or ever.
+func retryJob(dbc *db.Container) bool {
+	job := dbc.Job
+	if job.State != db.JobFailed || job.Retries >= job.BackoffLimit {
+		return false
+	}
+
+	backoff := time.Duration(job.Backoff) * time.Second << uint(job.Retries)
+	if timeNow().Before(job.Finished.Add(backoff)) {
+		return false
+	}
+
+	dbc.Job = db.Job{
+		Parallelism:  job.Parallelism,
+		BackoffLimit: job.BackoffLimit,
+		Backoff:      job.Backoff,
+		State:        db.JobPending,
+		Retries:      job.Retries + 1,
+	}
+	dbc.Minion = ""
+	dbc.DockerID = ""
+	return true
+}
+
 func validPlacement(constraints []db.Placement, m minion, dbc *db.Container) bool {
 	cLabels := map[string]struct{}{}
 	for _, label := range dbc.Labels {
@@ -149,10 +245,15 @@ func makeContext(minions []db.Minion, constraints []db.Placement,
 
 	ctx := context{}
 	ctx.constraints = constraints
+	ctx.labelTotal = map[string]int{}
+	ctx.labelReady = map[string]int{}
+	ctx.labelJobs = map[string]int{}
 
+	// Containers on draining minions are placed again elsewhere, before their
+	// cloud provider reclaims them.
 	ipMinion := map[string]*minion{}
 	for _, dbm := range minions {
-		if dbm.Role != db.Worker || dbm.PrivateIP == "" {
+		if dbm.Role != db.Worker || dbm.PrivateIP == "" || dbm.Draining {
 			continue
 		}
 
@@ -163,9 +264,30 @@ func makeContext(minions []db.Minion, constraints []db.Placement,
 
 	for i := range containers {
 		dbc := &containers[i]
+		for _, label := range dbc.Labels {
+			ctx.labelTotal[label]++
+			if dbc.Ready() ||
+				dbc.IsJob() && dbc.Job.State == db.JobSucceeded {
+				ctx.labelReady[label]++
+			}
+		}
+
+		if retryJob(dbc) {
+			ctx.changed = append(ctx.changed, dbc)
+		}
+
+		// Job containers that have exited no longer take up space on their
+		// minion, and must not be placed again.
+		if dbc.IsJob() && dbc.Job.Done() {
+			continue
+		}
+
 		minion := ipMinion[dbc.Minion]
 		if minion == nil && dbc.Minion != "" {
 			dbc.Minion = ""
+			if dbc.IsJob() {
+				dbc.Job.State = db.JobPending
+			}
 			ctx.changed = append(ctx.changed, dbc)
 		}
 
@@ -174,6 +296,7 @@ func makeContext(minions []db.Minion, constraints []db.Placement,
 			continue
 		}
 
+		ctx.countJob(dbc, 1)
 		minion.containers = append(minion.containers, dbc)
 	}
 
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/NetSys/quilt/db"
 	"github.com/davecgh/go-spew/spew"
@@ -47,6 +48,29 @@ func TestPlaceContainers(t *testing.T) {
 	})
 }
 
+func TestPlaceDraining(t *testing.T) {
+	t.Parallel()
+
+	containers := []db.Container{
+		{ID: 1, Minion: "1"},
+		{ID: 2, Minion: "2"},
+	}
+	minions := []db.Minion{
+		{PrivateIP: "1", Role: db.Worker, Draining: true},
+		{PrivateIP: "2", Role: db.Worker},
+	}
+
+	// Containers are moved off of draining minions, and never placed on them.
+	ctx := makeContext(minions, nil, containers)
+	placeUnassigned(ctx)
+	for _, dbc := range containers {
+		if dbc.Minion != "2" {
+			t.Error(spew.Sprintf("container placed on draining minion: %v",
+				dbc))
+		}
+	}
+}
+
 func TestCleanup(t *testing.T) {
 	t.Parallel()
 
@@ -579,3 +603,158 @@ func TestValidPlacementMachine(t *testing.T) {
 func (m minion) String() string {
 	return spew.Sprintf("(%s Containers: %s)", m.Minion, m.containers)
 }
+
+func TestValidPlacementStitchID(t *testing.T) {
+	t.Parallel()
+
+	dbc := &db.Container{ID: 1, StitchID: 5, Image: "new"}
+
+	m := minion{}
+	m.containers = []*db.Container{{ID: 2, StitchID: 4, Image: "old"}}
+	if !validPlacement(nil, m, dbc) {
+		t.Error("Expected container to be placeable on minion.")
+	}
+
+	// The old and new versions of a container being updated may share a minion,
+	// so that a surge can be placed on a single worker.
+	m.containers = append(m.containers, &db.Container{
+		ID: 3, StitchID: 5, Image: "old"})
+	if !validPlacement(nil, m, dbc) {
+		t.Error("Expected two versions of a container to share a minion.")
+	}
+}
+
+func TestPlaceDependencies(t *testing.T) {
+	t.Parallel()
+
+	minions := []db.Minion{{PrivateIP: "1", Role: db.Worker}}
+	containers := []db.Container{
+		{ID: 1, Labels: []string{"mongo"}},
+		{ID: 2, Labels: []string{"mongo"}},
+		{ID: 3, Labels: []string{"app"},
+			Dependencies: []db.Dependency{{Label: "mongo"}}},
+		{ID: 4, Labels: []string{"web"},
+			Dependencies: []db.Dependency{
+				{Label: "mongo", UntilHealthy: true}}},
+
+		// A label without containers has nothing to wait on.
+		{ID: 5, Labels: []string{"admin"},
+			Dependencies: []db.Dependency{{Label: "empty"}}},
+	}
+
+	placed := func() []int {
+		ctx := makeContext(minions, nil, containers)
+		placeUnassigned(ctx)
+
+		var ids []int
+		for _, dbc := range ctx.changed {
+			ids = append(ids, dbc.ID)
+		}
+		return ids
+	}
+
+	if ids := placed(); !eq(ids, []int{1, 2, 5}) {
+		t.Errorf("Expected only the dependencies to be placed, got %v", ids)
+	}
+
+	containers[0].IP = "10.0.0.1"
+	containers[0].DockerID = "abc"
+	if ids := placed(); !eq(ids, []int{3}) {
+		t.Errorf("Expected app to be placed, got %v", ids)
+	}
+
+	containers[1].IP = "10.0.0.2"
+	containers[1].DockerID = "def"
+	if ids := placed(); !eq(ids, []int{4}) {
+		t.Errorf("Expected web to be placed, got %v", ids)
+	}
+}
+
+func TestPlaceAfterJob(t *testing.T) {
+	t.Parallel()
+
+	minions := []db.Minion{{PrivateIP: "1", Role: db.Worker}}
+	containers := []db.Container{
+		{ID: 1, Labels: []string{"migrate"}, Minion: "1",
+			Job: db.Job{Parallelism: 1, State: db.JobRunning}},
+		{ID: 2, Labels: []string{"app"},
+			Dependencies: []db.Dependency{
+				{Label: "migrate", UntilHealthy: true}}},
+	}
+
+	ctx := makeContext(minions, nil, containers)
+	placeUnassigned(ctx)
+	if len(ctx.changed) != 0 {
+		t.Error("Expected app to wait on the job")
+	}
+
+	// The job's container is removed once it exits, but it still counts as
+	// ready.
+	containers[0].Minion = ""
+	containers[0].Job.State = db.JobSucceeded
+	ctx = makeContext(minions, nil, containers)
+	placeUnassigned(ctx)
+	if len(ctx.changed) != 1 || ctx.changed[0].ID != 2 {
+		t.Error(spew.Sprintf("Expected app to be placed, got %v", ctx.changed))
+	}
+}
+
+func TestPlaceJobs(t *testing.T) {
+	minions := []db.Minion{{PrivateIP: "1", Role: db.Worker}}
+	job := db.Job{Parallelism: 2, BackoffLimit: 1, Backoff: 10}
+	containers := []db.Container{
+		{ID: 1, Labels: []string{"job"}, Job: job},
+		{ID: 2, Labels: []string{"job"}, Job: job},
+		{ID: 3, Labels: []string{"job"}, Job: job},
+	}
+
+	placed := func() []int {
+		ctx := makeContext(minions, nil, containers)
+		placeUnassigned(ctx)
+
+		var ids []int
+		for _, dbc := range ctx.changed {
+			ids = append(ids, dbc.ID)
+		}
+		return ids
+	}
+
+	if ids := placed(); !eq(ids, []int{1, 2}) {
+		t.Errorf("Expected two containers to be placed, got %v", ids)
+	}
+
+	// The succeeded container shouldn't be placed again, and makes room for the
+	// remaining one.
+	containers[0].Minion = ""
+	containers[0].Job.State = db.JobSucceeded
+	if ids := placed(); !eq(ids, []int{3}) {
+		t.Errorf("Expected the last container to be placed, got %v", ids)
+	}
+
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	containers[1].Job.State = db.JobFailed
+	containers[1].Job.ExitCode = 1
+	containers[1].Job.Finished = now.Add(-5 * time.Second)
+	if ids := placed(); len(ids) != 0 {
+		t.Errorf("Expected the failed container to back off, got %v", ids)
+	}
+
+	containers[1].Job.Finished = now.Add(-10 * time.Second)
+	placed()
+
+	expJob := db.Job{Parallelism: 2, BackoffLimit: 1, Backoff: 10,
+		State: db.JobPending, Retries: 1}
+	if containers[1].Job != expJob || containers[1].Minion != "1" {
+		t.Error(spew.Sprintf("Unexpected retried container: %v", containers[1]))
+	}
+
+	// The retry limit has been reached.
+	containers[1].Job.State = db.JobFailed
+	containers[1].Job.Finished = now.Add(-time.Hour)
+	if ids := placed(); len(ids) != 0 {
+		t.Errorf("Expected no more retries, got %v", ids)
+	}
+}
@@ -30,16 +30,26 @@ func runWorker(conn db.Conn, dk docker.Client, myIP string) {
 			return
 		}
 
+		exited, err := dk.ListExited(filter)
+		if err != nil {
+			log.WithError(err).Warning("Failed to list exited containers.")
+			return
+		}
+
 		conn.Transact(func(view db.Database) error {
 			dbcs := view.SelectFromContainer(func(dbc db.Container) bool {
 				return dbc.Minion == myIP
 			})
 
-			var changed []db.Container
+			var changed, jobChanged []db.Container
+			var toRemove []interface{}
+			dbcs, jobChanged, toRemove = syncJobs(dbcs, exited)
 			changed, toBoot, toKill = syncWorker(dbcs, dkcs)
-			for _, dbc := range changed {
+			toKill = append(toKill, toRemove...)
+			for _, dbc := range append(jobChanged, changed...) {
 				view.Commit(dbc)
 			}
+			toBoot = filterDependencies(toBoot, view.SelectFromLabel(nil))
 
 			// XXX: We do the actual booting and destruction in the
 			// transaction so that we can prevent the network from running
@@ -71,9 +81,13 @@ func syncWorker(dbcs []db.Container, dkcs []docker.Container) (changed []db.Cont
 		dbc := pair.L.(db.Container)
 		dkc := pair.R.(docker.Container)
 
-		if dbc.DockerID != dkc.ID {
+		if dbc.DockerID != dkc.ID ||
+			dbc.IsJob() && dbc.Job.State != db.JobRunning {
 			dbc.DockerID = dkc.ID
 			dbc.Pid = dkc.Pid
+			if dbc.IsJob() {
+				dbc.Job.State = db.JobRunning
+			}
 			changed = append(changed, dbc)
 		}
 	}
@@ -81,6 +95,92 @@ func syncWorker(dbcs []db.Container, dkcs []docker.Container) (changed []db.Cont
 	return changed, toBoot, toKill
 }
 
+// syncJobs records the exit codes of the job containers in `exited`, and returns the
+// containers that should still be running.  Job containers that have already exited
+// are left out so they aren't restarted.  The exited Docker containers of jobs are
+// returned to be removed, but those of other containers are kept so that crashes
+// can be debugged.
+func syncJobs(dbcs []db.Container, exited []docker.Container) (live,
+	changed []db.Container, toRemove []interface{}) {
+
+	var jobs []db.Container
+	for _, dbc := range dbcs {
+		if dbc.IsJob() {
+			jobs = append(jobs, dbc)
+		} else {
+			live = append(live, dbc)
+		}
+	}
+
+	pairs, unpaired, _ := join.Join(jobs, exited, syncJoinScore)
+	for _, i := range unpaired {
+		if dbc := i.(db.Container); !dbc.Job.Done() {
+			live = append(live, dbc)
+		}
+	}
+
+	for _, pair := range pairs {
+		dbc := pair.L.(db.Container)
+		dkc := pair.R.(docker.Container)
+		toRemove = append(toRemove, dkc)
+
+		// The container was removed before, but it may have failed.
+		if dbc.Job.Done() {
+			continue
+		}
+
+		dbc.Job.State = db.JobSucceeded
+		if dkc.ExitCode != 0 {
+			dbc.Job.State = db.JobFailed
+		}
+		dbc.Job.ExitCode = dkc.ExitCode
+		dbc.DockerID = ""
+		dbc.Pid = 0
+		changed = append(changed, dbc)
+		log.WithField("container", dbc).Info("Job container exited.")
+	}
+
+	return live, changed, toRemove
+}
+
+// filterDependencies removes the containers whose dependencies don't yet have IP
+// addresses from `toBoot`.  They'll be booted on a later run, once the labels they
+// depend on are updated.  As on the master, a label without containers has nothing
+// to wait on.
+func filterDependencies(toBoot []interface{}, labels []db.Label) []interface{} {
+	labelIPs := map[string][]string{}
+	for _, label := range labels {
+		labelIPs[label.Label] = label.ContainerIPs
+	}
+
+	var ready []interface{}
+Outer:
+	for _, intf := range toBoot {
+		dbc := intf.(db.Container)
+		for _, dep := range dbc.Dependencies {
+			if len(labelIPs[dep.Label]) == 0 {
+				continue
+			}
+
+			var withIP int
+			for _, ip := range labelIPs[dep.Label] {
+				if ip != "" {
+					withIP++
+				}
+			}
+
+			if withIP == 0 ||
+				dep.UntilHealthy && withIP < len(labelIPs[dep.Label]) {
+				log.WithField("container", dbc).Debug(
+					"Waiting on dependencies to start container.")
+				continue Outer
+			}
+		}
+		ready = append(ready, dbc)
+	}
+	return ready
+}
+
 func doContainers(dk docker.Client, containers []interface{},
 	do func(docker.Client, chan interface{})) {
 
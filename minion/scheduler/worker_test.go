@@ -210,3 +210,92 @@ func TestSyncJoinScore(t *testing.T) {
 func expLog(msg string, got, exp interface{}) string {
 	return spew.Sprintf("%s\nGot: %s\nExp: %s\n", msg, got, exp)
 }
+
+func TestFilterDependencies(t *testing.T) {
+	t.Parallel()
+
+	app := db.Container{ID: 1,
+		Dependencies: []db.Dependency{{Label: "mongo"}}}
+	web := db.Container{ID: 2,
+		Dependencies: []db.Dependency{{Label: "mongo", UntilHealthy: true}}}
+	toBoot := []interface{}{app, web}
+
+	labels := []db.Label{{Label: "mongo", ContainerIPs: []string{"", ""}}}
+	if ready := filterDependencies(toBoot, labels); len(ready) != 0 {
+		t.Errorf("Expected no containers to boot, got %v", ready)
+	}
+
+	labels[0].ContainerIPs = []string{"10.0.0.1", ""}
+	ready := filterDependencies(toBoot, labels)
+	if !eq(ready, []interface{}{app}) {
+		t.Errorf("Expected only app to boot, got %v", ready)
+	}
+
+	labels[0].ContainerIPs = []string{"10.0.0.1", "10.0.0.2"}
+	ready = filterDependencies(toBoot, labels)
+	if !eq(ready, toBoot) {
+		t.Errorf("Expected all containers to boot, got %v", ready)
+	}
+
+	// A label without containers has no row, or no IPs, and isn't waited on.
+	empty := db.Container{ID: 3, Dependencies: []db.Dependency{
+		{Label: "empty", UntilHealthy: true}, {Label: "none"}}}
+	labels = []db.Label{{Label: "empty"}}
+	ready = filterDependencies([]interface{}{empty}, labels)
+	if !eq(ready, []interface{}{empty}) {
+		t.Errorf("Expected the container to boot, got %v", ready)
+	}
+}
+
+func TestSyncJobs(t *testing.T) {
+	t.Parallel()
+
+	job := db.Job{Parallelism: 1, State: db.JobRunning}
+	dbcs := []db.Container{
+		{ID: 1, Image: "a", DockerID: "1", Job: job},
+		{ID: 2, Image: "b", DockerID: "2", Job: job},
+		{ID: 3, Image: "c", Job: db.Job{Parallelism: 1, State: db.JobSucceeded}},
+		{ID: 4, Image: "d"},
+	}
+	exited := []docker.Container{
+		{ID: "1", Image: "a", ExitCode: 0},
+		{ID: "5", Image: "e", ExitCode: 1},
+		{ID: "6", Image: "c", ExitCode: 0},
+	}
+
+	live, changed, toRemove := syncJobs(dbcs, exited)
+	if exp := []db.Container{dbcs[3], dbcs[1]}; !eq(live, exp) {
+		t.Error(expLog("Unexpected live containers", live, exp))
+	}
+
+	expChanged := dbcs[0]
+	expChanged.DockerID = ""
+	expChanged.Job.State = db.JobSucceeded
+	if exp := []db.Container{expChanged}; !eq(changed, exp) {
+		t.Error(expLog("Unexpected changed containers", changed, exp))
+	}
+
+	// Only the exited containers of jobs are removed.
+	if exp := []interface{}{exited[0], exited[2]}; !eq(toRemove, exp) {
+		t.Error(expLog("Unexpected removed containers", toRemove, exp))
+	}
+
+	// Running job containers are marked as such.
+	md, dk := docker.NewMock()
+	dbcs = []db.Container{{ID: 1, Image: "a",
+		Job: db.Job{Parallelism: 1, State: db.JobPending}}}
+	runSync(dk, dbcs, nil)
+	dkcs, _ := dk.List(nil)
+	changed = runSync(dk, dbcs, dkcs)
+	if len(changed) != 1 || changed[0].Job.State != db.JobRunning {
+		t.Error(spew.Sprintf("Unexpected changed containers: %v", changed))
+	}
+
+	md.ExitContainer(dkcs[0].ID, 2)
+	exited, _ = dk.ListExited(nil)
+	_, changed, _ = syncJobs(changed, exited)
+	if len(changed) != 1 || changed[0].Job.State != db.JobFailed ||
+		changed[0].Job.ExitCode != 2 {
+		t.Error(spew.Sprintf("Unexpected changed containers: %v", changed))
+	}
+}
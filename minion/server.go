@@ -50,6 +50,7 @@ func (s server) GetMinionConfig(cts context.Context,
 		cfg.Provider = m.Provider
 		cfg.Size = m.Size
 		cfg.Region = m.Region
+		cfg.Draining = m.Draining
 	} else {
 		cfg.Role = db.RoleToPB(db.None)
 	}
@@ -72,6 +73,7 @@ func (s server) SetMinionConfig(ctx context.Context,
 		minion.Provider = msg.Provider
 		minion.Size = msg.Size
 		minion.Region = msg.Region
+		minion.Draining = msg.Draining
 		minion.Self = true
 		view.Commit(minion)
 
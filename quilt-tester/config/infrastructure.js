@@ -1,12 +1,18 @@
 // We will have three worker machines.
 var nWorker = 3;
 
+// The tester overrides the provider at the end of this file if $PROVIDER is set.
+var provider = "Amazon";
+
 var deployMachines = function(deployment) {
-    var baseMachine = new Machine({
-        provider: "Amazon",
-        region: "us-west-1",
+    var opts = {
+        provider: provider,
         sshKeys: ["ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCxMuzNUdKJREFgUkSpD0OPjtgDtbDvHQLDxgqnTrZpSvTw5r8XDd+AFS6eVibBfYv1u+geNF3IEkpOklDlII37DzhW7wzlRB0SmjUtODxL5hf9hKoScDpvXG3RBD6PBCyOHA5IJBTqPGpIZUMmOlXDYZA1KLaKQs6GByg7QMp6z1/gLCgcQygTDdiTfESgVMwR1uSQ5MRjBaL7vcVfrKExyCLxito77lpWFMARGG9W1wTWnmcPrzYR7cLzhzUClakazNJmfso/b4Y5m+pNH2dLZdJ/eieLtSEsBDSP8X0GYpmTyFabZycSXZFYP+wBkrUTmgIh9LQ56U1lvA4UlxHJ"],
-    });
+    };
+    if (provider === "Amazon") {
+        opts.region = "us-west-1";
+    }
+    var baseMachine = new Machine(opts);
 
     deployment.deploy(baseMachine.asMaster())
     deployment.deploy(baseMachine.asWorker().replicate(nWorker + 1));
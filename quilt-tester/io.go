@@ -185,3 +185,15 @@ func updateNamespace(specfile string, namespace string) error {
 
 	return overwrite(specfile, updatedSpec)
 }
+
+// updateProvider sets the provider of the machines deployed by the infrastructure
+// module at `specfile`.
+func updateProvider(specfile string, provider string) error {
+	specContents, err := fileContents(specfile)
+	if err != nil {
+		return err
+	}
+
+	updatedSpec := specContents + fmt.Sprintf("; provider = %q;", provider)
+	return overwrite(specfile, updatedSpec)
+}
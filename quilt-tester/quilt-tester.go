@@ -20,6 +20,8 @@ const (
 	quiltPath          = "/.quilt"
 	infrastructureSpec = quiltPath + "/github.com/NetSys/quilt/quilt-tester/" +
 		"config/infrastructure-runner.js"
+	infrastructureModule = quiltPath + "/github.com/NetSys/quilt/quilt-tester/" +
+		"config/infrastructure.js"
 	slackEndpoint = "https://hooks.slack.com/services/T04Q3TL41/B0M25TWP5/" +
 		"soKJeP5HbWcjkUJzEHh7ylYm"
 	testerImport = "github.com/NetSys/quilt"
@@ -183,6 +185,17 @@ func (t *tester) setup() error {
 		return err
 	}
 
+	// The machines are booted on Amazon unless another provider is given.
+	if provider := os.Getenv("PROVIDER"); provider != "" {
+		l.infoln(fmt.Sprintf("Booting the machines on %s.", provider))
+		if err := updateProvider(infrastructureModule, provider); err != nil {
+			l.infoln(fmt.Sprintf("Error updating provider for %s.",
+				infrastructureModule))
+			l.errorln(err.Error())
+			return err
+		}
+	}
+
 	// Setup infrastructure.
 	l.infoln("Booting the machines the test suites will run on, and waiting " +
 		"for them to connect back.")
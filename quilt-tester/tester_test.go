@@ -76,6 +76,28 @@ func TestURLGeneration(t *testing.T) {
 	}
 }
 
+func TestUpdateProvider(t *testing.T) {
+	appFs = afero.NewMemMapFs()
+
+	specPath := "/infrastructure.js"
+	err := overwrite(specPath, `var provider = "Amazon";`)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err.Error())
+	}
+	updateProvider(specPath, "Local")
+
+	res, err := fileContents(specPath)
+	exp := `var provider = "Amazon";; provider = "Local";`
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err.Error())
+		return
+	}
+	if res != exp {
+		t.Errorf("Provider didn't properly update, expected %s, got %s",
+			exp, res)
+	}
+}
+
 func TestUpdateNamespace(t *testing.T) {
 	appFs = afero.NewMemMapFs()
 
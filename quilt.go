@@ -22,11 +22,13 @@ func main() {
 		fmt.Println("Usage: quilt " +
 			"[-log-level=<level> | -l=<level>] [-H=<listen_address>] " +
 			"[log-file=<log_output_file>] " +
-			"[daemon | inspect <stitch> | run <stitch> | minion | " +
+			"[daemon | inspect <stitch> | lint <stitch> | " +
+			"run <stitch> | diff <stitch> | history | " +
+			"rollback <revision> | minion | " +
 			"stop <namespace> | get <import_path> | " +
-			"machines | containers | ssh <machine> | " +
+			"machines | containers | status | ssh <machine> | " +
 			"exec <container> <command>]" +
-			"logs <container>")
+			"logs <container> | catalog update")
 		fmt.Println("\nWhen provided a stitch, quilt takes responsibility\n" +
 			"for deploying it as specified.  Alternatively, quilt may be\n" +
 			"instructed to stop all deployments in a given namespace,\n" +
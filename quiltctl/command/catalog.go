@@ -0,0 +1,164 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/catalog"
+	"github.com/NetSys/quilt/cluster/provider/digitalocean"
+	"github.com/NetSys/quilt/db"
+)
+
+// Catalog contains the options for updating the catalog of machine sizes.
+type Catalog struct {
+	path    string
+	regions string
+
+	// The sources of the providers' sizes, given the regions to list.
+	getSources func(regions []string) []catalog.Source
+}
+
+// NewCatalogCommand creates a new Catalog command.
+func NewCatalogCommand() *Catalog {
+	return &Catalog{getSources: apiSources}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (cCmd *Catalog) InstallFlags(flags *flag.FlagSet) {
+	flags.StringVar(&cCmd.path, "path", catalog.DefaultPath,
+		"the catalog file to update")
+	flags.StringVar(&cCmd.regions, "regions", "",
+		"comma-separated Amazon regions to list (default all)")
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt catalog [-path=<path>] " +
+			"[-regions=<regions>] update")
+		fmt.Println("`catalog update` fetches the machine sizes, and their " +
+			"prices, from the Amazon and DigitalOcean APIs, and saves " +
+			"them to the catalog used to choose machine sizes.  Other " +
+			"providers, and with -regions, Amazon's other regions, keep " +
+			"their current sizes.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the catalog command.
+func (cCmd *Catalog) Parse(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no catalog command specified")
+	}
+
+	if args[0] != "update" {
+		return fmt.Errorf("unknown catalog command: %s", args[0])
+	}
+	return nil
+}
+
+// Run updates the catalog.  Providers whose sizes can't be fetched keep their
+// current ones, but a non-zero status is returned.
+func (cCmd *Catalog) Run() int {
+	current, err := catalog.NewFile(cCmd.path).Catalog()
+	if err != nil {
+		log.WithError(err).Warn("Failed to read the current catalog, " +
+			"starting from the bundled one instead.")
+		if current, err = catalog.Bundled.Catalog(); err != nil {
+			log.WithError(err).Error("Failed to read the bundled catalog.")
+			return 1
+		}
+	}
+
+	var regions []string
+	if cCmd.regions != "" {
+		regions = strings.Split(cCmd.regions, ",")
+	}
+
+	status := 0
+	for _, source := range cCmd.getSources(regions) {
+		fetched, err := source.Catalog()
+		if err != nil {
+			log.WithError(err).Error("Failed to fetch machine sizes.")
+			status = 1
+			continue
+		}
+
+		for p, descriptions := range fetched {
+			// Only the given regions of Amazon were listed, so its sizes in
+			// the other regions are kept.
+			if p == db.Amazon && len(regions) > 0 {
+				descriptions = mergeRegions(current[p], descriptions,
+					regions)
+			}
+			current[p] = descriptions
+		}
+	}
+
+	if err := catalog.Save(cCmd.path, current); err != nil {
+		log.WithError(err).Error("Failed to save the catalog.")
+		return 1
+	}
+
+	fmt.Print(catalogSummary(current))
+	return status
+}
+
+// mergeRegions returns the descriptions in `fetched`, along with those in `current`
+// that aren't in one of the fetched `regions`.
+func mergeRegions(current, fetched []catalog.Description,
+	regions []string) []catalog.Description {
+
+	fetchedRegions := map[string]bool{}
+	for _, region := range regions {
+		fetchedRegions[region] = true
+	}
+
+	var merged []catalog.Description
+	for _, d := range current {
+		if !fetchedRegions[d.Region] {
+			merged = append(merged, d)
+		}
+	}
+	return append(merged, fetched...)
+}
+
+// apiSources returns the Sources that list sizes through the providers' APIs.
+// DigitalOcean is skipped if there's no API token.
+func apiSources(regions []string) []catalog.Source {
+	sources := []catalog.Source{catalog.Amazon{Regions: regions}}
+
+	keyfile := filepath.Join(os.Getenv("HOME"), ".digitalocean", "key")
+	key, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		log.WithError(err).Warn("Failed to read the DigitalOcean API token, " +
+			"so its sizes won't be updated.")
+		return sources
+	}
+
+	client := digitalocean.New(strings.TrimSpace(string(key)))
+	return append(sources, catalog.DigitalOcean{Client: client})
+}
+
+func catalogSummary(c catalog.Catalog) string {
+	var providers []string
+	for p := range c {
+		providers = append(providers, string(p))
+	}
+	sort.Strings(providers)
+
+	var str string
+	for _, p := range providers {
+		sizes := map[string]struct{}{}
+		for _, d := range c[db.Provider(p)] {
+			sizes[d.Size] = struct{}{}
+		}
+		str += fmt.Sprintf("%s sizes: %d\n", p, len(sizes))
+	}
+	return str
+}
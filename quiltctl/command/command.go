@@ -3,6 +3,8 @@ package command
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	log "github.com/Sirupsen/logrus"
 
@@ -37,17 +39,29 @@ var getClient = func(host string) (client.Client, error) {
 	return c, nil
 }
 
-// Get a client connected to the lead minion.
-func getLeaderClient(localClient client.Client) (client.Client, error) {
+// Get a client connected to the lead minion of `namespace`.  If `namespace` is
+// empty, the machines must all be in the same namespace, as each namespace has its
+// own leader.
+func getLeaderClient(localClient client.Client, namespace string) (client.Client,
+	error) {
+
 	machines, err := localClient.QueryMachines()
 	if err != nil {
 		return nil, fmt.Errorf("unable to query machines: %s", err.Error())
 	}
 
+	if namespace == "" {
+		if namespaces := machineNamespaces(machines); len(namespaces) > 1 {
+			return nil, fmt.Errorf("machines are running in several "+
+				"namespaces (%s), so one must be chosen with -namespace",
+				strings.Join(namespaces, ", "))
+		}
+	}
+
 	// Try to figure out the lead minion's IP by asking each of the machines
 	// tracked by the local daemon.
 	for _, m := range machines {
-		if m.PublicIP == "" {
+		if m.PublicIP == "" || (namespace != "" && m.Namespace != namespace) {
 			continue
 		}
 
@@ -61,6 +75,20 @@ func getLeaderClient(localClient client.Client) (client.Client, error) {
 	return nil, errors.New("no leader found")
 }
 
+// machineNamespaces returns the sorted namespaces of the `machines` that have booted.
+func machineNamespaces(machines []db.Machine) []string {
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, m := range machines {
+		if m.PublicIP != "" && !seen[m.Namespace] {
+			seen[m.Namespace] = true
+			namespaces = append(namespaces, m.Namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
 // Get the public IP of the lead minion by querying the remote machine's etcd
 // table for the private IP, and then searching for the public IP in the local
 // daemon.
@@ -99,14 +127,15 @@ func getPublicIP(c client.Client, privateIP string) (string, error) {
 	return "", fmt.Errorf("no machine with private IP %s", privateIP)
 }
 
-// Get a client connected to the local daemon and the daemon on the lead minion.
-func getClients(host string) (client.Client, client.Client, error) {
+// Get a client connected to the local daemon and the daemon on the lead minion of
+// `namespace`.
+func getClients(host, namespace string) (client.Client, client.Client, error) {
 	localClient, err := getClient(host)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	leaderClient, err := getLeaderClient(localClient)
+	leaderClient, err := getLeaderClient(localClient, namespace)
 	if err != nil {
 		localClient.Close()
 		return nil, nil, err
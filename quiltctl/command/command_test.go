@@ -3,13 +3,19 @@ package command
 import (
 	"errors"
 	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/NetSys/quilt/api"
 	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/catalog"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/quiltctl/testutils"
+	"github.com/NetSys/quilt/stitch"
 )
 
 func TestMachineFlags(t *testing.T) {
@@ -18,7 +24,7 @@ func TestMachineFlags(t *testing.T) {
 	expHost := "IP"
 
 	machineCmd := NewMachineCommand()
-	err := parseHelper(machineCmd, []string{"-H", expHost})
+	err := parseHelper(machineCmd, []string{"-H", expHost, "-namespace", "ns"})
 
 	if err != nil {
 		t.Errorf("Unexpected error when parsing machine args: %s", err.Error())
@@ -29,6 +35,11 @@ func TestMachineFlags(t *testing.T) {
 		t.Errorf("Expected machine command to parse arg %s, but got %s",
 			expHost, machineCmd.host)
 	}
+
+	if machineCmd.namespace != "ns" {
+		t.Errorf("Expected machine command to parse namespace ns, but got %s",
+			machineCmd.namespace)
+	}
 }
 
 func TestMachineOutput(t *testing.T) {
@@ -41,12 +52,22 @@ func TestMachineOutput(t *testing.T) {
 		Region:   "us-west-1",
 		Size:     "m4.large",
 		PublicIP: "8.8.8.8",
-	}})
+	}}, "")
 
 	exp := "Machine-1{Master, Amazon us-west-1 m4.large, PublicIP=8.8.8.8}\n"
 	if res != exp {
 		t.Errorf("\nGot: %s\nExp: %s\n", res, exp)
 	}
+
+	machines := []db.Machine{
+		{ID: 1, Namespace: "prod", Role: db.Master, Provider: "Amazon"},
+		{ID: 2, Namespace: "staging", Role: db.Master, Provider: "Amazon"},
+	}
+	res = machinesStr(machines, "staging")
+	exp = "Machine-2{Master, Amazon  , Namespace=staging}\n"
+	if res != exp {
+		t.Errorf("\nGot: %s\nExp: %s\n", res, exp)
+	}
 }
 
 func TestContainerFlags(t *testing.T) {
@@ -55,7 +76,7 @@ func TestContainerFlags(t *testing.T) {
 	expHost := "IP"
 
 	containerCmd := NewContainerCommand()
-	err := parseHelper(containerCmd, []string{"-H", expHost})
+	err := parseHelper(containerCmd, []string{"-H", expHost, "-namespace", "ns"})
 
 	if err != nil {
 		t.Errorf("Unexpected error when parsing container args: %s", err.Error())
@@ -66,6 +87,22 @@ func TestContainerFlags(t *testing.T) {
 		t.Errorf("Expected container command to parse arg %s, but got %s",
 			expHost, containerCmd.host)
 	}
+
+	if containerCmd.namespace != "ns" {
+		t.Errorf("Expected container command to parse namespace ns, but got %s",
+			containerCmd.namespace)
+	}
+
+	statusCmd := NewStatusCommand()
+	if err := parseHelper(statusCmd, []string{"-namespace", "ns"}); err != nil {
+		t.Errorf("Unexpected error when parsing status args: %s", err.Error())
+		return
+	}
+
+	if statusCmd.namespace != "ns" {
+		t.Errorf("Expected status command to parse namespace ns, but got %s",
+			statusCmd.namespace)
+	}
 }
 
 func TestContainerOutput(t *testing.T) {
@@ -78,6 +115,97 @@ func TestContainerOutput(t *testing.T) {
 	}
 }
 
+func TestJobContainerOutput(t *testing.T) {
+	t.Parallel()
+
+	res := containersStr([]db.Container{{ID: 1, Image: "image",
+		Job: db.Job{Parallelism: 1, State: db.JobFailed, ExitCode: 2,
+			Retries: 1}}})
+	exp := "Container-1{run image, Job: failed (exit 2), Retries: 1}\n"
+	if res != exp {
+		t.Errorf("Expected container command to print %s, but got %s.", exp, res)
+	}
+}
+
+func TestStatusOutput(t *testing.T) {
+	t.Parallel()
+
+	res := violationsStr(nil)
+	exp := "All invariants hold.\n"
+	if res != exp {
+		t.Errorf("Expected status command to print %s, but got %s.", exp, res)
+	}
+
+	res = violationsStr([]db.Violation{{ID: 1, Invariant: `reach true "a" "b"`,
+		Counterexample: "container 1 (a) can't reach container 2 (b)"}})
+	exp = "invariant failed: reach true \"a\" \"b\"\n" +
+		"    container 1 (a) can't reach container 2 (b)\n"
+	if res != exp {
+		t.Errorf("Expected status command to print %s, but got %s.", exp, res)
+	}
+}
+
+func TestHistoryOutput(t *testing.T) {
+	t.Parallel()
+
+	res := historyStr(nil)
+	exp := "No specs have been applied.\n"
+	if res != exp {
+		t.Errorf("Expected history command to print %s, but got %s.", exp, res)
+	}
+
+	applied := time.Date(2016, 11, 2, 15, 4, 5, 0, time.UTC)
+	res = historyStr([]db.Revision{
+		{Number: 3, Namespace: "ns", Time: applied.Add(2 * time.Hour)},
+		{Number: 2, Namespace: "other", Time: applied.Add(time.Hour)},
+		{Number: 1, Namespace: "ns", Time: applied, User: "alice"},
+	})
+	exp = "1: ns applied by alice at 2016-11-02 15:04:05 UTC\n" +
+		"2: other applied by unknown user at 2016-11-02 16:04:05 UTC " +
+		"(current)\n" +
+		"3: ns applied by unknown user at 2016-11-02 17:04:05 UTC (current)\n"
+	if res != exp {
+		t.Errorf("Expected history command to print %s, but got %s.", exp, res)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	c := &mockClient{}
+	getClient = func(host string) (client.Client, error) {
+		return c, nil
+	}
+
+	rollbackCmd := NewRollbackCommand()
+	if err := parseHelper(rollbackCmd, []string{"3"}); err != nil {
+		t.Fatalf("Unexpected error when parsing rollback args: %s", err)
+	}
+	if code := rollbackCmd.Run(); code != 0 {
+		t.Errorf("Expected rollback to succeed, but it exited with %d", code)
+	}
+	if c.rollbackArg != 3 || c.rollbackForceArg {
+		t.Errorf("Expected unforced rollback to revision 3, but got %d, %v",
+			c.rollbackArg, c.rollbackForceArg)
+	}
+
+	rollbackCmd = NewRollbackCommand()
+	if err := parseHelper(rollbackCmd, []string{"-force", "2"}); err != nil {
+		t.Fatalf("Unexpected error when parsing rollback args: %s", err)
+	}
+	if code := rollbackCmd.Run(); code != 0 {
+		t.Errorf("Expected rollback to succeed, but it exited with %d", code)
+	}
+	if c.rollbackArg != 2 || !c.rollbackForceArg {
+		t.Errorf("Expected forced rollback to revision 2, but got %d, %v",
+			c.rollbackArg, c.rollbackForceArg)
+	}
+
+	for _, args := range [][]string{{}, {"zero"}, {"0"}} {
+		if err := parseHelper(NewRollbackCommand(), args); err == nil {
+			t.Errorf("Expected an error when parsing rollback args %v", args)
+		}
+	}
+}
+
 func checkGetParsing(t *testing.T, args []string, expImport string, expErr error) {
 	getCmd := &Get{}
 	err := parseHelper(getCmd, args)
@@ -110,6 +238,153 @@ func TestGetFlags(t *testing.T) {
 	checkGetParsing(t, []string{}, "", errors.New("no import specified"))
 }
 
+func TestLintFlags(t *testing.T) {
+	t.Parallel()
+
+	lintCmd := &Lint{}
+	if err := parseHelper(lintCmd, []string{"spec.js"}); err != nil {
+		t.Errorf("Unexpected error when parsing lint args: %s", err.Error())
+	}
+	if lintCmd.stitch != "spec.js" {
+		t.Errorf("Expected lint command to parse arg spec.js, but got %s",
+			lintCmd.stitch)
+	}
+
+	err := parseHelper(&Lint{}, []string{})
+	if err == nil || err.Error() != "no spec specified" {
+		t.Errorf("Expected error \"no spec specified\", but got %v", err)
+	}
+}
+
+func TestLintOutput(t *testing.T) {
+	t.Parallel()
+
+	res := diagnosticsStr([]stitch.Diagnostic{
+		{File: "spec.js", Line: 3, Message: "unknown provider \"Azure\""},
+		{File: "spec.js", Message: "oops"},
+	})
+	exp := "spec.js:3: unknown provider \"Azure\"\nspec.js: oops\n"
+	if res != exp {
+		t.Errorf("Expected lint command to print %s, but got %s.", exp, res)
+	}
+}
+
+func TestCatalogFlags(t *testing.T) {
+	t.Parallel()
+
+	catalogCmd := NewCatalogCommand()
+	err := parseHelper(catalogCmd, []string{"-regions", "us-west-1,eu-west-1",
+		"update"})
+	if err != nil {
+		t.Errorf("Unexpected error when parsing catalog args: %s", err)
+	}
+	if catalogCmd.regions != "us-west-1,eu-west-1" {
+		t.Errorf("Expected regions us-west-1,eu-west-1, but got %s",
+			catalogCmd.regions)
+	}
+
+	err = parseHelper(NewCatalogCommand(), []string{})
+	if err == nil || err.Error() != "no catalog command specified" {
+		t.Errorf("Expected error \"no catalog command specified\", but got %v",
+			err)
+	}
+
+	err = parseHelper(NewCatalogCommand(), []string{"refresh"})
+	if err == nil || err.Error() != "unknown catalog command: refresh" {
+		t.Errorf("Expected an unknown command error, but got %v", err)
+	}
+}
+
+type fakeCatalogSource struct {
+	catalog catalog.Catalog
+	err     error
+}
+
+func (s fakeCatalogSource) Catalog() (catalog.Catalog, error) {
+	return s.catalog, s.err
+}
+
+func TestCatalogUpdate(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "catalog.json")
+	amazon := []catalog.Description{{Size: "m4.large", Region: "us-west-1"}}
+	google := []catalog.Description{{Size: "n1-standard-1"}}
+	err = catalog.Save(path, catalog.Catalog{
+		db.Amazon: {
+			{Size: "m3.medium", Region: "us-west-1"},
+			{Size: "m4.large", Region: "eu-west-1"},
+		},
+		db.Google:       google,
+		db.DigitalOcean: {{Size: "1gb"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var regions []string
+	cmd := &Catalog{path: path, regions: "us-west-1",
+		getSources: func(r []string) []catalog.Source {
+			regions = r
+			return []catalog.Source{
+				fakeCatalogSource{catalog: catalog.Catalog{
+					db.Amazon: amazon}},
+				fakeCatalogSource{err: errors.New("bad token")},
+			}
+		}}
+
+	// The DigitalOcean sizes couldn't be fetched, so the update fails, but the
+	// Amazon sizes are still updated in the fetched region, and kept in the
+	// others.
+	if status := cmd.Run(); status != 1 {
+		t.Errorf("Expected status 1, but got %d", status)
+	}
+
+	if !reflect.DeepEqual(regions, []string{"us-west-1"}) {
+		t.Errorf("Expected regions [us-west-1], but got %v", regions)
+	}
+
+	c, err := catalog.NewFile(path).Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := catalog.Catalog{
+		db.Amazon: {
+			{Size: "m4.large", Region: "eu-west-1"},
+			{Size: "m4.large", Region: "us-west-1"},
+		},
+		db.Google:       google,
+		db.DigitalOcean: {{Size: "1gb"}},
+	}
+	if !reflect.DeepEqual(c, exp) {
+		t.Errorf("Expected catalog %v, but got %v", exp, c)
+	}
+}
+
+func TestCatalogSummary(t *testing.T) {
+	t.Parallel()
+
+	res := catalogSummary(catalog.Catalog{
+		db.DigitalOcean: {{Size: "1gb"}},
+		db.Amazon: {
+			{Size: "m4.large", Region: "us-west-1"},
+			{Size: "m4.large", Region: "eu-west-1"},
+			{Size: "c4.large", Region: "us-west-1"},
+		},
+	})
+	exp := "Amazon sizes: 2\nDigitalOcean sizes: 1\n"
+	if res != exp {
+		t.Errorf("Expected summary %q, but got %q", exp, res)
+	}
+}
+
 func checkStopParsing(t *testing.T, args []string, expNamespace string, expErr error) {
 	stopCmd := NewStopCommand()
 	err := parseHelper(stopCmd, args)
@@ -227,13 +502,30 @@ func TestExecFlags(t *testing.T) {
 		errors.New("must specify a target container and command"))
 	checkExecParsing(t, []string{}, 0, "", "",
 		errors.New("must specify a target container and command"))
+
+	execCmd := NewExecCommand(nil)
+	err := parseHelper(execCmd, []string{"-namespace", "ns", "1", "sh"})
+	if err != nil {
+		t.Errorf("Unexpected error when parsing exec args: %s", err.Error())
+	} else if execCmd.namespace != "ns" {
+		t.Errorf("Expected exec command to parse namespace ns, but got %s",
+			execCmd.namespace)
+	}
 }
 
 type mockClient struct {
-	machineReturn   []db.Machine
-	containerReturn []db.Container
-	etcdReturn      []db.Etcd
-	runStitchArg    string
+	machineReturn    []db.Machine
+	containerReturn  []db.Container
+	etcdReturn       []db.Etcd
+	violationReturn  []db.Violation
+	connectionReturn []db.Connection
+	placementReturn  []db.Placement
+	aclReturn        []db.ACL
+	revisionReturn   []db.Revision
+	runStitchArg     string
+	runForceArg      bool
+	rollbackArg      int
+	rollbackForceArg bool
 }
 
 func (c *mockClient) QueryMachines() ([]db.Machine, error) {
@@ -248,12 +540,39 @@ func (c *mockClient) QueryEtcd() ([]db.Etcd, error) {
 	return c.etcdReturn, nil
 }
 
+func (c *mockClient) QueryViolations() ([]db.Violation, error) {
+	return c.violationReturn, nil
+}
+
+func (c *mockClient) QueryConnections() ([]db.Connection, error) {
+	return c.connectionReturn, nil
+}
+
+func (c *mockClient) QueryPlacements() ([]db.Placement, error) {
+	return c.placementReturn, nil
+}
+
+func (c *mockClient) QueryACLs() ([]db.ACL, error) {
+	return c.aclReturn, nil
+}
+
+func (c *mockClient) QueryRevisions() ([]db.Revision, error) {
+	return c.revisionReturn, nil
+}
+
 func (c *mockClient) Close() error {
 	return nil
 }
 
-func (c *mockClient) RunStitch(stitch string) error {
+func (c *mockClient) RunStitch(stitch string, force bool) error {
 	c.runStitchArg = stitch
+	c.runForceArg = force
+	return nil
+}
+
+func (c *mockClient) Rollback(revision int, force bool) error {
+	c.rollbackArg = revision
+	c.rollbackForceArg = force
 	return nil
 }
 
@@ -325,7 +644,58 @@ func TestGetLeaderClient(t *testing.T) {
 			},
 		},
 	}
-	res, err := getLeaderClient(localClient)
+	res, err := getLeaderClient(localClient, "")
+	if err != nil {
+		t.Errorf("Unexpected error when getting lead minion: %s", err.Error())
+		return
+	}
+
+	if res != passedClient {
+		t.Errorf("Didn't retrieve the proper client for the lead minion: "+
+			"expected %v, got %v", passedClient, res)
+	}
+}
+
+func TestLeaderNamespace(t *testing.T) {
+	passedClient := &mockClient{}
+	getClient = func(host string) (client.Client, error) {
+		switch host {
+		case api.RemoteAddress("prod"):
+			return &mockClient{
+				etcdReturn: []db.Etcd{{LeaderIP: "prod-priv"}},
+			}, nil
+		case api.RemoteAddress("staging"):
+			return &mockClient{
+				etcdReturn: []db.Etcd{{LeaderIP: "staging-priv"}},
+			}, nil
+		case api.RemoteAddress("prod-leader"):
+			return passedClient, nil
+		default:
+			t.Errorf("Unexpected call to getClient with host %s", host)
+			t.Fail()
+		}
+		panic("unreached")
+	}
+
+	localClient := &mockClient{
+		machineReturn: []db.Machine{
+			{Namespace: "staging", PublicIP: "staging",
+				PrivateIP: "staging-priv"},
+			{Namespace: "prod", PublicIP: "prod"},
+			{Namespace: "prod", PublicIP: "prod-leader",
+				PrivateIP: "prod-priv"},
+			{Namespace: "test"},
+		},
+	}
+
+	_, err := getLeaderClient(localClient, "")
+	expErr := "machines are running in several namespaces (prod, staging), " +
+		"so one must be chosen with -namespace"
+	if err == nil || err.Error() != expErr {
+		t.Errorf("Expected error %q, but got %v", expErr, err)
+	}
+
+	res, err := getLeaderClient(localClient, "prod")
 	if err != nil {
 		t.Errorf("Unexpected error when getting lead minion: %s", err.Error())
 		return
@@ -359,7 +729,7 @@ func TestNoLeader(t *testing.T) {
 			},
 		},
 	}
-	_, err := getLeaderClient(localClient)
+	_, err := getLeaderClient(localClient, "")
 	expErr := "no leader found"
 	if err == nil {
 		t.Errorf("Expected an error when the leader IP is not set.")
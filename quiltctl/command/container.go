@@ -1,6 +1,7 @@
 package command
 
 import (
+	"flag"
 	"fmt"
 
 	log "github.com/Sirupsen/logrus"
@@ -10,6 +11,10 @@ import (
 
 // Container contains the options for querying containers.
 type Container struct {
+	// The namespace whose lead minion is queried.  It may be omitted if only one
+	// namespace has machines.
+	namespace string
+
 	*commonFlags
 }
 
@@ -20,6 +25,14 @@ func NewContainerCommand() *Container {
 	}
 }
 
+// InstallFlags sets up parsing for command line flags.
+func (cCmd *Container) InstallFlags(flags *flag.FlagSet) {
+	cCmd.commonFlags.InstallFlags(flags)
+
+	flags.StringVar(&cCmd.namespace, "namespace", "",
+		"show the containers of this namespace")
+}
+
 // Parse parses the command line arguments for the container command.
 func (cCmd *Container) Parse(args []string) error {
 	return nil
@@ -33,7 +46,7 @@ func (cCmd *Container) Run() int {
 		return 1
 	}
 
-	c, err := getLeaderClient(localClient)
+	c, err := getLeaderClient(localClient, cCmd.namespace)
 	localClient.Close()
 	if err != nil {
 		log.WithError(err).Error("Error connecting to leader.")
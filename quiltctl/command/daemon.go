@@ -1,6 +1,8 @@
 package command
 
 import (
+	"flag"
+
 	"github.com/NetSys/quilt/api/server"
 	"github.com/NetSys/quilt/cluster"
 	"github.com/NetSys/quilt/db"
@@ -8,6 +10,10 @@ import (
 
 // Daemon contains the options for running the Quilt daemon.
 type Daemon struct {
+	// The largest fraction of the machines a stitch may terminate without
+	// `quilt run -force`.
+	maxTerminateFraction float64
+
 	*commonFlags
 }
 
@@ -18,6 +24,15 @@ func NewDaemonCommand() *Daemon {
 	}
 }
 
+// InstallFlags sets up parsing for command line flags.
+func (dCmd *Daemon) InstallFlags(flags *flag.FlagSet) {
+	dCmd.commonFlags.InstallFlags(flags)
+
+	flags.Float64Var(&dCmd.maxTerminateFraction, "max-terminate", 0.5,
+		"the largest fraction of the machines a stitch may terminate "+
+			"unless it's run with -force")
+}
+
 // Parse parses the command line arguments for the daemon command.
 func (dCmd *Daemon) Parse(args []string) error {
 	return nil
@@ -26,7 +41,7 @@ func (dCmd *Daemon) Parse(args []string) error {
 // Run starts the daemon.
 func (dCmd *Daemon) Run() int {
 	conn := db.New()
-	go server.Run(conn, dCmd.host)
+	go server.Run(conn, dCmd.host, dCmd.maxTerminateFraction)
 	cluster.Run(conn)
 	return 0
 }
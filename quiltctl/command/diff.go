@@ -0,0 +1,397 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/engine"
+	"github.com/NetSys/quilt/minion"
+	"github.com/NetSys/quilt/stitch"
+)
+
+// Diff contains the options for showing what running a Stitch would change.
+type Diff struct {
+	stitch string
+
+	common *commonFlags
+}
+
+// NewDiffCommand creates a new Diff command instance.
+func NewDiffCommand() *Diff {
+	return &Diff{
+		common: &commonFlags{},
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (dCmd *Diff) InstallFlags(flags *flag.FlagSet) {
+	dCmd.common.InstallFlags(flags)
+
+	flags.StringVar(&dCmd.stitch, "stitch", "", "the stitch to compare")
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt diff [-H=<daemon_host>] " +
+			"[-stitch=<stitch>] <stitch>")
+		fmt.Println("`diff` compiles the provided stitch, and shows the " +
+			"machines, ACLs, containers, connections, and placement rules " +
+			"that running it would add and remove.  Nothing is changed.  " +
+			"Services with an update strategy make these changes in " +
+			"batches, which aren't shown.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the diff command.
+func (dCmd *Diff) Parse(args []string) error {
+	if dCmd.stitch == "" {
+		if len(args) == 0 {
+			return errors.New("no spec specified")
+		}
+		dCmd.stitch = args[0]
+	}
+
+	return nil
+}
+
+// Run shows the changes running the provided Stitch would make.
+func (dCmd *Diff) Run() int {
+	c, err := getClient(dCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	compiled, err := compileStitch(dCmd.stitch)
+	if err != nil {
+		logCompileError(err)
+		return 1
+	}
+
+	return showPlan(c, compiled)
+}
+
+// showPlan prints the changes that running `compiled` would make.
+func showPlan(c client.Client, compiled string) int {
+	spec, err := stitch.New(compiled, stitch.DefaultImportGetter)
+	if err != nil {
+		log.Error("Invalid stitch.")
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	p, err := makePlan(c, spec)
+	if err != nil {
+		log.WithError(err).Error("Unable to compute plan.")
+		return 1
+	}
+
+	fmt.Print(p)
+	return 0
+}
+
+// A plan lists the changes that running a spec would make.  The machines and ACLs
+// are managed by the daemon, while the containers, connections, and placements are
+// managed by the lead minion.
+type plan struct {
+	bootMachines, terminateMachines   []db.Machine
+	addAdmin, removeAdmin             []string
+	addPorts, removePorts             []db.PortRange
+	addContainers, removeContainers   []db.Container
+	addConnections, removeConnections []db.Connection
+	addPlacements, removePlacements   []db.Placement
+
+	// Set if machines are running in the spec's namespace, but its lead minion
+	// couldn't be reached.  The
+	// containers, connections, and placements are then compared against an
+	// empty cluster.
+	noLeader bool
+
+	// The labels with an update strategy whose containers change.  Their
+	// changes are made in batches, which the plan doesn't show.
+	rollingLabels []string
+}
+
+func makePlan(c client.Client, spec stitch.Stitch) (plan, error) {
+	var p plan
+
+	namespace := engine.Namespace(spec)
+	machines, err := c.QueryMachines()
+	if err != nil {
+		return plan{}, err
+	}
+	p.bootMachines, p.terminateMachines = engine.PlanMachines(spec, machines)
+
+	acls, err := c.QueryACLs()
+	if err != nil {
+		return plan{}, err
+	}
+
+	var currACL db.ACL
+	for _, acl := range acls {
+		if acl.Namespace == namespace {
+			currACL = acl
+		}
+	}
+	newACL := engine.PlanACL(spec)
+	p.addAdmin, p.removeAdmin = diffStrings(newACL.Admin, currACL.Admin)
+
+	var newPorts, currPorts []string
+	portRanges := map[string]db.PortRange{}
+	for _, pr := range newACL.ApplicationPorts {
+		newPorts = append(newPorts, pr.String())
+		portRanges[pr.String()] = pr
+	}
+	for _, pr := range currACL.ApplicationPorts {
+		currPorts = append(currPorts, pr.String())
+		portRanges[pr.String()] = pr
+	}
+	addPorts, removePorts := diffStrings(newPorts, currPorts)
+	for _, pr := range addPorts {
+		p.addPorts = append(p.addPorts, portRanges[pr])
+	}
+	for _, pr := range removePorts {
+		p.removePorts = append(p.removePorts, portRanges[pr])
+	}
+
+	var containers []db.Container
+	var connections []db.Connection
+	var placements []db.Placement
+	if leader, err := getLeaderClient(c, namespace); err != nil {
+		for _, m := range machines {
+			p.noLeader = p.noLeader || m.Namespace == namespace
+		}
+	} else {
+		defer leader.Close()
+
+		if containers, err = leader.QueryContainers(); err != nil {
+			return plan{}, err
+		}
+		if connections, err = leader.QueryConnections(); err != nil {
+			return plan{}, err
+		}
+		if placements, err = leader.QueryPlacements(); err != nil {
+			return plan{}, err
+		}
+	}
+
+	p.addContainers, p.removeContainers = minion.PlanContainers(spec, containers)
+	p.rollingLabels = rollingLabels(spec,
+		append(p.addContainers, p.removeContainers...))
+	p.addConnections, p.removeConnections = minion.PlanConnections(spec,
+		connections)
+	p.addPlacements, p.removePlacements = minion.PlanPlacements(spec, placements)
+	return p, nil
+}
+
+// rollingLabels returns the labels of `changed` that have an update strategy.
+func rollingLabels(spec stitch.Stitch, changed []db.Container) []string {
+	changedLabels := map[string]bool{}
+	for _, dbc := range changed {
+		for _, label := range dbc.Labels {
+			changedLabels[label] = true
+		}
+	}
+
+	var labels []string
+	for _, label := range spec.QueryLabels() {
+		if label.UpdateStrategy != nil && changedLabels[label.Name] {
+			labels = append(labels, label.Name)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// diffStrings returns the strings in `new` but not `old`, and those in `old` but
+// not `new`.
+func diffStrings(new, old []string) (added, removed []string) {
+	newSet := map[string]struct{}{}
+	for _, str := range new {
+		newSet[str] = struct{}{}
+	}
+
+	oldSet := map[string]struct{}{}
+	for _, str := range old {
+		oldSet[str] = struct{}{}
+		if _, ok := newSet[str]; !ok {
+			removed = append(removed, str)
+		}
+	}
+
+	for _, str := range new {
+		if _, ok := oldSet[str]; !ok {
+			added = append(added, str)
+		}
+	}
+	return added, removed
+}
+
+func (p plan) String() string {
+	var sections []string
+	var adds, removes int
+	addSection := func(title string, added, removed []string) {
+		if len(added) == 0 && len(removed) == 0 {
+			return
+		}
+		adds += len(added)
+		removes += len(removed)
+
+		sort.Strings(added)
+		sort.Strings(removed)
+		section := title + ":\n"
+		for _, str := range added {
+			section += "    + " + str + "\n"
+		}
+		for _, str := range removed {
+			section += "    - " + str + "\n"
+		}
+		sections = append(sections, section)
+	}
+
+	var bootStrs, terminateStrs []string
+	for _, m := range p.bootMachines {
+		bootStrs = append(bootStrs, planMachineStr(m))
+	}
+	for _, m := range p.terminateMachines {
+		terminateStrs = append(terminateStrs, planMachineStr(m))
+	}
+	addSection("Machines", bootStrs, terminateStrs)
+
+	var addACLs, removeACLs []string
+	for _, admin := range p.addAdmin {
+		addACLs = append(addACLs, "admin access from "+admin)
+	}
+	for _, admin := range p.removeAdmin {
+		removeACLs = append(removeACLs, "admin access from "+admin)
+	}
+	for _, pr := range p.addPorts {
+		addACLs = append(addACLs, "public access to port "+pr.String())
+	}
+	for _, pr := range p.removePorts {
+		removeACLs = append(removeACLs, "public access to port "+pr.String())
+	}
+	addSection("ACLs", addACLs, removeACLs)
+
+	var addContainers, removeContainers []string
+	for _, dbc := range p.addContainers {
+		addContainers = append(addContainers, planContainerStr(dbc))
+	}
+	for _, dbc := range p.removeContainers {
+		removeContainers = append(removeContainers, planContainerStr(dbc))
+	}
+	addSection("Containers", addContainers, removeContainers)
+
+	var addConnections, removeConnections []string
+	for _, conn := range p.addConnections {
+		addConnections = append(addConnections, planConnectionStr(conn))
+	}
+	for _, conn := range p.removeConnections {
+		removeConnections = append(removeConnections, planConnectionStr(conn))
+	}
+	addSection("Connections", addConnections, removeConnections)
+
+	var addPlacements, removePlacements []string
+	for _, pl := range p.addPlacements {
+		addPlacements = append(addPlacements, planPlacementStr(pl))
+	}
+	for _, pl := range p.removePlacements {
+		removePlacements = append(removePlacements, planPlacementStr(pl))
+	}
+	addSection("Placements", addPlacements, removePlacements)
+
+	var str string
+	if p.noLeader {
+		str += "The lead minion couldn't be reached, so containers, " +
+			"connections, and placements are compared against an empty " +
+			"cluster.\n\n"
+	}
+
+	if len(sections) == 0 {
+		return str + "No changes.\n"
+	}
+
+	str += strings.Join(sections, "\n") +
+		fmt.Sprintf("\nPlan: %d to add, %d to remove.\n", adds, removes)
+	if len(p.rollingLabels) > 0 {
+		str += fmt.Sprintf("The containers of %s are replaced in batches "+
+			"according to their update strategy, which isn't shown.\n",
+			strings.Join(p.rollingLabels, ", "))
+	}
+	return str
+}
+
+// planMachineStr describes a machine, e.g. "Worker Amazon us-west-1 m4.large spot".
+func planMachineStr(m db.Machine) string {
+	var fields []string
+	for _, field := range []string{string(m.Role), string(m.Provider), m.Region,
+		m.Size, string(m.Market), m.CloudID, m.PublicIP} {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	if m.MaxPrice != 0 {
+		fields = append(fields, fmt.Sprintf("(max $%g)", m.MaxPrice))
+	}
+	return strings.Join(fields, " ")
+}
+
+// planContainerStr describes a container, e.g. "3: nginx run [web]".
+func planContainerStr(dbc db.Container) string {
+	str := fmt.Sprintf("%d: %s", dbc.StitchID,
+		strings.Join(append([]string{dbc.Image}, dbc.Command...), " "))
+	if len(dbc.Labels) > 0 {
+		labels := append([]string{}, dbc.Labels...)
+		sort.Strings(labels)
+		str += " [" + strings.Join(labels, ", ") + "]"
+	}
+	return str
+}
+
+// planConnectionStr describes a connection, e.g. "web -> db:3306".
+func planConnectionStr(conn db.Connection) string {
+	pr := db.PortRange{
+		MinPort: conn.MinPort,
+		MaxPort: conn.MaxPort,
+	}
+	str := fmt.Sprintf("%s -> %s:%s", conn.From, conn.To, pr)
+	if conn.CidrIP != "" {
+		str += " from " + conn.CidrIP
+	}
+	return str
+}
+
+// planPlacementStr describes a placement rule, e.g. "web must not share a machine
+// with db".
+func planPlacementStr(pl db.Placement) string {
+	must := "must"
+	if pl.Exclusive {
+		must = "must not"
+	}
+
+	if pl.OtherLabel != "" {
+		return fmt.Sprintf("%s %s share a machine with %s", pl.TargetLabel,
+			must, pl.OtherLabel)
+	}
+
+	var attrs []string
+	for _, attr := range []struct{ name, val string }{
+		{"provider", pl.Provider},
+		{"size", pl.Size},
+		{"region", pl.Region},
+	} {
+		if attr.val != "" {
+			attrs = append(attrs, attr.name+" "+attr.val)
+		}
+	}
+	return fmt.Sprintf("%s %s be on a machine with %s", pl.TargetLabel, must,
+		strings.Join(attrs, " and "))
+}
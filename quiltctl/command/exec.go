@@ -16,6 +16,7 @@ import (
 // Exec contains the options for running commands in containers.
 type Exec struct {
 	privateKey      string
+	namespace       string
 	targetContainer int
 	command         string
 
@@ -38,9 +39,12 @@ func (eCmd *Exec) InstallFlags(flags *flag.FlagSet) {
 
 	flags.StringVar(&eCmd.privateKey, "i", "",
 		"the private key to use to connect to the host")
+	flags.StringVar(&eCmd.namespace, "namespace", "",
+		"the namespace of the container")
 
 	flags.Usage = func() {
 		fmt.Println("usage: quilt exec [-H=<daemon_host>] [-i=<private_key>] " +
+			"[-namespace=<namespace>] " +
 			"<stitch_id> <command>")
 		fmt.Println("`exec` runs a command within the specified container. " +
 			"The container is identified by the stitch ID produced by " +
@@ -69,7 +73,7 @@ func (eCmd *Exec) Parse(args []string) error {
 
 // Run finds the target continer, and executes the given command in it.
 func (eCmd *Exec) Run() int {
-	localClient, leaderClient, err := getClients(eCmd.common.host)
+	localClient, leaderClient, err := getClients(eCmd.common.host, eCmd.namespace)
 	if err != nil {
 		log.Error(err)
 		return 1
@@ -0,0 +1,132 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/db"
+)
+
+// History contains the options for showing the specs the daemon has applied.
+type History struct {
+	// The revision whose spec should be printed, or 0 to list the revisions.
+	revision int
+
+	common *commonFlags
+}
+
+// NewHistoryCommand creates a new History command instance.
+func NewHistoryCommand() *History {
+	return &History{
+		common: &commonFlags{},
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (hCmd *History) InstallFlags(flags *flag.FlagSet) {
+	hCmd.common.InstallFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt history [-H=<daemon_host>] [<revision>]")
+		fmt.Println("`history` lists the specs the Quilt daemon has applied, " +
+			"with their namespace, and when and by whom they were " +
+			"applied.  If a revision is given, its compiled spec is " +
+			"printed instead.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the history command.
+func (hCmd *History) Parse(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	revision, err := strconv.Atoi(args[0])
+	if err != nil || revision < 1 {
+		return errors.New("the revision must be a positive number")
+	}
+	hCmd.revision = revision
+	return nil
+}
+
+// Run prints the revision history, or the spec of a single revision.
+func (hCmd *History) Run() int {
+	c, err := getClient(hCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	revisions, err := c.QueryRevisions()
+	if err != nil {
+		log.WithError(err).Error("Unable to query revisions.")
+		return 1
+	}
+
+	if hCmd.revision == 0 {
+		fmt.Print(historyStr(revisions))
+		return 0
+	}
+
+	for _, rev := range revisions {
+		if rev.Number == hCmd.revision {
+			fmt.Println(rev.Spec)
+			return 0
+		}
+	}
+
+	log.Errorf("No revision %d.", hCmd.revision)
+	return 1
+}
+
+func historyStr(revisions []db.Revision) string {
+	if len(revisions) == 0 {
+		return "No specs have been applied.\n"
+	}
+
+	sort.Sort(revisionSlice(revisions))
+
+	// The latest revision in each namespace is the one that's running.
+	current := map[string]int{}
+	for _, rev := range revisions {
+		current[rev.Namespace] = rev.Number
+	}
+
+	var str string
+	for _, rev := range revisions {
+		user := rev.User
+		if user == "" {
+			user = "unknown user"
+		}
+
+		str += fmt.Sprintf("%d: %s applied by %s at %s", rev.Number,
+			rev.Namespace, user, rev.Time.Format("2006-01-02 15:04:05 MST"))
+		if current[rev.Namespace] == rev.Number {
+			str += " (current)"
+		}
+		str += "\n"
+	}
+	return str
+}
+
+// revisionSlice sorts revisions by number.
+type revisionSlice []db.Revision
+
+func (revs revisionSlice) Len() int {
+	return len(revs)
+}
+
+func (revs revisionSlice) Less(i, j int) bool {
+	return revs[i].Number < revs[j].Number
+}
+
+func (revs revisionSlice) Swap(i, j int) {
+	revs[i], revs[j] = revs[j], revs[i]
+}
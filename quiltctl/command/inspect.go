@@ -7,12 +7,18 @@ import (
 
 // Inspect contains the options for inspecting Stitches.
 type Inspect struct {
-	args []string
+	format string
+	args   []string
 }
 
 // InstallFlags sets up parsing for command line flags.
 func (iCmd *Inspect) InstallFlags(flags *flag.FlagSet) {
-	flags.Usage = inspect.Usage
+	flags.StringVar(&iCmd.format, "format", "",
+		"the output format: html, svg, json, pdf or ascii")
+	flags.Usage = func() {
+		inspect.Usage()
+		flags.PrintDefaults()
+	}
 }
 
 // Parse parses the command line arguments for the inspect command.
@@ -23,5 +29,5 @@ func (iCmd *Inspect) Parse(args []string) error {
 
 // Run inspects the provided Stitch.
 func (iCmd *Inspect) Run() int {
-	return inspect.Main(iCmd.args)
+	return inspect.Main(iCmd.format, iCmd.args)
 }
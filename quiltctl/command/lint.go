@@ -0,0 +1,89 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/engine"
+	"github.com/NetSys/quilt/stitch"
+)
+
+// Lint contains the options for linting Stitches.
+type Lint struct {
+	stitch string
+	sizes  bool
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (lCmd *Lint) InstallFlags(flags *flag.FlagSet) {
+	flags.StringVar(&lCmd.stitch, "stitch", "", "the stitch to lint")
+	flags.BoolVar(&lCmd.sizes, "sizes", false,
+		"explain the sizes chosen for machines that don't give one")
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt lint [-stitch=<stitch>] [-sizes] <stitch>")
+		fmt.Println("`lint` evaluates the provided stitch, and reports " +
+			"mistakes such as unknown providers, unused services, and " +
+			"placement rules that can't be satisfied.  With -sizes, it " +
+			"also explains the size chosen for each machine.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the lint command.
+func (lCmd *Lint) Parse(args []string) error {
+	if lCmd.stitch == "" {
+		if len(args) == 0 {
+			return errors.New("no spec specified")
+		}
+		lCmd.stitch = args[0]
+	}
+
+	return nil
+}
+
+// Run lints the provided Stitch.  It exits with a non-zero status if any problems
+// are found.
+func (lCmd *Lint) Run() int {
+	diags, err := stitch.Lint(lCmd.stitch, stitch.DefaultImportGetter)
+	if err != nil {
+		log.WithError(err).Error("Unable to lint stitch.")
+		return 1
+	}
+
+	fmt.Print(diagnosticsStr(diags))
+
+	if lCmd.sizes {
+		compiled, err := stitch.Compile(lCmd.stitch, stitch.DefaultImportGetter)
+		if err != nil {
+			log.WithError(err).Error("Unable to compile stitch.")
+			return 1
+		}
+
+		spec, err := stitch.New(compiled, stitch.DefaultImportGetter)
+		if err != nil {
+			log.WithError(err).Error("Unable to evaluate stitch.")
+			return 1
+		}
+
+		for _, explanation := range engine.ExplainSizes(spec) {
+			fmt.Println(explanation)
+		}
+	}
+
+	if len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func diagnosticsStr(diags []stitch.Diagnostic) string {
+	var str string
+	for _, diag := range diags {
+		str += fmt.Sprintf("%s\n", diag)
+	}
+	return str
+}
@@ -14,6 +14,7 @@ import (
 // Log is the structure for the `quilt logs` command.
 type Log struct {
 	privateKey     string
+	namespace      string
 	sinceTimestamp string
 	showTimestamps bool
 	shouldTail     bool
@@ -38,12 +39,15 @@ func (lCmd *Log) InstallFlags(flags *flag.FlagSet) {
 
 	flags.StringVar(&lCmd.privateKey, "i", "",
 		"the private key to use to connect to the host")
+	flags.StringVar(&lCmd.namespace, "namespace", "",
+		"the namespace of the container")
 	flags.StringVar(&lCmd.sinceTimestamp, "since", "", "show logs since timestamp")
 	flags.BoolVar(&lCmd.shouldTail, "f", false, "follow log output")
 	flags.BoolVar(&lCmd.showTimestamps, "t", false, "show timestamps")
 
 	flags.Usage = func() {
 		fmt.Println("usage: quilt logs [-H=<daemon_host>] [-i=<private_key>] " +
+			"[-namespace=<namespace>] " +
 			"<stitch_id> <command>")
 		fmt.Println("`logs` fetches the logs of a container. " +
 			"The container is identified by the stitch ID provided by " +
@@ -71,7 +75,7 @@ func (lCmd *Log) Parse(args []string) error {
 
 // Run finds the target continer and outputs logs.
 func (lCmd *Log) Run() int {
-	localClient, leaderClient, err := getClients(lCmd.common.host)
+	localClient, leaderClient, err := getClients(lCmd.common.host, lCmd.namespace)
 	if err != nil {
 		log.Error(err)
 		return 1
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"flag"
 	"fmt"
 
 	log "github.com/Sirupsen/logrus"
@@ -10,6 +11,9 @@ import (
 
 // Machine contains the options for querying machines.
 type Machine struct {
+	// Only machines in this namespace are shown, unless it's empty.
+	namespace string
+
 	*commonFlags
 }
 
@@ -20,6 +24,14 @@ func NewMachineCommand() *Machine {
 	}
 }
 
+// InstallFlags sets up parsing for command line flags.
+func (mCmd *Machine) InstallFlags(flags *flag.FlagSet) {
+	mCmd.commonFlags.InstallFlags(flags)
+
+	flags.StringVar(&mCmd.namespace, "namespace", "",
+		"only show the machines in this namespace")
+}
+
 // Parse parses the command line arguments for the machine command.
 func (mCmd *Machine) Parse(args []string) error {
 	return nil
@@ -40,16 +52,20 @@ func (mCmd *Machine) Run() int {
 		return 1
 	}
 
-	str := machinesStr(machines)
+	str := machinesStr(machines, mCmd.namespace)
 	fmt.Print(str)
 
 	return 0
 }
 
-func machinesStr(machines []db.Machine) string {
+// machinesStr lists the machines in `namespace`, or every machine if `namespace` is
+// empty.
+func machinesStr(machines []db.Machine, namespace string) string {
 	var machinesStr string
 	for _, m := range db.SortMachines(machines) {
-		machinesStr += fmt.Sprintf("%v\n", m)
+		if namespace == "" || m.Namespace == namespace {
+			machinesStr += fmt.Sprintf("%v\n", m)
+		}
 	}
 
 	return machinesStr
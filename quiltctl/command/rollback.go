@@ -0,0 +1,79 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	log "github.com/Sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// Rollback contains the options for re-applying an earlier spec.
+type Rollback struct {
+	revision int
+	force    bool
+
+	common *commonFlags
+}
+
+// NewRollbackCommand creates a new Rollback command instance.
+func NewRollbackCommand() *Rollback {
+	return &Rollback{
+		common: &commonFlags{},
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (rCmd *Rollback) InstallFlags(flags *flag.FlagSet) {
+	rCmd.common.InstallFlags(flags)
+
+	flags.BoolVar(&rCmd.force, "force", false,
+		"roll back even if it would terminate many of the machines")
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt rollback [-H=<daemon_host>] [-force] " +
+			"<revision>")
+		fmt.Println("`rollback` asks the Quilt daemon to re-apply the spec " +
+			"of an earlier revision, as listed by `quilt history`.  The " +
+			"rollback is recorded as a new revision.  Like `quilt run`, " +
+			"it's refused if it would terminate too many machines unless " +
+			"-force is given.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the rollback command.
+func (rCmd *Rollback) Parse(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no revision specified")
+	}
+
+	revision, err := strconv.Atoi(args[0])
+	if err != nil || revision < 1 {
+		return errors.New("the revision must be a positive number")
+	}
+	rCmd.revision = revision
+	return nil
+}
+
+// Run re-applies the spec of the requested revision.
+func (rCmd *Rollback) Run() int {
+	c, err := getClient(rCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	if err = c.Rollback(rCmd.revision, rCmd.force); err != nil {
+		log.Error("Unable to roll back.")
+		fmt.Fprintln(os.Stderr, grpc.ErrorDesc(err))
+		return 1
+	}
+
+	fmt.Printf("Successfully rolled back to revision %d.\n", rCmd.revision)
+	return 0
+}
@@ -10,6 +10,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/robertkrimen/otto"
+	"google.golang.org/grpc"
 
 	"github.com/NetSys/quilt/stitch"
 )
@@ -17,6 +18,8 @@ import (
 // Run contains the options for running Stitches.
 type Run struct {
 	stitch string
+	dryRun bool
+	force  bool
 
 	common *commonFlags
 }
@@ -33,12 +36,19 @@ func (rCmd *Run) InstallFlags(flags *flag.FlagSet) {
 	rCmd.common.InstallFlags(flags)
 
 	flags.StringVar(&rCmd.stitch, "stitch", "", "the stitch to run")
+	flags.BoolVar(&rCmd.dryRun, "dry-run", false,
+		"show the changes the stitch would make without making them")
+	flags.BoolVar(&rCmd.force, "force", false,
+		"run the stitch even if it would terminate many of the machines")
 
 	flags.Usage = func() {
-		fmt.Println("usage: quilt run [-H=<daemon_host>] " +
-			"[-stitch=<stitch>] <stitch>")
+		fmt.Println("usage: quilt run [-H=<daemon_host>] [-dry-run] " +
+			"[-force] [-stitch=<stitch>] <stitch>")
 		fmt.Println("`run` compiles the provided stitch, and sends the " +
-			"result to the Quilt daemon to be executed.")
+			"result to the Quilt daemon to be executed.  With -dry-run, " +
+			"it instead shows what the daemon would change, like " +
+			"`quilt diff`.  The daemon refuses stitches that would " +
+			"terminate too many machines unless -force is given.")
 		flags.PrintDefaults()
 	}
 }
@@ -64,7 +74,32 @@ func (rCmd *Run) Run() int {
 	}
 	defer c.Close()
 
-	stitchPath := rCmd.stitch
+	compiled, err := compileStitch(rCmd.stitch)
+	if err != nil {
+		logCompileError(err)
+		return 1
+	}
+
+	if rCmd.dryRun {
+		return showPlan(c, compiled)
+	}
+
+	err = c.RunStitch(compiled, rCmd.force)
+	if err != nil {
+		// The description is printed on its own so that multi-line errors, such
+		// as the counterexample for a failed invariant, stay readable.
+		log.Error("Unable to start run.")
+		fmt.Fprintln(os.Stderr, grpc.ErrorDesc(err))
+		return 1
+	}
+
+	fmt.Println("Successfully started run.")
+	return 0
+}
+
+// compileStitch compiles the stitch at `stitchPath`.  Relative paths that don't
+// exist are also looked up in the QUILT_PATH.
+func compileStitch(stitchPath string) (string, error) {
 	compiled, err := stitch.Compile(stitchPath, stitch.DefaultImportGetter)
 	if err != nil && os.IsNotExist(err) && !filepath.IsAbs(stitchPath) {
 		// Automatically add the ".js" file suffix if it's not provided.
@@ -75,22 +110,14 @@ func (rCmd *Run) Run() int {
 			filepath.Join(stitch.GetQuiltPath(), stitchPath),
 			stitch.DefaultImportGetter)
 	}
-	if err != nil {
-		// Print the stacktrace if it's an Otto error.
-		if ottoError, ok := err.(*otto.Error); ok {
-			log.Error(ottoError.String())
-		} else {
-			log.Error(err)
-		}
-		return 1
-	}
+	return compiled, err
+}
 
-	err = c.RunStitch(compiled)
-	if err != nil {
-		log.WithError(err).Error("Unable to start run.")
-		return 1
+func logCompileError(err error) {
+	// Print the stacktrace if it's an Otto error.
+	if ottoError, ok := err.(*otto.Error); ok {
+		log.Error(ottoError.String())
+	} else {
+		log.Error(err)
 	}
-
-	fmt.Println("Successfully started run.")
-	return 0
 }
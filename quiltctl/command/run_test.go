@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/stitch"
 	"github.com/NetSys/quilt/util"
 )
 
@@ -124,3 +126,105 @@ func checkRunParsing(t *testing.T, args []string, expStitch string, expErr error
 			expStitch, runCmd.stitch)
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	c := &mockClient{
+		machineReturn: []db.Machine{{
+			Namespace: "default-namespace",
+			Role:      db.Master,
+			Provider:  db.Amazon,
+			Region:    "us-west-1",
+			Size:      "m4.large",
+			DiskSize:  32,
+			Market:    db.Spot,
+			CloudID:   "i-1",
+		}, {
+			Namespace: "default-namespace",
+			Role:      db.Worker,
+			Provider:  db.Amazon,
+			Region:    "us-west-1",
+			Size:      "m4.xlarge",
+			DiskSize:  32,
+			Market:    db.Spot,
+			CloudID:   "i-2",
+		}, {
+			// Machines in other namespaces are left alone.
+			Namespace: "other",
+			Role:      db.Worker,
+			Provider:  db.Amazon,
+			Region:    "us-west-1",
+			Size:      "m4.2xlarge",
+			DiskSize:  32,
+			Market:    db.Spot,
+			CloudID:   "i-3",
+		}},
+		aclReturn: []db.ACL{
+			{Namespace: "default-namespace", Admin: []string{"1.2.3.4/32"}},
+			{Namespace: "other", Admin: []string{"5.6.7.8/32"}},
+		},
+	}
+	getClient = func(host string) (client.Client, error) {
+		return c, nil
+	}
+
+	util.AppFs = afero.NewMemMapFs()
+	util.WriteFile("test.js", []byte(`var machine = new Machine({
+		provider: "Amazon", size: "m4.large"});
+	deployment.deploy([machine.asMaster(), machine.asWorker()]);
+	var web = new Service("web", [new Container("nginx")]);
+	publicInternet.connect(80, web);
+	deployment.deploy(web);`), 0644)
+
+	runCmd := NewRunCommand()
+	runCmd.stitch = "test.js"
+	runCmd.dryRun = true
+	assert.Equal(t, 0, runCmd.Run())
+	assert.Equal(t, "", c.runStitchArg)
+
+	compiled, err := compileStitch("test.js")
+	assert.Nil(t, err)
+	spec, err := stitch.New(compiled, stitch.DefaultImportGetter)
+	assert.Nil(t, err)
+
+	p, err := makePlan(c, spec)
+	assert.Nil(t, err)
+	assert.Equal(t, "The lead minion couldn't be reached, so containers, "+
+		`connections, and placements are compared against an empty cluster.
+
+Machines:
+    + Worker Amazon us-west-1 m4.large spot
+    - Worker Amazon us-west-1 m4.xlarge spot i-2
+
+ACLs:
+    + public access to port 80
+    - admin access from 1.2.3.4/32
+
+Containers:
+    + 1: nginx [web]
+
+Connections:
+    + public -> web:80
+
+Placements:
+    + web must not share a machine with web
+
+Plan: 5 to add, 2 to remove.
+`, p.String())
+
+	assert.Equal(t, "No changes.\n", plan{}.String())
+
+	util.WriteFile("test.js", []byte(`var web = new Service("web",
+		[new Container("nginx")]);
+	web.setUpdateStrategy({maxUnavailable: 1});
+	deployment.deploy(web);`), 0644)
+	compiled, err = compileStitch("test.js")
+	assert.Nil(t, err)
+	spec, err = stitch.New(compiled, stitch.DefaultImportGetter)
+	assert.Nil(t, err)
+
+	p, err = makePlan(c, spec)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"web"}, p.rollingLabels)
+	assert.Contains(t, p.String(), "The containers of web are replaced in "+
+		"batches according to their update strategy, which isn't shown.\n")
+}
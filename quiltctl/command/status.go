@@ -0,0 +1,78 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/db"
+)
+
+// Status contains the options for querying the health of the deployment.
+type Status struct {
+	// The namespace whose lead minion is queried.  It may be omitted if only one
+	// namespace has machines.
+	namespace string
+
+	*commonFlags
+}
+
+// NewStatusCommand creates a new Status command instance.
+func NewStatusCommand() *Status {
+	return &Status{
+		commonFlags: &commonFlags{},
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (sCmd *Status) InstallFlags(flags *flag.FlagSet) {
+	sCmd.commonFlags.InstallFlags(flags)
+
+	flags.StringVar(&sCmd.namespace, "namespace", "",
+		"show the invariants of this namespace")
+}
+
+// Parse parses the command line arguments for the status command.
+func (sCmd *Status) Parse(args []string) error {
+	return nil
+}
+
+// Run retrieves and prints the invariants that don't hold for the running cluster.
+func (sCmd *Status) Run() int {
+	localClient, err := getClient(sCmd.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+
+	c, err := getLeaderClient(localClient, sCmd.namespace)
+	localClient.Close()
+	if err != nil {
+		log.WithError(err).Error("Error connecting to leader.")
+		return 1
+	}
+	defer c.Close()
+
+	violations, err := c.QueryViolations()
+	if err != nil {
+		log.WithError(err).Error("Unable to query invariant violations.")
+		return 1
+	}
+
+	fmt.Print(violationsStr(violations))
+	return 0
+}
+
+func violationsStr(violations []db.Violation) string {
+	if len(violations) == 0 {
+		return "All invariants hold.\n"
+	}
+
+	var str string
+	for _, v := range violations {
+		str += fmt.Sprintf("invariant failed: %s\n    %s\n", v.Invariant,
+			v.Counterexample)
+	}
+	return str
+}
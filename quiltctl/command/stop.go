@@ -64,7 +64,7 @@ func (sCmd *Stop) Run() int {
 	}
 	defer c.Close()
 
-	if err = c.RunStitch(specStr); err != nil {
+	if err = c.RunStitch(specStr, true); err != nil {
 		log.WithError(err).Error("Unable to stop namespace.")
 		return 1
 	}
@@ -12,16 +12,22 @@ import (
 )
 
 var commands = map[string]command.SubCommand{
+	"catalog":    command.NewCatalogCommand(),
 	"containers": command.NewContainerCommand(),
 	"daemon":     command.NewDaemonCommand(),
+	"diff":       command.NewDiffCommand(),
 	"exec":       command.NewExecCommand(ssh.NewNativeClient()),
 	"get":        &command.Get{},
+	"history":    command.NewHistoryCommand(),
 	"inspect":    &command.Inspect{},
+	"lint":       &command.Lint{},
 	"logs":       command.NewLogCommand(ssh.NewNativeClient()),
 	"machines":   command.NewMachineCommand(),
 	"minion":     &command.Minion{},
+	"rollback":   command.NewRollbackCommand(),
 	"run":        command.NewRunCommand(),
 	"ssh":        command.NewSSHCommand(),
+	"status":     command.NewStatusCommand(),
 	"stop":       command.NewStopCommand(),
 }
 
@@ -34,6 +34,7 @@ haproxy.service.place(new LabelRule(true, haproxy.service));
 
 mongo.connect(mongo.port(), app);
 app.connect(mongo.port(), mongo);
+app.dependsOn(mongo);
 haproxy.public();
 
 namespace.deploy(app);
@@ -39,4 +39,10 @@ Node.prototype.connect = function(port, to) {
   }.bind(this));
 };
 
+Node.prototype.dependsOn = function(on, opts) {
+  on.services().forEach(function(service) {
+    this._app.dependsOn(service, opts);
+  }.bind(this));
+};
+
 module.exports = Node;
@@ -53,7 +53,11 @@ Deployment.prototype.toQuiltRepresentation = function() {
         services.push({
             name: service.name,
             ids: ids,
-            annotations: service.annotations
+            annotations: service.annotations,
+            updateStrategy: service.updateStrategy,
+            job: service.job,
+            dependencies: service.getQuiltDependencies(),
+            protected: service.protected
         });
     });
 
@@ -76,6 +80,46 @@ Deployment.prototype.toQuiltRepresentation = function() {
     };
 };
 
+// Describe the deployment, along with where each of its parts was declared, so
+// that it can be checked by quilt lint.
+Deployment.prototype.toLintRepresentation = function() {
+    var deployed = {};
+    this.services.forEach(function(service) {
+        deployed[service.name] = true;
+    });
+
+    var services = allServices.map(function(service) {
+        var ports = [];
+        service.containers.forEach(function(container) {
+            ports = ports.concat(container.ports);
+        });
+
+        return {
+            name: service.name,
+            location: service.location,
+            deployed: deployed[service.name] || false,
+            containers: service.containers.length,
+            ports: ports,
+            connections: service.connections.map(function(conn) {
+                return {
+                    to: conn.to.name,
+                    minPort: conn.minPort,
+                    maxPort: conn.maxPort,
+                    location: conn.location
+                };
+            }),
+            incomingPublic: service.incomingPublic.length,
+            job: service.job !== undefined,
+            placements: service.placements
+        };
+    });
+
+    return {
+        services: services,
+        machines: this.machines
+    };
+};
+
 // Check if all referenced services in connections and placements are really deployed.
 Deployment.prototype.vet = function() {
     var labelMap = {};
@@ -91,6 +135,13 @@ Deployment.prototype.vet = function() {
             }
         });
 
+        service.dependencies.forEach(function(dep) {
+            var on = dep.service.name;
+            if (!labelMap[on]) {
+                throw service.name + " depends on undeployed service: " + on;
+            }
+        });
+
         service.placements.forEach(function(plcm) {
             var otherLabel = plcm.otherLabel;
             if (otherLabel !== undefined && !labelMap[otherLabel]) {
@@ -121,15 +172,21 @@ Deployment.prototype.assert = function(rule, desired) {
     this.invariants.push(new Assertion(rule, desired));
 };
 
+var allServices = [];
+
 function Service(name, containers) {
     this.name = uniqueLabelName(name);
+    this.location = callerLocation();
+    allServices.push(this);
     this.containers = containers;
     this.annotations = [];
     this.placements = [];
+    this.protected = false;
 
     this.connections = [];
     this.outgoingPublic = [];
     this.incomingPublic = [];
+    this.dependencies = [];
 }
 
 // Get the Quilt hostname that represents the entire service.
@@ -151,13 +208,28 @@ Service.prototype.annotate = function(annotation) {
     this.annotations.push(annotation);
 };
 
+// Protect the service from removal.  The daemon refuses specs that would remove a
+// protected service until it's unprotected.
+Service.prototype.protect = function() {
+    this.protected = true;
+    return this;
+};
+
 Service.prototype.canReach = function(target) {
-    if (target === publicInternet) {
+    if (target instanceof PublicInternet) {
         return reachable(this.name, publicInternetLabel);
     }
     return reachable(this.name, target.name);
 };
 
+// Assert reachability only on the given port, e.g. web.canReachPort(db, 5432).
+Service.prototype.canReachPort = function(target, port) {
+    if (target instanceof PublicInternet) {
+        return reachablePort(this.name, publicInternetLabel, port);
+    }
+    return reachablePort(this.name, target.name, port);
+};
+
 Service.prototype.canReachACL = function(target) {
     return reachableACL(this.name, target.name);
 };
@@ -166,6 +238,10 @@ Service.prototype.between = function(src, dst) {
     return between(src.name, this.name, dst.name);
 };
 
+Service.prototype.betweenPort = function(src, dst, port) {
+    return betweenPort(src.name, this.name, dst.name, port);
+};
+
 Service.prototype.neighborOf = function(target) {
     return neighbor(this.name, target.name);
 };
@@ -177,46 +253,98 @@ Service.prototype.deploy = function(deployment) {
 
 Service.prototype.connect = function(range, to) {
     range = boxRange(range);
-    if (to === publicInternet) {
-        return this.connectToPublic(range);
+    if (to instanceof PublicInternet) {
+        return this.connectToPublic(range, to.cidr);
     }
     this.connections.push(new Connection(range, to));
 };
 
-// publicInternet is an object that looks like another service that can be
-// connected to or from. However, it is actually just syntactic sugar to hide
-// the connectToPublic and connectFromPublic functions.
-var publicInternet = {
-    connect: function(range, to) {
-        to.connectFromPublic(range);
-    },
-    canReach: function(to) {
-        return reachable(publicInternetLabel, to.name);
+// PublicInternet looks like another service that can be connected to or from.
+// However, it is actually just syntactic sugar to hide the connectToPublic and
+// connectFromPublic functions.  If cidr is set, traffic is restricted to
+// hosts within that block.
+function PublicInternet(cidr) {
+    this.cidr = cidr || "";
+}
+
+PublicInternet.prototype.connect = function(range, to) {
+    to.connectFromPublic(range, this.cidr);
+};
+
+PublicInternet.prototype.canReach = function(to) {
+    return reachable(publicInternetLabel, to.name);
+};
+
+PublicInternet.prototype.canReachPort = function(to, port) {
+    return reachablePort(publicInternetLabel, to.name, port);
+};
+
+// Restrict the public internet to the hosts in cidr, e.g.
+// publicInternet.fromCIDR("1.2.3.0/24").connect(8080, admin).
+PublicInternet.prototype.fromCIDR = function(cidr) {
+    if (!isCIDR(cidr)) {
+        throw "invalid CIDR: " + cidr;
     }
+    return new PublicInternet(cidr);
 };
 
+var publicInternet = new PublicInternet();
+
+function isCIDR(cidr) {
+    var match = /^(\d+)\.(\d+)\.(\d+)\.(\d+)\/(\d+)$/.exec(cidr);
+    if (match === null) {
+        return false;
+    }
+    for (var i = 1; i <= 4; i++) {
+        if (parseInt(match[i]) > 255) {
+            return false;
+        }
+    }
+    return parseInt(match[5]) <= 32;
+}
+
 // Allow outbound traffic from the service to public internet.
-Service.prototype.connectToPublic = function(range) {
+Service.prototype.connectToPublic = function(range, cidr) {
     range = boxRange(range);
-    if (range.min != range.max) {
-        throw "public internet cannot connect on port ranges";
-    }
-    this.outgoingPublic.push(range);
+    this.outgoingPublic.push(new PublicRange(range, cidr));
 };
 
 // Allow inbound traffic from public internet to the service.
-Service.prototype.connectFromPublic = function(range) {
+Service.prototype.connectFromPublic = function(range, cidr) {
     range = boxRange(range);
-    if (range.min != range.max) {
-        throw "public internet cannot connect on port ranges";
-    }
-    this.incomingPublic.push(range);
+    this.incomingPublic.push(new PublicRange(range, cidr));
 };
 
 Service.prototype.place = function(rule) {
     this.placements.push(rule);
 };
 
+// Hold back the service's containers until a container of service has an IP
+// address and is running.  If opts.untilHealthy is set, wait for all of
+// them instead.
+Service.prototype.dependsOn = function(service, opts) {
+    opts = opts || {};
+    this.dependencies.push({
+        service: service,
+        untilHealthy: opts.untilHealthy || false
+    });
+};
+
+Service.prototype.getQuiltDependencies = function() {
+    return this.dependencies.map(function(dep) {
+        return {
+            label: dep.service.name,
+            untilHealthy: dep.untilHealthy
+        };
+    });
+};
+
+// Control how the service's containers are replaced when their image, command
+// or environment changes.  By default, all of them are replaced at once.
+Service.prototype.setUpdateStrategy = function(strategy) {
+    this.updateStrategy = new UpdateStrategy(strategy);
+};
+
 Service.prototype.getQuiltConnections = function() {
     var connections = [];
     var that = this;
@@ -235,7 +363,8 @@ Service.prototype.getQuiltConnections = function() {
             from: that.name,
             to: publicInternetLabel,
             minPort: rng.min,
-            maxPort: rng.max
+            maxPort: rng.max,
+            cidrIP: rng.cidr
         });
     });
 
@@ -244,7 +373,8 @@ Service.prototype.getQuiltConnections = function() {
             from: publicInternetLabel,
             to: that.name,
             minPort: rng.min,
-            maxPort: rng.max
+            maxPort: rng.max,
+            cidrIP: rng.cidr
         });
     });
 
@@ -268,6 +398,25 @@ Service.prototype.getQuiltPlacements = function() {
     return placements;
 };
 
+// A Job is a Service whose containers run to completion, rather than being
+// restarted whenever they exit.  It runs opts.completions copies of container, at
+// most opts.parallelism at a time.  A container that exits with a non-zero code
+// is retried up to opts.backoffLimit times, waiting opts.backoff seconds before
+// the first retry, and twice as long before each one after that.
+function Job(name, container, opts) {
+    opts = opts || {};
+    var completions = opts.completions === undefined ? 1 : opts.completions;
+    if (completions < 1) {
+        throw "job completions must be positive";
+    }
+
+    Service.call(this, name, container.replicate(completions));
+    this.job = new JobSpec(completions, opts);
+}
+
+Job.prototype = Object.create(Service.prototype);
+Job.prototype.constructor = Job;
+
 var labelNameCount = {};
 function uniqueLabelName(name) {
     if (!(name in labelNameCount)) {
@@ -280,6 +429,18 @@ function uniqueLabelName(name) {
     return name + labelNameCount[name];
 }
 
+// Get the "file:line" of the spec code that called into the bindings.
+function callerLocation() {
+    var frames = new Error().stack.split("\n");
+    for (var i = 1; i < frames.length; i++) {
+        var match = frames[i].match(/([^ ()]+):(\d+):\d+\)?$/);
+        if (match && match[1] !== "<javascript_bindings>") {
+            return match[1] + ":" + match[2];
+        }
+    }
+    return "";
+}
+
 // Box raw integers into range.
 function boxRange(x) {
     if (x === undefined) {
@@ -292,14 +453,27 @@ function boxRange(x) {
 }
 
 function Machine(optionalArgs) {
+    this.location = callerLocation();
     this.provider = optionalArgs.provider || "";
     this.role = optionalArgs.role || "";
     this.region = optionalArgs.region || "";
     this.size = optionalArgs.size || "";
     this.diskSize = optionalArgs.diskSize || 0;
+    this.market = optionalArgs.market || "";
+    this.maxPrice = optionalArgs.maxPrice || 0;
+    this.image = optionalArgs.image || "";
+    this.bootScriptExtra = optionalArgs.bootScriptExtra || "";
+    this.host = optionalArgs.host || "";
     this.sshKeys = optionalArgs.sshKeys || [];
     this.cpu = boxRange(optionalArgs.cpu);
     this.ram = boxRange(optionalArgs.ram);
+    this.gpu = boxRange(optionalArgs.gpu);
+    this.protected = optionalArgs.protected || false;
+    this.localSSD = optionalArgs.localSSD || false;
+    this.minNetwork = optionalArgs.minNetwork || 0;
+    this.families = optionalArgs.families || [];
+    this.excludeFamilies = optionalArgs.excludeFamilies || [];
+    this.preferPreviousGeneration = optionalArgs.preferPreviousGeneration || false;
 }
 
 Machine.prototype.deploy = function(deployment) {
@@ -308,10 +482,11 @@ Machine.prototype.deploy = function(deployment) {
 
 // Create a new machine with the same attributes.
 Machine.prototype.clone = function() {
-    // _.clone only creates a shallow copy, so we must clone sshKeys ourselves.
-    var keyClone = _.clone(this.sshKeys);
+    // _.clone only creates a shallow copy, so we must clone the arrays ourselves.
     var cloned = _.clone(this);
-    cloned.sshKeys = keyClone;
+    cloned.sshKeys = _.clone(this.sshKeys);
+    cloned.families = _.clone(this.families);
+    cloned.excludeFamilies = _.clone(this.excludeFamilies);
     return new Machine(cloned);
 };
 
@@ -346,15 +521,24 @@ function Container(image, command) {
     this.image = image;
     this.command = command || [];
     this.env = {};
+    this.ports = [];
 }
 
 // Create a new Container with the same attributes.
 Container.prototype.clone = function() {
     var cloned = new Container(this.image, _.clone(this.command));
     cloned.env = _.clone(this.env);
+    cloned.ports = _.clone(this.ports);
     return cloned;
 };
 
+// Declare that the container listens on the given port, or range of ports.
+// Quilt lint uses this to find connections that nothing will answer.
+Container.prototype.listen = function(range) {
+    this.ports.push(boxRange(range));
+    return this;
+};
+
 // Create n new Containers with the same attributes.
 Container.prototype.replicate = function(n) {
     var i;
@@ -380,10 +564,13 @@ var between = invariantType("between");
 var neighbor = invariantType("reachDirect");
 var reachableACL = invariantType("reachACL");
 var reachable = invariantType("reach");
+var reachablePort = portInvariantType("reachPort");
+var betweenPort = portInvariantType("betweenPort");
 
 function Assertion(invariant, desired) {
     this.form = invariant.form;
     this.nodes = invariant.nodes;
+    this.port = invariant.port;
     this.target = desired;
 }
 
@@ -404,12 +591,29 @@ function invariantType(form) {
     };
 }
 
+// Port invariants take the nodes they operate on, followed by the port that
+// connections must be open on.
+function portInvariantType(form) {
+    var nodeInvariant = invariantType(form);
+    return function() {
+        var invariant = nodeInvariant.apply(this, arguments);
+        var port = invariant.nodes.pop();
+        if (typeof port !== "number" || port <= 0) {
+            throw form + " requires a positive port";
+        }
+        invariant.port = port;
+        return invariant;
+    };
+}
+
 function LabelRule(exclusive, otherService) {
+    this.location = callerLocation();
     this.exclusive = exclusive;
     this.otherLabel = otherService.name;
 }
 
 function MachineRule(exclusive, optionalArgs) {
+    this.location = callerLocation();
     this.exclusive = exclusive;
     if (optionalArgs.provider) {
         this.provider = optionalArgs.provider;
@@ -423,6 +627,7 @@ function MachineRule(exclusive, optionalArgs) {
 }
 
 function Connection(ports, to) {
+    this.location = callerLocation();
     this.minPort = ports.min;
     this.maxPort = ports.max;
     this.to = to;
@@ -438,3 +643,40 @@ function Port(p) {
 }
 
 var PortRange = Range;
+
+// maxUnavailable is the number of containers that may be down at any point
+// during an update, maxSurge is the number of containers that may be started in
+// excess of the service's size, and pause is the number of seconds to wait
+// between batches.
+function UpdateStrategy(opts) {
+    this.maxUnavailable = opts.maxUnavailable || 0;
+    this.maxSurge = opts.maxSurge || 0;
+    this.pause = opts.pause || 0;
+
+    if (this.maxUnavailable < 0 || this.maxSurge < 0 || this.pause < 0) {
+        throw "update strategy parameters must be non-negative";
+    }
+    if (this.maxUnavailable === 0 && this.maxSurge === 0) {
+        throw "update strategy must allow either maxUnavailable or maxSurge";
+    }
+}
+
+function JobSpec(completions, opts) {
+    this.completions = completions;
+    this.parallelism = opts.parallelism === undefined ? 1 : opts.parallelism;
+    this.backoffLimit = opts.backoffLimit === undefined ? 6 : opts.backoffLimit;
+    this.backoff = opts.backoff === undefined ? 10 : opts.backoff;
+
+    if (this.parallelism < 1) {
+        throw "job parallelism must be positive";
+    }
+    if (this.backoffLimit < 0 || this.backoff < 0) {
+        throw "job backoff parameters must be non-negative";
+    }
+}
+
+function PublicRange(range, cidr) {
+    this.min = range.min;
+    this.max = range.max;
+    this.cidr = cidr || "";
+}
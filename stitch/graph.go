@@ -2,6 +2,9 @@ package stitch
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // A Node in the communiction Graph.
@@ -10,8 +13,22 @@ type Node struct {
 	Label       string
 	Annotations map[string]struct{}
 	Connections map[string]Node
+
+	// The port ranges this Node may connect to, keyed by the name of the Node
+	// the connection goes to.
+	Ports map[string][]PortRange
+}
+
+// A PortRange is an inclusive range of ports.
+type PortRange struct {
+	Min int
+	Max int
 }
 
+// anyPort is used to query the graph regardless of the ports connections are open
+// on.
+const anyPort = 0
+
 // An Edge in the communication Graph.
 type Edge struct {
 	From string
@@ -28,6 +45,14 @@ type Graph struct {
 	// Constraints on which containers can be placed together.
 	Placement map[string][]string
 	Machines  []Machine
+
+	// The placement rules the containers are scheduled according to.
+	Constraints []Placement
+
+	// For a graph of a running deployment, the index in Machines of the Worker
+	// that each container already runs on.  Only the other containers are
+	// scheduled.
+	Placed map[string]int
 }
 
 // InitializeGraph queries the Stitch to fill in the Graph structure.
@@ -42,13 +67,13 @@ func InitializeGraph(spec Stitch) (Graph, error) {
 
 	for _, label := range spec.QueryLabels() {
 		for _, cid := range label.IDs {
-			g.addNode(fmt.Sprintf("%d", cid), label.Name, label.Annotations)
+			g.addNode(NodeName(cid), label.Name, label.Annotations)
 		}
 	}
 	g.addNode(PublicInternetLabel, PublicInternetLabel, []string{})
 
 	for _, conn := range spec.QueryConnections() {
-		err := g.addConnection(conn.From, conn.To)
+		err := g.addConnection(conn.From, conn.To, conn.MinPort, conn.MaxPort)
 		if err != nil {
 			return Graph{}, err
 		}
@@ -59,6 +84,7 @@ func InitializeGraph(spec Stitch) (Graph, error) {
 		if err != nil {
 			return Graph{}, err
 		}
+		g.Constraints = append(g.Constraints, pl)
 	}
 
 	for _, m := range spec.QueryMachines() {
@@ -68,6 +94,61 @@ func InitializeGraph(spec Stitch) (Graph, error) {
 	return g, nil
 }
 
+// NewGraph creates a Graph containing only the public internet.  Callers fill it in
+// with AddContainer and AddEdge, e.g. to describe a running deployment rather than
+// a spec.
+func NewGraph() Graph {
+	g := Graph{
+		Nodes:        map[string]Node{},
+		Availability: []AvailabilitySet{{}},
+		Placement:    map[string][]string{},
+		Machines:     []Machine{},
+	}
+	g.addNode(PublicInternetLabel, PublicInternetLabel, []string{})
+	return g
+}
+
+// NodeName returns the name of the Node of the container whose ID is `id`.  Graphs
+// that describe a running deployment must name their Nodes the same way, so that
+// their counterexamples refer to the same containers as the spec.
+func NodeName(id int) string {
+	return strconv.Itoa(id)
+}
+
+// AddContainer adds a Node named `name` that implements `label`.
+func (g *Graph) AddContainer(name, label string, annotations []string) {
+	g.addNode(name, label, annotations)
+}
+
+// PlaceContainer records that the Node named `name` runs on Machines[machine].
+func (g *Graph) PlaceContainer(name string, machine int) {
+	if g.Placed == nil {
+		g.Placed = map[string]int{}
+	}
+	g.Placed[name] = machine
+}
+
+// AddEdge permits the Node named `from` to connect to the Node named `to` on the
+// ports between `minPort` and `maxPort` inclusive.
+func (g *Graph) AddEdge(from, to string, minPort, maxPort int) error {
+	fromNode, ok := g.Nodes[from]
+	if !ok {
+		return fmt.Errorf("unknown node: %s", from)
+	}
+
+	toNode, ok := g.Nodes[to]
+	if !ok {
+		return fmt.Errorf("unknown node: %s", to)
+	}
+
+	if from != to {
+		fromNode.Connections[to] = toNode
+		fromNode.Ports[to] = append(fromNode.Ports[to],
+			PortRange{Min: minPort, Max: maxPort})
+	}
+	return nil
+}
+
 // GetConnections returns a list of the edges in the Graph.
 func (g Graph) GetConnections() []Edge {
 	var res []Edge
@@ -91,7 +172,7 @@ func (g Graph) copyGraph() Graph {
 	return Graph{Nodes: newNodes, Availability: newAvail}
 }
 
-func (g *Graph) addConnection(from string, to string) error {
+func (g *Graph) addConnection(from string, to string, minPort, maxPort int) error {
 	// from and to are labels
 	var fromContainers []Node
 	var toContainers []Node
@@ -109,6 +190,9 @@ func (g *Graph) addConnection(from string, to string) error {
 		for _, toNode := range toContainers {
 			if fromNode.Name != toNode.Name {
 				fromNode.Connections[toNode.Name] = toNode
+				fromNode.Ports[toNode.Name] = append(
+					fromNode.Ports[toNode.Name],
+					PortRange{Min: minPort, Max: maxPort})
 			}
 		}
 	}
@@ -134,6 +218,7 @@ func (g *Graph) addNode(cid string, label string, annotations []string) Node {
 		Label:       label,
 		Annotations: annotationSet,
 		Connections: map[string]Node{},
+		Ports:       map[string][]PortRange{},
 	}
 	g.Nodes[cid] = n
 	g.Availability[0].Insert(cid)
@@ -148,31 +233,64 @@ func (g *Graph) removeNode(label string) {
 	// Delete edges to this Node.
 	for _, n := range g.getNodes() {
 		delete(n.Connections, label)
+		delete(n.Ports, label)
 	}
 }
 
-// Find all nodes reachable from the given node.
-func (n Node) dfs() []string {
+// allows returns true if `n` may connect to the node named `to` on `port`.
+func (n Node) allows(to string, port int) bool {
+	if _, ok := n.Connections[to]; !ok {
+		return false
+	} else if port == anyPort {
+		return true
+	}
+
+	for _, r := range n.Ports[to] {
+		if r.Min <= port && port <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// Find all nodes reachable from the given node on `port`.  Only the last hop must
+// be open on `port`, as a container may use any of its connections to relay
+// traffic.
+func (n Node) dfs(port int) []string {
+	var reachable []string
+	for l := range n.reach(port, nil) {
+		reachable = append(reachable, l)
+	}
+	return reachable
+}
+
+// reach finds the set of nodes reachable from `n` on `port` without passing
+// through the nodes in `avoid`.
+func (n Node) reach(port int, avoid map[string]struct{}) map[string]struct{} {
+	explored := map[string]struct{}{}
 	reached := map[string]struct{}{}
 
 	var explore func(t Node)
 	explore = func(t Node) {
 		for label, node := range t.Connections {
-			_, explored := reached[label]
-			reached[label] = struct{}{}
-			if !explored && label != PublicInternetLabel {
+			if _, avoided := avoid[label]; avoided {
+				continue
+			}
+
+			if t.allows(label, port) {
+				reached[label] = struct{}{}
+			}
+
+			_, done := explored[label]
+			explored[label] = struct{}{}
+			if !done && label != PublicInternetLabel {
 				explore(node)
 			}
 		}
 	}
 	explore(n)
 
-	var reachable []string
-	for l := range reached {
-		reachable = append(reachable, string(l))
-	}
-
-	return reachable
+	return reached
 }
 
 // Find all nodes reachable from the given node.
@@ -204,28 +322,88 @@ func (n Node) dfsWithACL() []string {
 	return reachable
 }
 
-// Compute all the paths between two Nodes.
-func paths(start Node, end Node) ([][]string, bool) {
-	reach := start.dfs()
-	if !contains(reach, end.Name) {
-		return nil, false
-	}
-
-	var paths [][]string
-
-	var explore func(t Node, p []string)
-	explore = func(t Node, p []string) {
-		if t.Name == end.Name {
-			paths = append(paths, p)
-			return
+// pathTo finds the shortest path from `n` to the node named `end` whose last hop is
+// open on `port`, without passing through the nodes in `avoid`.  Like dfs, it
+// doesn't explore past the public internet.  It returns nil if there is no path.
+func (n Node) pathTo(end string, port int, avoid map[string]struct{}) []string {
+	prev := map[string]string{n.Name: ""}
+	queue := []Node{n}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+
+		var next []Node
+		for _, node := range t.Connections {
+			next = append(next, node)
 		}
+		sort.Sort(nodeSlice(next))
+
+		for _, node := range next {
+			if _, seen := prev[node.Name]; seen {
+				continue
+			} else if _, avoided := avoid[node.Name]; avoided {
+				continue
+			} else if node.Name == end && !t.allows(end, port) {
+				continue
+			}
+			prev[node.Name] = t.Name
+
+			if node.Name == end {
+				path := []string{end}
+				for name := t.Name; name != ""; name = prev[name] {
+					path = append([]string{name}, path...)
+				}
+				return path
+			}
 
-		for label, node := range t.Connections {
-			if !contains(p, label) { // Discount self-reachability.
-				explore(node, append(p, label))
+			if node.Name != PublicInternetLabel {
+				queue = append(queue, node)
 			}
 		}
 	}
-	explore(start, []string{start.Name})
-	return paths, true
+	return nil
+}
+
+// nodeStr describes the node named `name` for users, e.g. "container 3 (web)".
+func (g Graph) nodeStr(name string) string {
+	if name == PublicInternetLabel {
+		return "the public internet"
+	}
+	return "container " + g.pathNodeStr(name)
+}
+
+func (g Graph) pathNodeStr(name string) string {
+	if name == PublicInternetLabel {
+		return PublicInternetLabel
+	}
+	return fmt.Sprintf("%s (%s)", name, g.Nodes[name].Label)
+}
+
+// pathStr describes a path through the graph for users, e.g. "3 (web) -> 4 (db)".
+func (g Graph) pathStr(path []string) string {
+	var strs []string
+	for _, name := range path {
+		strs = append(strs, g.pathNodeStr(name))
+	}
+	return strings.Join(strs, " -> ")
+}
+
+// nodeSlice sorts nodes by container ID.
+type nodeSlice []Node
+
+func (nodes nodeSlice) Len() int {
+	return len(nodes)
+}
+
+func (nodes nodeSlice) Less(i, j int) bool {
+	l, lErr := strconv.Atoi(nodes[i].Name)
+	r, rErr := strconv.Atoi(nodes[j].Name)
+	if lErr != nil || rErr != nil {
+		return nodes[i].Name < nodes[j].Name
+	}
+	return l < r
+}
+
+func (nodes nodeSlice) Swap(i, j int) {
+	nodes[i], nodes[j] = nodes[j], nodes[i]
 }
@@ -2,6 +2,7 @@ package stitch
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -17,6 +18,11 @@ const (
 	reachACLInvariant = "reachACL"
 	// On-pathness (between): three arguments, <from> <to> <between>
 	betweenInvariant = "between"
+	// Reachability on a port (reachPort): two arguments, <from> <to>, and a port
+	reachPortInvariant = "reachPort"
+	// On-pathness on a port (betweenPort): three arguments, <from> <to>
+	// <between>, and a port
+	betweenPortInvariant = "betweenPort"
 	// Schedulability (enough): zero arguments
 	schedulabilityInvariant = "enough"
 )
@@ -28,10 +34,18 @@ const (
 
 type invariantError struct {
 	failer invariant
+
+	// A description of why the invariant failed, such as the containers that
+	// violated it.
+	counterexample string
 }
 
 func (invErr invariantError) Error() string {
-	return fmt.Sprintf("invariant failed: %s", invErr.failer)
+	msg := fmt.Sprintf("invariant failed: %s", invErr.failer)
+	if invErr.counterexample != "" {
+		msg += "\n    " + invErr.counterexample
+	}
+	return msg
 }
 
 // Even though `invariant` isn't exported, we have to export its fields so that
@@ -40,6 +54,7 @@ type invariant struct {
 	Form   invariantType
 	Target bool     // Desired answer to invariant question.
 	Nodes  []string // Nodes the invariant operates on.
+	Port   int      // The port connections must be open on, if any.
 }
 
 func (inv invariant) String() string {
@@ -48,173 +63,299 @@ func (inv invariant) String() string {
 	for _, node := range inv.Nodes {
 		tags = append(tags, fmt.Sprintf("%q", node))
 	}
+	if inv.Port != anyPort {
+		tags = append(tags, fmt.Sprintf("%d", inv.Port))
+	}
 	return strings.Join(tags, " ")
 }
 
-var formImpls map[invariantType]func(graph Graph, inv invariant) bool
+// Each form reports whether the invariant holds, and if it doesn't, a counterexample
+// explaining why.
+var formImpls map[invariantType]func(graph Graph, inv invariant) (bool, string)
 
 func init() {
-	formImpls = map[invariantType]func(graph Graph, inv invariant) bool{
+	formImpls = map[invariantType]func(graph Graph, inv invariant) (bool, string){
 		reachInvariant:          reachImpl,
 		neighborInvariant:       neighborImpl,
 		reachACLInvariant:       reachACLImpl,
 		betweenInvariant:        betweenImpl,
+		reachPortInvariant:      reachImpl,
+		betweenPortInvariant:    betweenImpl,
 		schedulabilityInvariant: schedulabilityImpl,
 	}
 }
 
-func checkInvariants(graph Graph, invs []invariant) error {
-	for _, asrt := range invs {
-		if val := formImpls[asrt.Form](graph, asrt); !val {
-			return invariantError{asrt}
+// A Violation is an invariant that doesn't hold, along with a counterexample
+// explaining why.
+type Violation struct {
+	Invariant      string
+	Counterexample string
+}
+
+// CheckInvariants evaluates the spec's invariants against `graph`, which may
+// describe a running deployment rather than the spec itself, and returns those that
+// fail.
+func (stitch Stitch) CheckInvariants(graph Graph) []Violation {
+	var violations []Violation
+	for _, result := range stitch.EvaluateInvariants(graph) {
+		if !result.Holds {
+			violations = append(violations, Violation{
+				Invariant:      result.Invariant,
+				Counterexample: result.Counterexample,
+			})
 		}
 	}
+	return violations
+}
 
-	return nil
+// An InvariantResult reports whether an invariant holds in a Graph.
+type InvariantResult struct {
+	Invariant      string
+	Holds          bool
+	Counterexample string
 }
 
-func reachImpl(graph Graph, inv invariant) bool {
-	var fromNodes []Node
-	var toNodes []Node
-	for _, node := range graph.Nodes {
-		if node.Label == inv.Nodes[0] {
-			fromNodes = append(fromNodes, node)
-		}
-		if node.Label == inv.Nodes[1] {
-			toNodes = append(toNodes, node)
+// EvaluateInvariants evaluates each of the spec's invariants against `graph`, in
+// the order they were asserted.
+func (stitch Stitch) EvaluateInvariants(graph Graph) []InvariantResult {
+	var results []InvariantResult
+	for _, inv := range stitch.ctx.Invariants {
+		ok, counterexample := formImpls[inv.Form](graph, inv)
+		results = append(results, InvariantResult{
+			Invariant:      inv.String(),
+			Holds:          ok,
+			Counterexample: counterexample,
+		})
+	}
+	return results
+}
+
+func checkInvariants(graph Graph, invs []invariant) error {
+	for _, asrt := range invs {
+		if ok, counterexample := formImpls[asrt.Form](graph, asrt); !ok {
+			return invariantError{asrt, counterexample}
 		}
 	}
 
-	for _, from := range fromNodes {
-		for _, to := range toNodes {
-			reachable := contains(from.dfs(), to.Name)
-			if reachable != inv.Target {
-				return false
+	return nil
+}
+
+// nodesWithLabels returns the nodes implementing each of `labels`, sorted by name so
+// that counterexamples are deterministic.
+func nodesWithLabels(graph Graph, labels ...string) [][]Node {
+	result := make([][]Node, len(labels))
+	for _, node := range graph.getNodes() {
+		for i, label := range labels {
+			if node.Label == label {
+				result[i] = append(result[i], node)
 			}
 		}
 	}
 
-	return true
+	for _, nodes := range result {
+		sort.Sort(nodeSlice(nodes))
+	}
+	return result
 }
 
-func neighborImpl(graph Graph, inv invariant) bool {
-	var fromNodes []Node
-	var toNodes []Node
-	for _, node := range graph.Nodes {
-		if node.Label == inv.Nodes[0] {
-			fromNodes = append(fromNodes, node)
-		}
-		if node.Label == inv.Nodes[1] {
-			toNodes = append(toNodes, node)
+func reachImpl(graph Graph, inv invariant) (bool, string) {
+	nodes := nodesWithLabels(graph, inv.Nodes[0], inv.Nodes[1])
+	for _, from := range nodes[0] {
+		for _, to := range nodes[1] {
+			reachable := contains(from.dfs(inv.Port), to.Name)
+			if reachable == inv.Target {
+				continue
+			}
+
+			if !reachable {
+				return false, fmt.Sprintf("%s can't reach %s%s",
+					graph.nodeStr(from.Name), graph.nodeStr(to.Name),
+					onPort(inv.Port))
+			}
+			return false, fmt.Sprintf("%s can reach %s%s: %s",
+				graph.nodeStr(from.Name), graph.nodeStr(to.Name),
+				onPort(inv.Port),
+				graph.pathStr(from.pathTo(to.Name, inv.Port, nil)))
 		}
 	}
 
-	for _, from := range fromNodes {
-		for _, to := range toNodes {
+	return true, ""
+}
+
+func neighborImpl(graph Graph, inv invariant) (bool, string) {
+	nodes := nodesWithLabels(graph, inv.Nodes[0], inv.Nodes[1])
+	for _, from := range nodes[0] {
+		for _, to := range nodes[1] {
 			_, isNeighbor := from.Connections[to.Name]
-			if isNeighbor != inv.Target {
-				return false
+			if isNeighbor == inv.Target {
+				continue
+			}
+
+			verb := "connects"
+			if !isNeighbor {
+				verb = "doesn't connect"
 			}
+			return false, fmt.Sprintf("%s %s directly to %s",
+				graph.nodeStr(from.Name), verb, graph.nodeStr(to.Name))
 		}
 	}
 
-	return true
+	return true, ""
 }
 
-func reachACLImpl(graph Graph, inv invariant) bool {
-	var fromNodes []Node
-	var toNodes []Node
-	for _, node := range graph.Nodes {
-		if node.Label == inv.Nodes[0] {
-			fromNodes = append(fromNodes, node)
-		}
-		if node.Label == inv.Nodes[1] {
-			toNodes = append(toNodes, node)
-		}
-	}
+func reachACLImpl(graph Graph, inv invariant) (bool, string) {
+	nodes := nodesWithLabels(graph, inv.Nodes[0], inv.Nodes[1])
+	for _, from := range nodes[0] {
+		for _, to := range nodes[1] {
+			reachable := contains(from.dfsWithACL(), to.Name)
+			if reachable == inv.Target {
+				continue
+			}
 
-	for _, from := range fromNodes {
-		for _, to := range toNodes {
-			if reachable := contains(from.dfsWithACL(),
-				to.Name); reachable != inv.Target {
-				return false
+			if !reachable {
+				return false, fmt.Sprintf("%s can't reach %s "+
+					"without passing through an ACL",
+					graph.nodeStr(from.Name), graph.nodeStr(to.Name))
 			}
+			return false, fmt.Sprintf("%s can reach %s without passing "+
+				"through an ACL", graph.nodeStr(from.Name),
+				graph.nodeStr(to.Name))
 		}
 	}
 
-	return true
+	return true, ""
 }
 
-func betweenImpl(graph Graph, inv invariant) bool {
-	var fromNodes []Node
-	var toNodes []Node
-	var betweenNodes []Node
-	for _, node := range graph.Nodes {
-		switch node.Label {
-		case inv.Nodes[0]:
-			fromNodes = append(fromNodes, node)
-		case inv.Nodes[1]:
-			toNodes = append(toNodes, node)
-		case inv.Nodes[2]:
-			betweenNodes = append(betweenNodes, node)
-		}
+// betweenImpl checks whether the paths between containers pass through the between
+// containers.  Enumerating the paths takes exponential time, so it relies on
+// reachability instead.  Every path passes through a between container iff the
+// destination is unreachable once they're removed.  A path passes through one if
+// the source reaches it without passing through the destination, and it reaches
+// the destination without passing through the source.  This rules out walks that
+// only reach a between container by looping back through the source, but may still
+// accept a walk whose two halves share some other container.
+func betweenImpl(graph Graph, inv invariant) (bool, string) {
+	nodes := nodesWithLabels(graph, inv.Nodes[0], inv.Nodes[1], inv.Nodes[2])
+	betweenNodes := nodes[2]
+
+	avoid := map[string]struct{}{}
+	for _, between := range betweenNodes {
+		avoid[between.Name] = struct{}{}
 	}
 
-	allPassed := true
-	for _, from := range fromNodes {
-		for _, to := range toNodes {
-			allPassed = allPassed && betweenPathsHelper(
-				betweenNodes,
-				from,
-				to,
-				inv.Target,
-			)
+	for _, from := range nodes[0] {
+		reach := from.reach(inv.Port, nil)
+		bypassReach := from.reach(inv.Port, avoid)
+
+		// What each between container reaches without looping back through
+		// the source.
+		avoidFrom := map[string]struct{}{from.Name: {}}
+		betweenReach := map[string]map[string]struct{}{}
+		if !inv.Target {
+			for _, between := range betweenNodes {
+				betweenReach[between.Name] = between.reach(inv.Port,
+					avoidFrom)
+			}
 		}
-	}
-	return allPassed
-}
 
-func betweenPathsHelper(betweenNodes []Node, from Node, to Node, target bool) bool {
-	paths, ok := paths(from, to)
-	if !ok {
-		// No path between source and dest.
-		return !target
-	}
+		for _, to := range nodes[1] {
+			if _, ok := reach[to.Name]; !ok {
+				if !inv.Target {
+					continue
+				}
+				return false, fmt.Sprintf("%s can't reach %s%s",
+					graph.nodeStr(from.Name), graph.nodeStr(to.Name),
+					onPort(inv.Port))
+			}
+
+			// Every path passes through its endpoints.
+			_, fromBetween := avoid[from.Name]
+			_, toBetween := avoid[to.Name]
+			endpointBetween := fromBetween || toBetween
+
+			if inv.Target {
+				if _, ok := bypassReach[to.Name]; endpointBetween || !ok {
+					continue
+				}
+
+				path := from.pathTo(to.Name, inv.Port, avoid)
+				return false, fmt.Sprintf("the path %s doesn't pass "+
+					"through %q", graph.pathStr(path), inv.Nodes[2])
+			}
+
+			var path []string
+			if endpointBetween {
+				path = from.pathTo(to.Name, inv.Port, nil)
+			}
 
-	if target { // A betweenNode must be in all paths.
-		allPaths := true
-	pathsAll:
-		for _, path := range paths {
+			avoidTo := map[string]struct{}{to.Name: {}}
+			relayReach := from.reach(anyPort, avoidTo)
 			for _, between := range betweenNodes {
-				if contains(path, between.Name) {
+				if path != nil {
 					break
-				} else {
-					allPaths = false
-					break pathsAll
+				}
+
+				_, relays := relayReach[between.Name]
+				_, reaches := betweenReach[between.Name][to.Name]
+				if relays && reaches {
+					path = pathThrough(from, between, to, inv.Port)
 				}
 			}
-		}
-		return allPaths
-	}
-	// A betweenNode must not be in any path.
-	noPaths := true
-pathsAny:
-	for _, path := range paths {
-		for _, between := range betweenNodes {
-			if contains(path, between.Name) {
-				noPaths = false
-				break pathsAny
+
+			if path != nil {
+				return false, fmt.Sprintf("the path %s passes through %q",
+					graph.pathStr(path), inv.Nodes[2])
 			}
 		}
 	}
-	return noPaths
+	return true, ""
+}
+
+// pathThrough joins a path from `from` to `between` to a path from `between` to
+// `to`, whose last hop is open on `port`.  It prefers a tail that avoids the
+// head's containers, so that the path doesn't repeat any.
+func pathThrough(from, between, to Node, port int) []string {
+	head := from.pathTo(between.Name, anyPort,
+		map[string]struct{}{to.Name: {}})
+
+	avoid := map[string]struct{}{}
+	for _, name := range head[:len(head)-1] {
+		avoid[name] = struct{}{}
+	}
+
+	tail := between.pathTo(to.Name, port, avoid)
+	if tail == nil {
+		tail = between.pathTo(to.Name, port,
+			map[string]struct{}{from.Name: {}})
+	}
+	return append(head, tail[1:]...)
+}
+
+// onPort describes the port an invariant is qualified with, if any.
+func onPort(port int) string {
+	if port == anyPort {
+		return ""
+	}
+	return fmt.Sprintf(" on port %d", port)
 }
 
-func schedulabilityImpl(graph Graph, inv invariant) bool {
-	machines := graph.Machines
-	avSets := graph.Availability
-	if _, ok := graph.Nodes["public"]; ok {
-		return len(machines) >= (len(avSets) - 1)
+func schedulabilityImpl(graph Graph, inv invariant) (bool, string) {
+	workers, unplaced := graph.schedule()
+	if len(unplaced) == 0 {
+		return true, ""
+	} else if len(workers) == 0 {
+		return false, "no Worker machines are declared"
+	}
+
+	var reasons []string
+	for _, node := range unplaced {
+		reason := "every Worker machine already hosts a container it must be " +
+			"kept apart from"
+		if !graph.fitsEmptyMachine(node, workers) {
+			reason = "no Worker machine satisfies its placement rules"
+		}
+		reasons = append(reasons, fmt.Sprintf("%s can't be placed: %s",
+			graph.nodeStr(node.Name), reason))
 	}
-	return len(machines) >= len(avSets)
+	return false, strings.Join(reasons, "; ")
 }
@@ -1,6 +1,9 @@
 package stitch
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -96,7 +99,8 @@ func TestFail(t *testing.T) {
 
 	deployment.assert(a.canReach(c), true);
 	deployment.assert(c.canReach(a), true);`
-	expectedFailure := `invariant failed: reach true "c" "a"`
+	expectedFailure := `invariant failed: reach true "c" "a"
+    container 3 (c) can't reach container 1 (a)`
 	if _, err := initSpec(stc); err == nil {
 		t.Errorf("got no error, expected %s", expectedFailure)
 	} else if err.Error() != expectedFailure {
@@ -127,6 +131,274 @@ func TestBetween(t *testing.T) {
 	}
 }
 
+func TestReachPort(t *testing.T) {
+	stc := `var web = new Service("web", [new Container("nginx")]);
+	var app = new Service("app", [new Container("ubuntu")]);
+	var db = new Service("db", [new Container("postgres")]);
+	publicInternet.connect(new PortRange(443, 444), web);
+	web.connect(8080, app);
+	app.connect(5432, db);
+
+	deployment.deploy([web, app, db]);
+
+	deployment.assert(publicInternet.canReachPort(web, 443), true);
+	deployment.assert(publicInternet.canReachPort(web, 80), false);
+	deployment.assert(publicInternet.canReachPort(db, 5432), true);
+	deployment.assert(web.canReachPort(db, 5432), true);
+	deployment.assert(web.canReachPort(db, 8080), false);
+	deployment.assert(app.canReachPort(web, 8080), false);
+	deployment.assert(db.betweenPort(web, app, 5432), true);
+	deployment.assert(app.betweenPort(web, db, 8080), false);`
+	_, err := initSpec(stc)
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = initSpec(stc + `deployment.assert(web.canReachPort(db, 3306), true);`)
+	exp := `invariant failed: reachPort true "web" "db" 3306
+    container 1 (web) can't reach container 3 (db) on port 3306`
+	if err == nil || err.Error() != exp {
+		t.Errorf("got error %v, expected %s", err, exp)
+	}
+
+	_, err = initSpec(stc + `deployment.assert(web.canReachPort(db, 5432), false);`)
+	exp = `invariant failed: reachPort false "web" "db" 5432
+    container 1 (web) can reach container 3 (db) on port 5432: ` +
+		`1 (web) -> 2 (app) -> 3 (db)`
+	if err == nil || err.Error() != exp {
+		t.Errorf("got error %v, expected %s", err, exp)
+	}
+
+	_, err = initSpec(stc + `deployment.assert(web.canReachPort(db, "http"), true);`)
+	exp = "reachPort requires a positive port"
+	if err == nil || !strings.Contains(err.Error(), exp) {
+		t.Errorf("got error %v, expected %s", err, exp)
+	}
+}
+
+func TestPortGraph(t *testing.T) {
+	t.Parallel()
+
+	g := Graph{
+		Nodes:        map[string]Node{},
+		Availability: []AvailabilitySet{{}},
+		Placement:    map[string][]string{},
+	}
+	a := g.addNode("1", "a", nil)
+	g.addNode("2", "b", nil)
+	g.addNode("3", "c", nil)
+	g.addConnection("a", "b", 80, 80)
+	g.addConnection("a", "c", 22, 22)
+	g.addConnection("b", "c", 1000, 2000)
+
+	if reach := a.dfs(22); len(reach) != 1 || reach[0] != "3" {
+		t.Errorf("bad reachability on port 22: %v", reach)
+	}
+	if reach := a.dfs(1500); len(reach) != 1 || reach[0] != "3" {
+		t.Errorf("bad reachability on port 1500: %v", reach)
+	}
+	if reach := a.dfs(443); len(reach) != 0 {
+		t.Errorf("bad reachability on port 443: %v", reach)
+	}
+
+	if path := a.pathTo("3", 1500, nil); !reflect.DeepEqual(path,
+		[]string{"1", "2", "3"}) {
+		t.Errorf("bad path on port 1500: %v", path)
+	}
+	if path := a.pathTo("3", anyPort, nil); !reflect.DeepEqual(path,
+		[]string{"1", "3"}) {
+		t.Errorf("bad path on any port: %v", path)
+	}
+	if path := a.pathTo("2", 22, nil); path != nil {
+		t.Errorf("expected no path on port 22, got %v", path)
+	}
+
+	avoid := map[string]struct{}{"2": {}}
+	if path := a.pathTo("3", 1500, avoid); path != nil {
+		t.Errorf("expected no path avoiding b, got %v", path)
+	}
+}
+
+func TestBetweenEndpoints(t *testing.T) {
+	t.Parallel()
+
+	stc := `var a = new Service("a", [new Container("ubuntu")]);
+	var b = new Service("b", [new Container("ubuntu")]);
+	var c = new Service("c", [new Container("ubuntu")]);
+	var d = new Service("d", [new Container("ubuntu")]);
+	a.connect(22, b);
+	b.connect(22, c);
+	c.connect(22, a);
+
+	deployment.deploy([a, b, c, d]);
+
+	deployment.assert(c.between(a, a), true);
+	deployment.assert(c.between(a, b), true);
+	deployment.assert(a.between(b, c), true);
+	deployment.assert(d.between(a, c), false);`
+	if _, err := initSpec(stc); err != nil {
+		t.Error(err)
+	}
+
+	// b is only reachable from a on a cycle back through a, so it isn't on any
+	// path from a to c.
+	stc = `var a = new Service("a", [new Container("ubuntu")]);
+	var b = new Service("b", [new Container("ubuntu")]);
+	var c = new Service("c", [new Container("ubuntu")]);
+	a.connect(22, b);
+	b.connect(22, a);
+	a.connect(22, c);
+
+	deployment.deploy([a, b, c]);
+
+	deployment.assert(c.between(a, b), false);`
+	if _, err := initSpec(stc); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCounterexample(t *testing.T) {
+	t.Parallel()
+
+	services := `var a = new Service("a", [new Container("ubuntu")]);
+	var b = new Service("b", [new Container("ubuntu")]);
+	var c = new Service("c", [new Container("ubuntu")]);
+	var d = new Service("d", [new Container("ubuntu")]);
+	a.connect(22, b);
+	a.connect(22, c);
+	b.connect(22, d);
+	c.connect(22, d);
+	c.annotate("ACL");
+	deployment.deploy([a, b, c, d]);
+	`
+
+	checkFailure := func(assertion, exp string) {
+		_, err := initSpec(services + assertion)
+		if err == nil {
+			t.Errorf("%s: got no error, expected %s", assertion, exp)
+			return
+		}
+
+		lines := strings.SplitN(err.Error(), "\n", 2)
+		if len(lines) != 2 || strings.TrimSpace(lines[1]) != exp {
+			t.Errorf("%s: got error %q, expected counterexample %q",
+				assertion, err, exp)
+		}
+	}
+
+	checkFailure("deployment.assert(d.canReach(a), true);",
+		"container 4 (d) can't reach container 1 (a)")
+	checkFailure("deployment.assert(a.canReach(d), false);",
+		"container 1 (a) can reach container 4 (d): 1 (a) -> 2 (b) -> 4 (d)")
+	checkFailure("deployment.assert(a.neighborOf(d), true);",
+		"container 1 (a) doesn't connect directly to container 4 (d)")
+	checkFailure("deployment.assert(a.neighborOf(b), false);",
+		"container 1 (a) connects directly to container 2 (b)")
+	checkFailure("deployment.assert(a.canReachACL(d), false);",
+		"container 1 (a) can reach container 4 (d) without passing through "+
+			"an ACL")
+	checkFailure("deployment.assert(d.between(a, b), true);",
+		`the path 1 (a) -> 3 (c) -> 4 (d) doesn't pass through "b"`)
+	checkFailure("deployment.assert(d.between(a, c), false);",
+		`the path 1 (a) -> 3 (c) -> 4 (d) passes through "c"`)
+	checkFailure("deployment.assert(a.between(d, b), true);",
+		"container 4 (d) can't reach container 1 (a)")
+	checkFailure(`a.place(new LabelRule(true, b));
+		deployment.deploy(new Machine({role: "Worker"}));
+		deployment.assert(enough, true);`,
+		"container 2 (b) can't be placed: every Worker machine already "+
+			"hosts a container it must be kept apart from")
+}
+
+func TestPlaceNodesDeterministic(t *testing.T) {
+	t.Parallel()
+
+	spec, err := initSpec(`var a = new Service("a", [new Container("ubuntu")]);
+	var b = new Service("b", [new Container("ubuntu")]);
+	var c = new Service("c", new Container("ubuntu").replicate(3));
+	a.place(new LabelRule(true, b));
+	c.place(new LabelRule(true, a));
+	deployment.deploy([a, b, c]);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sets := func() []string {
+		graph, err := InitializeGraph(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var strs []string
+		for _, av := range graph.Availability {
+			strs = append(strs, av.Str())
+		}
+		return strs
+	}
+
+	exp := sets()
+	for i := 0; i < 20; i++ {
+		if res := sets(); !reflect.DeepEqual(res, exp) {
+			t.Fatalf("Expected the same availability sets, got %v and %v",
+				exp, res)
+		}
+	}
+}
+
+func TestEnough(t *testing.T) {
+	t.Parallel()
+
+	services := `var a = new Service("a", new Container("ubuntu").replicate(2));
+	var b = new Service("b", [new Container("ubuntu")]);
+	deployment.deploy([a, b]);
+	deployment.assert(enough, true);
+	`
+
+	check := func(spec, exp string) {
+		_, err := initSpec(services + spec)
+		if exp == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", spec, err)
+			}
+			return
+		} else if err == nil {
+			t.Errorf("%s: got no error, expected %s", spec, exp)
+			return
+		}
+
+		lines := strings.SplitN(err.Error(), "\n", 2)
+		if len(lines) != 2 || strings.TrimSpace(lines[1]) != exp {
+			t.Errorf("%s: got error %q, expected counterexample %q",
+				spec, err, exp)
+		}
+	}
+
+	check("", "no Worker machines are declared")
+
+	amazon := `deployment.deploy(new Machine({provider: "Amazon", role: "Worker",
+		size: "m4.large"}).replicate(2));`
+	check(amazon, "")
+	check(amazon+`a.place(new LabelRule(true, a));`, "")
+	check(amazon+`a.place(new LabelRule(true, a));
+		b.place(new LabelRule(true, a));`,
+		"container 4 (b) can't be placed: every Worker machine already "+
+			"hosts a container it must be kept apart from")
+	check(amazon+`b.place(new MachineRule(false, {provider: "Google"}));`,
+		"container 4 (b) can't be placed: no Worker machine satisfies its "+
+			"placement rules")
+	check(amazon+`a.place(new MachineRule(true, {size: "m4.large"}));`,
+		"container 2 (a) can't be placed: no Worker machine satisfies its "+
+			"placement rules; container 3 (a) can't be placed: no Worker "+
+			"machine satisfies its placement rules")
+
+	// Rules about a machine's unknown region are assumed to hold.
+	check(amazon+`b.place(new MachineRule(false, {region: "us-west-1"}));`, "")
+
+	// Masters don't run containers.
+	check(`deployment.deploy(new Machine({provider: "Amazon", role: "Master"}));`,
+		"no Worker machines are declared")
+}
+
 func TestNoConnect(t *testing.T) {
 	t.Skip("wait for scheduler, use the new scheduling algorithm")
 	stc := `(label "a" (docker "ubuntu"))
@@ -204,3 +476,60 @@ func TestPlacementInvs(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// layeredGraph generates a graph of `layers` layers of `width` containers each, in
+// which every container connects to every container in the next layer.  The number
+// of paths through it grows exponentially with the number of layers.
+func layeredGraph(layers, width int) Graph {
+	g := NewGraph()
+	for layer := 0; layer < layers; layer++ {
+		label := fmt.Sprintf("layer%d", layer)
+		for i := 0; i < width; i++ {
+			g.AddContainer(fmt.Sprintf("%d", layer*width+i), label, nil)
+		}
+	}
+
+	for layer := 0; layer+1 < layers; layer++ {
+		for i := 0; i < width; i++ {
+			for j := 0; j < width; j++ {
+				g.AddEdge(fmt.Sprintf("%d", layer*width+i),
+					fmt.Sprintf("%d", (layer+1)*width+j), 80, 80)
+			}
+		}
+	}
+	return g
+}
+
+func benchmarkBetween(b *testing.B, layers, width int, target bool) {
+	g := layeredGraph(layers, width)
+	inv := invariant{
+		Form:   betweenInvariant,
+		Target: target,
+		Nodes: []string{"layer0", fmt.Sprintf("layer%d", layers-1),
+			fmt.Sprintf("layer%d", layers/2)},
+	}
+	if !target {
+		// Nothing connects to the first layer.
+		inv.Nodes[2] = "layer0"
+		inv.Nodes[0] = "layer1"
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, counterexample := betweenImpl(g, inv); !ok {
+			b.Fatalf("unexpected failure: %s", counterexample)
+		}
+	}
+}
+
+func BenchmarkBetween50(b *testing.B) {
+	benchmarkBetween(b, 10, 5, true)
+}
+
+func BenchmarkBetween200(b *testing.B) {
+	benchmarkBetween(b, 20, 10, true)
+}
+
+func BenchmarkNotBetween200(b *testing.B) {
+	benchmarkBetween(b, 20, 10, false)
+}
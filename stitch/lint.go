@@ -0,0 +1,449 @@
+package stitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/robertkrimen/otto"
+
+	"github.com/NetSys/quilt/catalog"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/util"
+)
+
+// A Diagnostic describes a problem that Lint found in a spec.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+}
+
+// The lint representation of a deployment.  Its fields are exported so that it can
+// be unmarshalled with `encoding/json`.
+type lintCtx struct {
+	Services []lintService
+	Machines []lintMachine
+}
+
+type lintService struct {
+	Name       string
+	Location   string
+	Deployed   bool
+	Containers int
+
+	// The ports the service's containers declared they listen on.
+	Ports []Range
+
+	Connections []lintConnection
+
+	// The number of connections from the public internet to the service.
+	IncomingPublic int
+
+	Job        bool
+	Placements []lintPlacement
+}
+
+type lintConnection struct {
+	To       string
+	MinPort  int
+	MaxPort  int
+	Location string
+}
+
+type lintPlacement struct {
+	Exclusive  bool
+	OtherLabel string
+	Provider   string
+	Size       string
+	Region     string
+	Location   string
+}
+
+type lintMachine struct {
+	Machine
+	Location string
+}
+
+// Lint evaluates the spec at `filename`, and reports mistakes that would otherwise
+// go unnoticed until it's run, if they're noticed at all.  An error is returned
+// only if the spec couldn't be read.
+func Lint(filename string, getter ImportGetter) ([]Diagnostic, error) {
+	specStr, err := util.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := newVM(getter)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := runSpec(vm, filename, specStr); err != nil {
+		return []Diagnostic{errorDiagnostic(filename, err)}, nil
+	}
+
+	if _, err := fromVM(vm, specStr); err != nil {
+		return []Diagnostic{errorDiagnostic(filename, err)}, nil
+	}
+
+	ctx, err := parseLintContext(vm)
+	if err != nil {
+		return []Diagnostic{errorDiagnostic(filename, err)}, nil
+	}
+
+	var diags []Diagnostic
+	diags = append(diags, ctx.lintMachines(filename)...)
+	diags = append(diags, ctx.lintServices(filename)...)
+	diags = append(diags, ctx.lintPlacements(filename)...)
+	diags = append(diags, ctx.lintConnections(filename)...)
+
+	sort.Sort(diagnosticSlice(diags))
+	return diags, nil
+}
+
+func parseLintContext(vm *otto.Otto) (ctx lintCtx, err error) {
+	vmCtx, err := vm.Run("deployment.toLintRepresentation()")
+	if err != nil {
+		return ctx, err
+	}
+
+	exp, _ := vmCtx.Export()
+	ctxStr, err := json.Marshal(exp)
+	if err != nil {
+		return ctx, err
+	}
+	err = json.Unmarshal(ctxStr, &ctx)
+	return ctx, err
+}
+
+func (ctx lintCtx) lintMachines(filename string) []Diagnostic {
+	var diags []Diagnostic
+	var master, worker *lintMachine
+	for i, m := range ctx.Machines {
+		report := func(format string, args ...interface{}) {
+			diags = append(diags, newDiagnostic(filename, m.Location,
+				fmt.Sprintf(format, args...)))
+		}
+
+		role, err := db.ParseRole(m.Role)
+		if err != nil {
+			report("unknown role %q", m.Role)
+		} else if role == db.Master && master == nil {
+			master = &ctx.Machines[i]
+		} else if role == db.Worker && worker == nil {
+			worker = &ctx.Machines[i]
+		}
+
+		provider, err := db.ParseProvider(m.Provider)
+		if err != nil {
+			report("unknown provider %q", m.Provider)
+			continue
+		}
+
+		if m.Size != "" && !sizeOffered(provider, m.Size) {
+			report("size %q is not offered by %s", m.Size, provider)
+		}
+
+		if _, err := db.ParseMarket(provider, m.Market); err != nil {
+			report("market %q is not offered by %s", m.Market, provider)
+		}
+
+		if m.Host != "" && provider != db.Static {
+			report("hosts may only be given for Static machines")
+		}
+
+		prefs := strings.Join(m.SizePreferences(), ", ")
+		switch {
+		case prefs == "":
+		case m.Size != "":
+			report("size preferences (%s) are ignored, as the size is given",
+				prefs)
+		case provider == db.Vagrant || provider == db.Static ||
+			provider == db.Local:
+			report("%s ignores size preferences (%s)", provider, prefs)
+		}
+
+		for _, family := range append(m.Families, m.ExcludeFamilies...) {
+			if !familyOffered(provider, family) {
+				report("%s offers no sizes in family %q", provider,
+					family)
+			}
+		}
+	}
+
+	if master != nil && worker == nil {
+		diags = append(diags, newDiagnostic(filename, master.Location,
+			"a Master was specified but no Workers, so no machines "+
+				"will boot"))
+	} else if worker != nil && master == nil {
+		diags = append(diags, newDiagnostic(filename, worker.Location,
+			"a Worker was specified but no Masters, so no machines "+
+				"will boot"))
+	}
+
+	return diags
+}
+
+// sizeOffered returns false if `provider` is known not to offer `size`.  Providers
+// missing from the catalog are assumed to accept any size.
+func sizeOffered(provider db.Provider, size string) bool {
+	descriptions := catalog.Get(provider)
+	if len(descriptions) == 0 {
+		return true
+	}
+
+	for _, desc := range descriptions {
+		if desc.Size == size {
+			return true
+		}
+	}
+	return false
+}
+
+// familyOffered returns false if `provider` is known not to offer any sizes in
+// `family`.
+func familyOffered(provider db.Provider, family string) bool {
+	descriptions := catalog.Get(provider)
+	if len(descriptions) == 0 {
+		return true
+	}
+
+	for _, desc := range descriptions {
+		if catalog.InFamilies(desc.Family, []string{family}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx lintCtx) lintServices(filename string) []Diagnostic {
+	reachable := map[string]bool{}
+	for _, svc := range ctx.Services {
+		if svc.IncomingPublic > 0 {
+			reachable[svc.Name] = true
+		}
+		for _, conn := range svc.Connections {
+			reachable[conn.To] = true
+		}
+	}
+
+	var diags []Diagnostic
+	for _, svc := range ctx.Services {
+		switch {
+		case !svc.Deployed:
+			diags = append(diags, newDiagnostic(filename, svc.Location,
+				fmt.Sprintf("service %q is never deployed", svc.Name)))
+
+		// Jobs, and services that don't listen on any ports, may only make
+		// outbound connections, so only listening services are expected to
+		// be reachable.
+		case !reachable[svc.Name] && len(svc.Ports) > 0 && !svc.Job:
+			diags = append(diags, newDiagnostic(filename, svc.Location,
+				fmt.Sprintf("service %q is unreachable: its "+
+					"containers listen on ports, but nothing "+
+					"connects to it", svc.Name)))
+		}
+	}
+	return diags
+}
+
+func (ctx lintCtx) lintPlacements(filename string) []Diagnostic {
+	var workers []lintMachine
+	for _, m := range ctx.Machines {
+		if m.Role == string(db.Worker) {
+			workers = append(workers, m)
+		}
+	}
+
+	// Without any workers there's nothing to check the placement rules against.
+	if len(workers) == 0 {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for _, svc := range ctx.Services {
+		if !svc.Deployed {
+			continue
+		}
+
+		for _, plcm := range svc.Placements {
+			report := func(format string, args ...interface{}) {
+				diags = append(diags, newDiagnostic(filename,
+					plcm.Location, fmt.Sprintf(format, args...)))
+			}
+
+			if plcm.OtherLabel != "" {
+				if plcm.Exclusive && plcm.OtherLabel == svc.Name &&
+					svc.Containers > len(workers) {
+					report("%q has %d containers that must each be "+
+						"on a separate machine, but only %d "+
+						"Workers are declared", svc.Name,
+						svc.Containers, len(workers))
+				}
+				continue
+			}
+
+			var matches int
+			var undecided bool
+			for _, m := range workers {
+				undecided = undecided || !plcm.decidable(m)
+				if plcm.matches(m) {
+					matches++
+				}
+			}
+
+			if undecided {
+				continue
+			} else if !plcm.Exclusive && matches == 0 {
+				report("no Worker machine is %s, as the placement of "+
+					"%q requires", plcm.describe(), svc.Name)
+			} else if plcm.Exclusive && matches == len(workers) {
+				report("every Worker machine is %s, which the "+
+					"placement of %q forbids", plcm.describe(),
+					svc.Name)
+			}
+		}
+	}
+	return diags
+}
+
+func (plcm lintPlacement) matches(m lintMachine) bool {
+	return (plcm.Provider == "" || plcm.Provider == m.Provider) &&
+		(plcm.Size == "" || plcm.Size == m.Size) &&
+		(plcm.Region == "" || plcm.Region == m.Region)
+}
+
+// decidable returns false if the rule depends on a size or region that won't be
+// chosen for `m` until it boots.
+func (plcm lintPlacement) decidable(m lintMachine) bool {
+	return (plcm.Size == "" || m.Size != "") &&
+		(plcm.Region == "" || m.Region != "")
+}
+
+func (plcm lintPlacement) describe() string {
+	var attrs []string
+	if plcm.Provider != "" {
+		attrs = append(attrs, "provider "+plcm.Provider)
+	}
+	if plcm.Size != "" {
+		attrs = append(attrs, "size "+plcm.Size)
+	}
+	if plcm.Region != "" {
+		attrs = append(attrs, "region "+plcm.Region)
+	}
+	return strings.Join(attrs, ", ")
+}
+
+func (ctx lintCtx) lintConnections(filename string) []Diagnostic {
+	ports := map[string][]Range{}
+	for _, svc := range ctx.Services {
+		ports[svc.Name] = svc.Ports
+	}
+
+	var diags []Diagnostic
+	for _, svc := range ctx.Services {
+		if !svc.Deployed {
+			continue
+		}
+
+		for _, conn := range svc.Connections {
+			// Containers that don't declare their ports might listen on
+			// any of them.
+			listening := ports[conn.To]
+			if len(listening) == 0 {
+				continue
+			}
+
+			var open bool
+			for _, r := range listening {
+				min, max := int(r.Min), int(r.Max)
+				if min <= conn.MaxPort && conn.MinPort <= max {
+					open = true
+					break
+				}
+			}
+
+			if !open {
+				msg := fmt.Sprintf("%q connects to %q on %s, but no "+
+					"%q container listens there", svc.Name, conn.To,
+					portsStr(conn.MinPort, conn.MaxPort), conn.To)
+				diags = append(diags, newDiagnostic(filename,
+					conn.Location, msg))
+			}
+		}
+	}
+	return diags
+}
+
+func portsStr(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("port %d", min)
+	}
+	return fmt.Sprintf("ports %d-%d", min, max)
+}
+
+// newDiagnostic creates a Diagnostic at `location`, a "file:line" string recorded
+// by the bindings.  If the location is missing, the diagnostic refers to `filename`
+// as a whole.
+func newDiagnostic(filename, location, msg string) Diagnostic {
+	diag := Diagnostic{File: filename, Message: msg}
+	if i := strings.LastIndex(location, ":"); i >= 0 {
+		if line, err := strconv.Atoi(location[i+1:]); err == nil {
+			diag.File = location[:i]
+			diag.Line = line
+		}
+	}
+	return diag
+}
+
+var framePattern = regexp.MustCompile(`([^ ()]+:\d+):\d+\)?$`)
+
+// errorDiagnostic converts an error raised while evaluating a spec into a
+// Diagnostic.  Otto errors carry a stack trace, which is used to find the line of
+// the spec, rather than of the bindings, that caused the error.
+func errorDiagnostic(filename string, err error) Diagnostic {
+	ottoErr, ok := err.(*otto.Error)
+	if !ok {
+		return Diagnostic{File: filename, Message: err.Error()}
+	}
+
+	for _, frame := range strings.Split(ottoErr.String(), "\n")[1:] {
+		match := framePattern.FindStringSubmatch(strings.TrimSpace(frame))
+		if match != nil && !strings.HasPrefix(match[1], "<javascript_bindings>") {
+			return newDiagnostic(filename, match[1], ottoErr.Error())
+		}
+	}
+	return Diagnostic{File: filename, Message: ottoErr.Error()}
+}
+
+type diagnosticSlice []Diagnostic
+
+func (diags diagnosticSlice) Len() int {
+	return len(diags)
+}
+
+func (diags diagnosticSlice) Less(i, j int) bool {
+	l, r := diags[i], diags[j]
+	if l.File != r.File {
+		return l.File < r.File
+	}
+	if l.Line != r.Line {
+		return l.Line < r.Line
+	}
+	return l.Message < r.Message
+}
+
+func (diags diagnosticSlice) Swap(i, j int) {
+	diags[i], diags[j] = diags[j], diags[i]
+}
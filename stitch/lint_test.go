@@ -0,0 +1,163 @@
+package stitch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/NetSys/quilt/util"
+)
+
+func checkLint(t *testing.T, spec string, exp []string) {
+	util.AppFs = afero.NewMemMapFs()
+	util.WriteFile("spec.js", []byte(spec), 0644)
+
+	diags, err := Lint("spec.js", ImportGetter{Path: "."})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	var strs []string
+	for _, diag := range diags {
+		strs = append(strs, diag.String())
+	}
+
+	if !reflect.DeepEqual(strs, exp) {
+		t.Errorf("Bad diagnostics:\nGot: %v\nExp: %v", strs, exp)
+	}
+}
+
+func TestLintMachines(t *testing.T) {
+	checkLint(t, `var master = new Machine({provider: "Amazon", role: "Master",
+		size: "m4.large"});
+	deployment.deploy([master,
+		new Machine({provider: "Amazon", role: "Worker", size: "huge"}),
+		new Machine({provider: "Azure", role: "Worker"}),
+		new Machine({provider: "Vagrant", role: "Boss"}),
+		new Machine({provider: "Amazon", role: "Worker", market: "ondemand"}),
+		new Machine({provider: "Amazon", role: "Worker", market: "reserved"}),
+		new Machine({provider: "Google", role: "Worker", market: "spot"}),
+		new Machine({provider: "Static", role: "Worker", host: "10.0.0.5"}),
+		new Machine({provider: "Amazon", role: "Worker", host: "10.0.0.6"})]);`,
+		[]string{
+			`spec.js:4: size "huge" is not offered by Amazon`,
+			`spec.js:5: unknown provider "Azure"`,
+			`spec.js:6: unknown role "Boss"`,
+			`spec.js:8: market "reserved" is not offered by Amazon`,
+			`spec.js:9: market "spot" is not offered by Google`,
+			`spec.js:11: hosts may only be given for Static machines`,
+		})
+
+	checkLint(t, `deployment.deploy(
+		new Machine({provider: "Amazon", role: "Master"}));`,
+		[]string{"spec.js:2: a Master was specified but no Workers, so no " +
+			"machines will boot"})
+
+	checkLint(t, `deployment.deploy([
+		new Machine({provider: "Amazon", role: "Master", families: ["m4"]}),
+		new Machine({provider: "Amazon", role: "Worker", size: "m4.large",
+			localSSD: true}),
+		new Machine({provider: "Vagrant", role: "Worker", minNetwork: 1}),
+		new Machine({provider: "Amazon", role: "Worker",
+			excludeFamilies: ["zz9"]})]);`,
+		[]string{
+			`spec.js:3: size preferences (localSSD) are ignored, as the ` +
+				`size is given`,
+			`spec.js:5: Vagrant ignores size preferences (minNetwork)`,
+			`spec.js:6: Amazon offers no sizes in family "zz9"`,
+		})
+}
+
+func TestLintServices(t *testing.T) {
+	checkLint(t, `var server = new Container("image").listen(80);
+	var a = new Service("a", []);
+	var b = new Service("b", [server]);
+	var c = new Service("c", [server.clone()]);
+	var d = new Service("d", [server.clone()]);
+	a.connect(80, b);
+	publicInternet.connect(80, d);
+
+	// Neither services without ports, nor jobs, are expected to be reachable.
+	var worker = new Service("worker", [new Container("image")]);
+	worker.connect(443, publicInternet);
+	var migrate = new Job("migrate", new Container("image").listen(80));
+	deployment.deploy([a, b, c, d, worker, migrate]);
+	var unused = new Service("unused", []);`,
+		[]string{
+			`spec.js:4: service "c" is unreachable: its containers listen ` +
+				`on ports, but nothing connects to it`,
+			`spec.js:14: service "unused" is never deployed`,
+		})
+}
+
+func TestLintPlacements(t *testing.T) {
+	checkLint(t, `var machine = new Machine({provider: "Amazon", size: "m4.large"});
+	deployment.deploy([machine.asMaster(), machine.asWorker()]);
+	var a = new Service("a", new Container("image").replicate(2));
+	a.connect(80, a);
+	a.place(new MachineRule(false, {provider: "Google"}));
+	a.place(new MachineRule(true, {size: "m4.large"}));
+	a.place(new MachineRule(false, {provider: "Amazon"}));
+	a.place(new LabelRule(true, a));
+	a.place(new MachineRule(false, {region: "us-west-1"}));
+	deployment.deploy(a);`,
+		[]string{
+			`spec.js:5: no Worker machine is provider Google, as the ` +
+				`placement of "a" requires`,
+			`spec.js:6: every Worker machine is size m4.large, which the ` +
+				`placement of "a" forbids`,
+			`spec.js:8: "a" has 2 containers that must each be on a ` +
+				`separate machine, but only 1 Workers are declared`,
+		})
+}
+
+func TestLintConnections(t *testing.T) {
+	checkLint(t, `var web = new Service("web",
+		[new Container("nginx").listen(80).listen(new PortRange(8000, 8080))]);
+	var db = new Service("db", [new Container("mysql")]);
+	web.connect(8080, web);
+	web.connect(443, web);
+	web.connect(new PortRange(70, 79), web);
+	web.connect(3306, db);
+	deployment.deploy([web, db]);`,
+		[]string{
+			`spec.js:5: "web" connects to "web" on port 443, but no "web" ` +
+				`container listens there`,
+			`spec.js:6: "web" connects to "web" on ports 70-79, but no ` +
+				`"web" container listens there`,
+		})
+}
+
+func TestLintErrors(t *testing.T) {
+	checkLint(t, `var a = new Service("a", []);
+
+	a.bogus();`,
+		[]string{"spec.js:3: TypeError: 'bogus' is not a function"})
+
+	checkLint(t, `var a = new Service("a", []);
+	a.connect(80, new Service("b", []));
+	deployment.deploy(a);`,
+		[]string{"spec.js: a has a connection to undeployed service: b"})
+
+	util.AppFs = afero.NewMemMapFs()
+	if _, err := Lint("missing.js", ImportGetter{Path: "."}); err == nil {
+		t.Error("Expected an error linting a missing file")
+	}
+}
+
+func TestErrorDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	diag := errorDiagnostic("spec.js", errors.New("oops"))
+	if exp := (Diagnostic{File: "spec.js", Message: "oops"}); diag != exp {
+		t.Errorf("Bad diagnostic: %v, expected %v", diag, exp)
+	}
+
+	diag = newDiagnostic("spec.js", "import.js:12", "msg")
+	if exp := (Diagnostic{File: "import.js", Line: 12, Message: "msg"}); diag != exp {
+		t.Errorf("Bad diagnostic: %v, expected %v", diag, exp)
+	}
+}
@@ -2,17 +2,19 @@ package stitch
 
 import (
 	"fmt"
+	"sort"
 )
 
 // AvailabilitySet represents a set of containers which can be placed together on a VM.
 type AvailabilitySet map[string]struct{}
 
-// Nodes returns the membership of the set.
+// Nodes returns the membership of the set, sorted.
 func (avSet AvailabilitySet) Nodes() []string {
 	var labels []string
 	for l := range avSet {
 		labels = append(labels, l)
 	}
+	sort.Strings(labels)
 	return labels
 }
 
@@ -142,12 +144,22 @@ func validateRule(place Placement, g Graph) ([]string, []string) {
 //   - If so, remove those nodes from the availability set.
 //   - For each removed node, try to find another availability set to move it to.
 //   - Create a new set for nodes that could not be moved to an existing set.
+//
+// The nodes are placed in sorted order so that the sets don't depend on map
+// iteration order.
 func (g *Graph) placeNodes() {
-	for node, wantExclusives := range g.Placement {
+	var targets []string
+	for node := range g.Placement {
+		targets = append(targets, node)
+	}
+	sort.Strings(targets)
+
+	for _, node := range targets {
+		wantExclusives := g.Placement[node]
 		if _, ok := g.Nodes[node]; !ok {
 			panic(
 				fmt.Errorf(
-					"invalid node: %s, nodes: %s",
+					"invalid node: %s, nodes: %v",
 					node,
 					g.getNodes(),
 				),
@@ -159,6 +171,7 @@ func (g *Graph) placeNodes() {
 			panic(fmt.Errorf("could not find availability set: %s", node))
 		}
 		toMove := av.removeAndCheck(wantExclusives...)
+		sort.Strings(toMove)
 
 		for ind, move := range toMove {
 			avoids := g.Placement[move]
@@ -190,3 +203,114 @@ func (g *Graph) placeNodes() {
 		}
 	}
 }
+
+// A machine being filled in by `schedule`.
+type scheduledMachine struct {
+	Machine
+	nodes []Node
+}
+
+// schedule places the Graph's containers on its Worker machines, using the rules
+// and the least-loaded-first strategy of the minion scheduler.  Containers that are
+// already placed stay where they are.  It returns the Workers, and the containers
+// that couldn't be placed on any of them.
+func (g Graph) schedule() (workers []*scheduledMachine, unplaced []Node) {
+	byIndex := map[int]*scheduledMachine{}
+	for i, m := range g.Machines {
+		if m.Role == "Worker" {
+			byIndex[i] = &scheduledMachine{Machine: m}
+			workers = append(workers, byIndex[i])
+		}
+	}
+
+	var nodes []Node
+	for _, node := range g.Nodes {
+		if node.Name == PublicInternetLabel {
+			continue
+		}
+
+		if i, ok := g.Placed[node.Name]; ok && byIndex[i] != nil {
+			byIndex[i].nodes = append(byIndex[i].nodes, node)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Sort(nodeSlice(nodes))
+
+	for _, node := range nodes {
+		var best *scheduledMachine
+		for _, m := range workers {
+			if (best == nil || len(m.nodes) < len(best.nodes)) &&
+				g.validPlacement(*m, node) {
+				best = m
+			}
+		}
+
+		if best == nil {
+			unplaced = append(unplaced, node)
+			continue
+		}
+		best.nodes = append(best.nodes, node)
+	}
+	return workers, unplaced
+}
+
+// fitsEmptyMachine returns true if `node` could be placed on one of `workers` were
+// it not for the containers already there.
+func (g Graph) fitsEmptyMachine(node Node, workers []*scheduledMachine) bool {
+	for _, m := range workers {
+		if g.validPlacement(scheduledMachine{Machine: m.Machine}, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// validPlacement mirrors the minion scheduler's function of the same name.  The
+// size and region of a machine may not be known until it boots, in which case
+// rules about them are assumed to hold.
+func (g Graph) validPlacement(m scheduledMachine, node Node) bool {
+	for _, constraint := range g.Constraints {
+		if constraint.Exclusive && constraint.OtherLabel != "" {
+			for _, peer := range m.nodes {
+				if peer.Name == node.Name {
+					continue
+				}
+
+				if constraint.TargetLabel == node.Label &&
+					constraint.OtherLabel == peer.Label ||
+					constraint.TargetLabel == peer.Label &&
+						constraint.OtherLabel == node.Label {
+					return false
+				}
+			}
+		}
+
+		if constraint.TargetLabel != node.Label {
+			continue
+		}
+
+		if constraint.Provider != "" {
+			on := constraint.Provider == m.Provider
+			if constraint.Exclusive == on {
+				return false
+			}
+		}
+
+		if constraint.Region != "" && m.Region != "" {
+			on := constraint.Region == m.Region
+			if constraint.Exclusive == on {
+				return false
+			}
+		}
+
+		if constraint.Size != "" && m.Size != "" {
+			on := constraint.Size == m.Size
+			if constraint.Exclusive == on {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -5,6 +5,7 @@ package stitch
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/robertkrimen/otto"
 
@@ -50,6 +51,58 @@ type Label struct {
 	Name        string
 	IDs         []int
 	Annotations []string
+
+	// UpdateStrategy is nil if the label's containers should all be replaced
+	// at once.
+	UpdateStrategy *UpdateStrategy
+
+	// Job is nil unless the label's containers run to completion, rather than
+	// being restarted whenever they exit.
+	Job *Job
+
+	// The labels whose containers must be running before this label's
+	// containers may start.
+	Dependencies []Dependency
+
+	// Protected labels may not be removed from a running deployment.
+	Protected bool
+}
+
+// A Dependency holds back the containers of a label until a container of Label is
+// running, or if UntilHealthy is set, until all of them are.
+type Dependency struct {
+	Label        string
+	UntilHealthy bool
+}
+
+// An UpdateStrategy governs how the containers of a label are replaced when their
+// image, command, or environment change.
+type UpdateStrategy struct {
+	// The number of containers that may be unavailable during the update.
+	MaxUnavailable int
+
+	// The number of containers that may be started beyond the label's size.
+	MaxSurge int
+
+	// The number of seconds to wait between batches.
+	Pause int
+}
+
+// A Job describes how the containers of a label that run to completion are
+// scheduled.
+type Job struct {
+	// The number of containers that must complete successfully.
+	Completions int
+
+	// The number of containers that may run at once.
+	Parallelism int
+
+	// The number of times a failed container is retried.
+	BackoffLimit int
+
+	// The number of seconds to wait before the first retry.  The wait doubles
+	// with each retry after that.
+	Backoff int
 }
 
 // A Connection allows containers implementing the From label to speak to containers
@@ -59,6 +112,10 @@ type Connection struct {
 	To      string
 	MinPort int
 	MaxPort int
+
+	// CidrIP restricts connections with the public internet to the given
+	// block of addresses.  An empty CidrIP places no restriction.
+	CidrIP string
 }
 
 // A ConnectionSlice allows for slices of Collections to be used in joins
@@ -71,9 +128,66 @@ type Machine struct {
 	Size     string
 	CPU      Range
 	RAM      Range
+	GPU      Range
 	DiskSize int
 	Region   string
 	SSHKeys  []string
+
+	// The purchasing option, such as "spot" or "ondemand".  Only Amazon
+	// offers markets, and an empty Market means spot.
+	Market string
+
+	// The most to pay for the machine per hour, which is also the bid for spot
+	// machines.  Zero means the deployment's MaxPrice.
+	MaxPrice float64
+
+	// The image to boot from instead of the provider's default, such as an AMI
+	// on Amazon, and a script to run once the machine has booted.
+	Image           string
+	BootScriptExtra string
+
+	// The "[user@]address" of the server a Static machine runs on.  Static
+	// machines without a Host run on any free server in the inventory.
+	Host string
+
+	// Protected machines may not be terminated.
+	Protected bool
+
+	// Preferences for the size chosen when none is given.  Families are groups
+	// of sizes such as "m4" on Amazon, or "n1-highmem" on Google, and MinNetwork
+	// is in Gbps.  By default, the cheapest size that fits is chosen, but
+	// PreferPreviousGeneration chooses an outdated one if any fits, as they're
+	// often cheaper on the spot market.
+	LocalSSD                 bool
+	MinNetwork               float64
+	Families                 []string
+	ExcludeFamilies          []string
+	PreferPreviousGeneration bool
+}
+
+// SizePreferences returns the names of the preferences, other than for RAM and
+// CPUs, that `m` has for its size.
+func (m Machine) SizePreferences() []string {
+	var prefs []string
+	if m.GPU != (Range{}) {
+		prefs = append(prefs, "gpu")
+	}
+	if m.LocalSSD {
+		prefs = append(prefs, "localSSD")
+	}
+	if m.MinNetwork != 0 {
+		prefs = append(prefs, "minNetwork")
+	}
+	if len(m.Families) != 0 {
+		prefs = append(prefs, "families")
+	}
+	if len(m.ExcludeFamilies) != 0 {
+		prefs = append(prefs, "excludeFamilies")
+	}
+	if m.PreferPreviousGeneration {
+		prefs = append(prefs, "preferPreviousGeneration")
+	}
+	return prefs
 }
 
 // A Range defines a range of acceptable values for a Machine attribute
@@ -177,6 +291,27 @@ func FromFile(filename string, getter ImportGetter) (Stitch, error) {
 	return New(compiled, getter)
 }
 
+// FromFileUnchecked is like FromFile, except that it doesn't fail if the spec's
+// invariants don't hold.  It's meant for tools, such as inspect, that report the
+// invariants' results themselves.
+func FromFileUnchecked(filename string, getter ImportGetter) (Stitch, error) {
+	compiled, err := Compile(filename, getter)
+	if err != nil {
+		return Stitch{}, err
+	}
+
+	vm, err := newVM(getter)
+	if err != nil {
+		return Stitch{}, err
+	}
+
+	if _, err := runSpec(vm, "<raw_string>", compiled); err != nil {
+		return Stitch{}, err
+	}
+
+	return parseSpec(vm, compiled)
+}
+
 // New parses and executes a stitch (in text form), and returns an abstract Dsl handle.
 func New(specStr string, getter ImportGetter) (Stitch, error) {
 	vm, err := newVM(getter)
@@ -188,18 +323,18 @@ func New(specStr string, getter ImportGetter) (Stitch, error) {
 		return Stitch{}, err
 	}
 
-	ctx, err := parseContext(vm)
+	return fromVM(vm, specStr)
+}
+
+// fromVM builds a Stitch from a VM that has already evaluated `specStr`, and checks
+// it for dependency cycles and invariant violations.
+func fromVM(vm *otto.Otto, specStr string) (Stitch, error) {
+	spec, err := parseSpec(vm, specStr)
 	if err != nil {
 		return Stitch{}, err
 	}
-	ctx.createPortRules()
-
-	spec := Stitch{
-		code: specStr,
-		ctx:  &ctx,
-	}
 
-	if len(ctx.Invariants) == 0 {
+	if len(spec.ctx.Invariants) == 0 {
 		return spec, nil
 	}
 
@@ -208,13 +343,29 @@ func New(specStr string, getter ImportGetter) (Stitch, error) {
 		return Stitch{}, err
 	}
 
-	if err := checkInvariants(graph, ctx.Invariants); err != nil {
+	if err := checkInvariants(graph, spec.ctx.Invariants); err != nil {
 		return Stitch{}, err
 	}
 
 	return spec, nil
 }
 
+// parseSpec builds a Stitch from a VM that has already evaluated `specStr`, and
+// checks it for dependency cycles.
+func parseSpec(vm *otto.Otto, specStr string) (Stitch, error) {
+	ctx, err := parseContext(vm)
+	if err != nil {
+		return Stitch{}, err
+	}
+	ctx.createPortRules()
+
+	if err := checkDependencies(ctx.Labels); err != nil {
+		return Stitch{}, err
+	}
+
+	return Stitch{code: specStr, ctx: &ctx}, nil
+}
+
 func parseContext(vm *otto.Otto) (ctx evalCtx, err error) {
 	vmCtx, err := vm.Run("deployment.toQuiltRepresentation()")
 	if err != nil {
@@ -233,9 +384,14 @@ func parseContext(vm *otto.Otto) (ctx evalCtx, err error) {
 }
 
 // createPortRules creates exclusive placement rules such that no two containers
-// listening on the same public port get placed on the same machine.
+// listening on overlapping public port ranges get placed on the same machine.
 func (ctx *evalCtx) createPortRules() {
-	ports := make(map[int][]string)
+	type publicPorts struct {
+		label    string
+		min, max int
+	}
+
+	var ports []publicPorts
 	for _, c := range ctx.Connections {
 		if c.From != PublicInternetLabel && c.To != PublicInternetLabel {
 			continue
@@ -246,22 +402,82 @@ func (ctx *evalCtx) createPortRules() {
 			target = c.To
 		}
 
-		min := c.MinPort
-		ports[min] = append(ports[min], target)
+		ports = append(ports, publicPorts{target, c.MinPort, c.MaxPort})
 	}
 
-	for _, labels := range ports {
-		for _, tgt := range labels {
-			for _, other := range labels {
-				ctx.Placements = append(ctx.Placements,
-					Placement{
-						Exclusive:   true,
-						TargetLabel: tgt,
-						OtherLabel:  other,
-					})
+	placed := make(map[Placement]struct{})
+	for _, tgt := range ports {
+		for _, other := range ports {
+			if tgt.max < other.min || other.max < tgt.min {
+				continue
+			}
+
+			plcm := Placement{
+				Exclusive:   true,
+				TargetLabel: tgt.label,
+				OtherLabel:  other.label,
 			}
+			if _, ok := placed[plcm]; ok {
+				continue
+			}
+
+			placed[plcm] = struct{}{}
+			ctx.Placements = append(ctx.Placements, plcm)
+		}
+	}
+}
+
+// checkDependencies returns an error if the dependencies between labels contain a
+// cycle, as none of the containers in the cycle would ever start.
+func checkDependencies(labels []Label) error {
+	deps := map[string][]string{}
+	for _, label := range labels {
+		for _, dep := range label.Dependencies {
+			deps[label.Name] = append(deps[label.Name], dep.Label)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+
+	var path []string
+	var visit func(label string) error
+	visit = func(label string) error {
+		switch state[label] {
+		case visited:
+			return nil
+		case visiting:
+			for i, l := range path {
+				if l == label {
+					cycle := append(path[i:], label)
+					return fmt.Errorf("dependency cycle: %s",
+						strings.Join(cycle, " -> "))
+				}
+			}
+		}
+
+		state[label] = visiting
+		path = append(path, label)
+		for _, dep := range deps[label] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[label] = visited
+		return nil
+	}
+
+	for _, label := range labels {
+		if err := visit(label.Name); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // QueryLabels retrieves all labels declared in the Stitch.
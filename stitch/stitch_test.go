@@ -24,8 +24,14 @@ func TestMachine(t *testing.T) {
 		size: "m4.large",
 		cpu: new Range(2, 4),
 		ram: new Range(4, 8),
+		gpu: new Range(1),
 		diskSize: 32,
-		sshKeys: ["key1", "key2"]
+		sshKeys: ["key1", "key2"],
+		localSSD: true,
+		minNetwork: 10,
+		families: ["m4", "c4"],
+		excludeFamilies: ["p2"],
+		preferPreviousGeneration: true
 	})])`,
 		[]Machine{
 			{
@@ -35,22 +41,33 @@ func TestMachine(t *testing.T) {
 				Size:     "m4.large",
 				CPU:      Range{2, 4},
 				RAM:      Range{4, 8},
+				GPU:      Range{1, 0},
 				DiskSize: 32,
 				SSHKeys:  []string{"key1", "key2"},
+
+				LocalSSD:                 true,
+				MinNetwork:               10,
+				Families:                 []string{"m4", "c4"},
+				ExcludeFamilies:          []string{"p2"},
+				PreferPreviousGeneration: true,
 			}})
 
 	checkMachines(t, `var baseMachine = new Machine({provider: "Amazon"});
 		deployment.deploy(baseMachine.asMaster().replicate(2));`,
 		[]Machine{
 			{
-				Role:     "Master",
-				Provider: "Amazon",
-				SSHKeys:  []string{},
+				Role:            "Master",
+				Provider:        "Amazon",
+				SSHKeys:         []string{},
+				Families:        []string{},
+				ExcludeFamilies: []string{},
 			},
 			{
-				Role:     "Master",
-				Provider: "Amazon",
-				SSHKeys:  []string{},
+				Role:            "Master",
+				Provider:        "Amazon",
+				SSHKeys:         []string{},
+				Families:        []string{},
+				ExcludeFamilies: []string{},
 			},
 		},
 	)
@@ -61,14 +78,18 @@ func TestMachine(t *testing.T) {
 		deployment.deploy(machines);`,
 		[]Machine{
 			{
-				Role:     "Master",
-				Provider: "Amazon",
-				SSHKeys:  []string{"key"},
+				Role:            "Master",
+				Provider:        "Amazon",
+				SSHKeys:         []string{"key"},
+				Families:        []string{},
+				ExcludeFamilies: []string{},
 			},
 			{
-				Role:     "Master",
-				Provider: "Amazon",
-				SSHKeys:  []string{},
+				Role:            "Master",
+				Provider:        "Amazon",
+				SSHKeys:         []string{},
+				Families:        []string{},
+				ExcludeFamilies: []string{},
 			},
 		},
 	)
@@ -223,9 +244,10 @@ func TestLabel(t *testing.T) {
 	);`,
 		map[string]Label{
 			"web_tier": {
-				Name:        "web_tier",
-				IDs:         []int{1},
-				Annotations: []string{},
+				Name:         "web_tier",
+				IDs:          []int{1},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
 			},
 		})
 
@@ -237,9 +259,10 @@ func TestLabel(t *testing.T) {
 	);`,
 		map[string]Label{
 			"web_tier": {
-				Name:        "web_tier",
-				IDs:         []int{1, 2},
-				Annotations: []string{},
+				Name:         "web_tier",
+				IDs:          []int{1, 2},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
 			},
 		})
 
@@ -248,17 +271,41 @@ func TestLabel(t *testing.T) {
 	deployment.deploy(new Service("foo", []));`,
 		map[string]Label{
 			"foo": {
-				Name:        "foo",
-				IDs:         []int{},
-				Annotations: []string{},
+				Name:         "foo",
+				IDs:          []int{},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
 			},
 			"foo2": {
-				Name:        "foo2",
-				IDs:         []int{},
-				Annotations: []string{},
+				Name:         "foo2",
+				IDs:          []int{},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
 			},
 		})
 
+	checkLabels(t, `var foo = new Service("foo", []);
+	foo.setUpdateStrategy({maxSurge: 2, pause: 30});
+	deployment.deploy(foo);`,
+		map[string]Label{
+			"foo": {
+				Name:         "foo",
+				IDs:          []int{},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
+				UpdateStrategy: &UpdateStrategy{
+					MaxSurge: 2,
+					Pause:    30,
+				},
+			},
+		})
+
+	checkError(t, `new Service("foo", []).setUpdateStrategy({pause: 30});`,
+		"update strategy must allow either maxUnavailable or maxSurge")
+	checkError(t, `new Service("foo", []).setUpdateStrategy(
+		{maxUnavailable: -1});`,
+		"update strategy parameters must be non-negative")
+
 	expHostname := "foo.q"
 	checkJavascript(t, `(function() {
 		var foo = new Service("foo", []);
@@ -330,10 +377,148 @@ func TestConnect(t *testing.T) {
 			},
 		})
 
-	checkError(t, pre+`foo.connect(new PortRange(80, 81), publicInternet);`,
-		"public internet cannot connect on port ranges")
-	checkError(t, pre+`publicInternet.connect(new PortRange(80, 81), foo);`,
-		"public internet cannot connect on port ranges")
+	checkConnections(t, pre+`foo.connect(new PortRange(80, 81), publicInternet);`,
+		[]Connection{
+			{
+				From:    "foo",
+				To:      "public",
+				MinPort: 80,
+				MaxPort: 81,
+			},
+		})
+
+	checkConnections(t, pre+`publicInternet.connect(new PortRange(80, 81), foo);`,
+		[]Connection{
+			{
+				From:    "public",
+				To:      "foo",
+				MinPort: 80,
+				MaxPort: 81,
+			},
+		})
+
+	checkConnections(t, pre+`publicInternet.fromCIDR("1.2.3.0/24")
+		.connect(8080, foo);`,
+		[]Connection{
+			{
+				From:    "public",
+				To:      "foo",
+				MinPort: 8080,
+				MaxPort: 8080,
+				CidrIP:  "1.2.3.0/24",
+			},
+		})
+
+	checkConnections(t, pre+`foo.connect(new PortRange(80, 81),
+		publicInternet.fromCIDR("8.8.8.8/32"));`,
+		[]Connection{
+			{
+				From:    "foo",
+				To:      "public",
+				MinPort: 80,
+				MaxPort: 81,
+				CidrIP:  "8.8.8.8/32",
+			},
+		})
+
+	checkError(t, pre+`publicInternet.fromCIDR("1.2.3.0");`,
+		"invalid CIDR: 1.2.3.0")
+	checkError(t, pre+`publicInternet.fromCIDR("1.2.3.256/24");`,
+		"invalid CIDR: 1.2.3.256/24")
+}
+
+func TestPublicPortRules(t *testing.T) {
+	t.Parallel()
+
+	pre := `var foo = new Service("foo", []);
+	var bar = new Service("bar", []);
+	deployment.deploy([foo, bar]);`
+
+	checkPlacements(t, pre+`publicInternet.connect(new PortRange(80, 90), foo);
+	publicInternet.connect(85, bar);`,
+		[]Placement{
+			{TargetLabel: "foo", OtherLabel: "foo", Exclusive: true},
+			{TargetLabel: "foo", OtherLabel: "bar", Exclusive: true},
+			{TargetLabel: "bar", OtherLabel: "foo", Exclusive: true},
+			{TargetLabel: "bar", OtherLabel: "bar", Exclusive: true},
+		})
+
+	checkPlacements(t, pre+`publicInternet.connect(new PortRange(80, 90), foo);
+	publicInternet.connect(91, bar);`,
+		[]Placement{
+			{TargetLabel: "foo", OtherLabel: "foo", Exclusive: true},
+			{TargetLabel: "bar", OtherLabel: "bar", Exclusive: true},
+		})
+}
+
+func TestDependsOn(t *testing.T) {
+	t.Parallel()
+
+	checkLabels(t, `var mongo = new Service("mongo", []);
+	var app = new Service("app", []);
+	app.dependsOn(mongo, {untilHealthy: true});
+	deployment.deploy([mongo, app]);`,
+		map[string]Label{
+			"mongo": {
+				Name:         "mongo",
+				IDs:          []int{},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
+			},
+			"app": {
+				Name:        "app",
+				IDs:         []int{},
+				Annotations: []string{},
+				Dependencies: []Dependency{
+					{Label: "mongo", UntilHealthy: true},
+				},
+			},
+		})
+
+	checkError(t, `var a = new Service("a", []);
+	a.dependsOn(new Service("b", []));
+	deployment.deploy(a);`, "a depends on undeployed service: b")
+
+	checkError(t, `var a = new Service("a", []);
+	var b = new Service("b", []);
+	var c = new Service("c", []);
+	a.dependsOn(b);
+	b.dependsOn(c);
+	c.dependsOn(a);
+	deployment.deploy([a, b, c]);`, "dependency cycle: a -> b -> c -> a")
+
+	checkError(t, `var a = new Service("a", []);
+	a.dependsOn(a);
+	deployment.deploy(a);`, "dependency cycle: a -> a")
+}
+
+func TestJob(t *testing.T) {
+	t.Parallel()
+
+	checkLabels(t, `var job = new Job("job", new Container("image"),
+		{completions: 3, parallelism: 2, backoff: 5});
+	deployment.deploy(job);`,
+		map[string]Label{
+			"job": {
+				Name:         "job",
+				IDs:          []int{2, 3, 4},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
+				Job: &Job{
+					Completions:  3,
+					Parallelism:  2,
+					BackoffLimit: 6,
+					Backoff:      5,
+				},
+			},
+		})
+
+	checkError(t, `new Job("job", new Container("image"), {completions: 0});`,
+		"job completions must be positive")
+	checkError(t, `new Job("job", new Container("image"), {parallelism: 0});`,
+		"job parallelism must be positive")
+	checkError(t, `new Job("job", new Container("image"), {backoff: -1});`,
+		"job backoff parameters must be non-negative")
 }
 
 func TestVet(t *testing.T) {
@@ -366,14 +551,16 @@ func TestCustomDeploy(t *testing.T) {
 	);`,
 		map[string]Label{
 			"web_tier": {
-				Name:        "web_tier",
-				IDs:         []int{1},
-				Annotations: []string{},
+				Name:         "web_tier",
+				IDs:          []int{1},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
 			},
 			"web_tier2": {
-				Name:        "web_tier2",
-				IDs:         []int{2},
-				Annotations: []string{},
+				Name:         "web_tier2",
+				IDs:          []int{2},
+				Annotations:  []string{},
+				Dependencies: []Dependency{},
 			},
 		})
 